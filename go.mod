@@ -14,6 +14,5 @@ require (
 	github.com/spf13/viper v1.7.1
 	github.com/stretchr/testify v1.6.1
 	github.com/vektah/gqlparser/v2 v2.1.0
-	github.com/wowucco/go-novaposhta v0.0.0-20201217164729-2a25521177fc
 	github.com/wowucco/nestedset v0.0.0-20201109230149-8d862ee4db27
 )
@@ -2,8 +2,11 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/wowucco/G3/internal/checkout"
+	"github.com/wowucco/G3/internal/entity"
+	"github.com/wowucco/G3/pkg/pagination"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -48,6 +51,44 @@ func (h *Handler) create(c *gin.Context) {
 
 	order, err := h.orderManage.Create(c, form)
 
+	var (
+		priceErr   *checkout.PriceMismatchError
+		stockErr   *checkout.StockError
+		paymentErr *checkout.PaymentMethodError
+		promoErr   *checkout.PromoError
+		slotErr    *checkout.DeliverySlotError
+	)
+
+	if errors.As(err, &priceErr) {
+		log.Printf("[Checkout create request][price mismatch][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"price_mismatch": priceErr})
+		return
+	}
+
+	if errors.As(err, &stockErr) {
+		log.Printf("[Checkout create request][stock][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"stock": stockErr})
+		return
+	}
+
+	if errors.As(err, &paymentErr) {
+		log.Printf("[Checkout create request][payment method][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"payment_method": paymentErr})
+		return
+	}
+
+	if errors.As(err, &promoErr) {
+		log.Printf("[Checkout create request][promo][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"promo": promoErr})
+		return
+	}
+
+	if errors.As(err, &slotErr) {
+		log.Printf("[Checkout create request][delivery slot][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"delivery_slot": slotErr})
+		return
+	}
+
 	if err != nil {
 		log.Printf("[Checkout create request][create][%v]", err)
 		c.JSON(http.StatusBadRequest, err)
@@ -136,6 +177,137 @@ func (h *Handler) acceptHolden(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{})
 }
 
+func (h *Handler) cancelHolden(c *gin.Context) {
+	b, err := ioutil.ReadAll(c.Request.Body)
+
+	if err != nil {
+		log.Printf("[error][cancel holden payment request][read body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	var form CancelHoldenPaymentForm
+
+	if err := json.Unmarshal(b, &form); err != nil {
+		log.Printf("[error][cancel holden payment request][decode body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+
+	if err != nil {
+		log.Printf("[error][cancel holden payment request][validate][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = h.orderManage.CancelHoldenPayment(c, form)
+
+	if err != nil {
+		log.Printf("[error][cancel holden payment request]%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+func (h *Handler) confirmManualPayment(c *gin.Context) {
+	b, err := ioutil.ReadAll(c.Request.Body)
+
+	if err != nil {
+		log.Printf("[error][confirm manual payment request][read body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	var form ConfirmManualPaymentForm
+
+	if err := json.Unmarshal(b, &form); err != nil {
+		log.Printf("[error][confirm manual payment request][decode body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+
+	if err != nil {
+		log.Printf("[error][confirm manual payment request][validate][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = h.orderManage.ConfirmManualPayment(c, form)
+
+	if err != nil {
+		log.Printf("[error][confirm manual payment request]%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+func (h *Handler) refund(c *gin.Context) {
+	b, err := ioutil.ReadAll(c.Request.Body)
+
+	if err != nil {
+		log.Printf("[error][refund request][read body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	var form RefundPaymentForm
+
+	if err := json.Unmarshal(b, &form); err != nil {
+		log.Printf("[error][refund request][decode body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+
+	if err != nil {
+		log.Printf("[error][refund request][validate][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	refund, err := h.orderManage.Refund(c, form)
+
+	if err != nil && refund != nil {
+		log.Printf("[error][refund request]%v", err)
+		c.JSON(http.StatusFailedDependency, NewRefundResponse(refund))
+		return
+	}
+
+	if err != nil {
+		log.Printf("[error][refund request]%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewRefundResponse(refund))
+}
+
+func (h *Handler) invoice(c *gin.Context) {
+
+	form := InvoiceForm{
+		TransactionId: c.Param("transaction_id"),
+	}
+
+	invoice, err := h.orderManage.Invoice(c, form)
+
+	if err != nil {
+		log.Printf("[error][invoice request]%v", err)
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(invoice.GetDocument()))
+}
+
 func (h *Handler) orderInfo(c *gin.Context) {
 	b, err := ioutil.ReadAll(c.Request.Body)
 
@@ -172,6 +344,286 @@ func (h *Handler) orderInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, NewOrderInfoResponse(order))
 }
 
+func (h *Handler) editOrder(c *gin.Context) {
+	b, err := ioutil.ReadAll(c.Request.Body)
+
+	if err != nil {
+		log.Printf("[error][edit order request][read body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	var form EditOrderForm
+
+	if err := json.Unmarshal(b, &form); err != nil {
+		log.Printf("[error][edit order request][decode body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+
+	if err != nil {
+		log.Printf("[error][edit order request][validate][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	order, err := h.orderManage.EditOrder(c, form)
+
+	var (
+		editErr  *checkout.OrderNotEditableError
+		stockErr *checkout.StockError
+		promoErr *checkout.PromoError
+	)
+
+	if errors.As(err, &editErr) {
+		log.Printf("[error][edit order request][not editable][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"order": editErr})
+		return
+	}
+
+	if errors.As(err, &stockErr) {
+		log.Printf("[error][edit order request][stock][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"stock": stockErr})
+		return
+	}
+
+	if errors.As(err, &promoErr) {
+		log.Printf("[error][edit order request][promo][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"promo": promoErr})
+		return
+	}
+
+	if err != nil {
+		log.Printf("[error][edit order request]%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewOrderInfoResponse(order))
+}
+
+func (h *Handler) orderChanges(c *gin.Context) {
+	b, err := ioutil.ReadAll(c.Request.Body)
+
+	if err != nil {
+		log.Printf("[error][order changes request][read body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	var form OrderIdForm
+
+	if err := json.Unmarshal(b, &form); err != nil {
+		log.Printf("[error][order changes request][decode body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+
+	if err != nil {
+		log.Printf("[error][order changes request][validate][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	changes, err := h.orderManage.OrderChanges(c, form)
+
+	if err != nil {
+		log.Printf("[error][order changes request]%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{})
+		return
+	}
+
+	resp := make([]*OrderChangeResponse, len(changes))
+
+	for k, v := range changes {
+		resp[k] = NewOrderChangeResponse(v)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) updateDeliveryStatus(c *gin.Context) {
+	b, err := ioutil.ReadAll(c.Request.Body)
+
+	if err != nil {
+		log.Printf("[error][update delivery status request][read body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	var form UpdateDeliveryStatusForm
+
+	if err := json.Unmarshal(b, &form); err != nil {
+		log.Printf("[error][update delivery status request][decode body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+
+	if err != nil {
+		log.Printf("[error][update delivery status request][validate][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	order, err := h.orderManage.UpdateDeliveryStatus(c, form)
+
+	var transitionErr *entity.DeliveryStatusTransitionError
+
+	if errors.As(err, &transitionErr) {
+		log.Printf("[error][update delivery status request][transition][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"status": transitionErr.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Printf("[error][update delivery status request]%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewOrderInfoResponse(order))
+}
+
+func (h *Handler) createWaybill(c *gin.Context) {
+	b, err := ioutil.ReadAll(c.Request.Body)
+
+	if err != nil {
+		log.Printf("[error][create waybill request][read body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	var form CreateWaybillForm
+
+	if err := json.Unmarshal(b, &form); err != nil {
+		log.Printf("[error][create waybill request][decode body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+
+	if err != nil {
+		log.Printf("[error][create waybill request][validate][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	waybill, err := h.orderManage.CreateWaybill(c, form)
+
+	if err != nil {
+		log.Printf("[error][create waybill request]%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewWaybillResponse(waybill))
+}
+
+func (h *Handler) orders(c *gin.Context) {
+	b, err := ioutil.ReadAll(c.Request.Body)
+
+	if err != nil {
+		log.Printf("[error][orders request][read body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	var form OrdersForm
+
+	if err := json.Unmarshal(b, &form); err != nil {
+		log.Printf("[error][orders request][decode body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+
+	if err != nil {
+		log.Printf("[error][orders request][validate][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	count, err := h.orderManage.OrdersCount(c, form)
+
+	if err != nil {
+		log.Printf("[error][orders request][count]%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{})
+		return
+	}
+
+	pages := pagination.New(form.Page, form.PerPage, count)
+
+	orders, err := h.orderManage.Orders(c, form, pages.Offset(), pages.Limit())
+
+	if err != nil {
+		log.Printf("[error][orders request][find]%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{})
+		return
+	}
+
+	items := make([]*OrderInfoResponse, len(orders))
+
+	for k, v := range orders {
+		items[k] = NewOrderInfoResponse(v)
+	}
+
+	pages.Items = items
+
+	c.JSON(http.StatusOK, gin.H{
+		"orders": pages,
+	})
+}
+
+func (h *Handler) installments(c *gin.Context) {
+	b, err := ioutil.ReadAll(c.Request.Body)
+
+	if err != nil {
+		log.Printf("[error][installments request][read body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	var form InstallmentsForm
+
+	if err := json.Unmarshal(b, &form); err != nil {
+		log.Printf("[error][installments request][decode body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+
+	if err != nil {
+		log.Printf("[error][installments request][validate][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	installments, err := h.orderManage.Installments(c, form)
+
+	if err != nil {
+		log.Printf("[error][installments request]%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{})
+		return
+	}
+
+	resp := make([]*InstallmentResponse, len(installments))
+
+	for k, v := range installments {
+		resp[k] = NewInstallmentResponse(v)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 func (h *Handler) callback(c *gin.Context) {
 
 	provider := c.Param("provider")
@@ -189,3 +641,87 @@ func (h *Handler) callback(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{})
 }
+
+func (h *Handler) paymentEvents(c *gin.Context) {
+	b, err := ioutil.ReadAll(c.Request.Body)
+
+	if err != nil {
+		log.Printf("[error][payment events request][read body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	var form PaymentEventsForm
+
+	if err := json.Unmarshal(b, &form); err != nil {
+		log.Printf("[error][payment events request][decode body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+
+	if err != nil {
+		log.Printf("[error][payment events request][validate][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	events, err := h.orderManage.PaymentEvents(c, form)
+
+	if err != nil {
+		log.Printf("[error][payment events request]%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{})
+		return
+	}
+
+	resp := make([]*PaymentEventResponse, len(events))
+
+	for k, v := range events {
+		resp[k] = NewPaymentEventResponse(v)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) replayPaymentEvent(c *gin.Context) {
+	b, err := ioutil.ReadAll(c.Request.Body)
+
+	if err != nil {
+		log.Printf("[error][replay payment event request][read body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	var form ReplayPaymentEventForm
+
+	if err := json.Unmarshal(b, &form); err != nil {
+		log.Printf("[error][replay payment event request][decode body][%v]", err)
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+
+	if err != nil {
+		log.Printf("[error][replay payment event request][validate][%v]", err)
+		c.JSON(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	event, err := h.orderManage.ReplayPaymentEvent(c, form)
+
+	if err != nil && event != nil {
+		log.Printf("[error][replay payment event request]%v", err)
+		c.JSON(http.StatusFailedDependency, NewPaymentEventResponse(event))
+		return
+	}
+
+	if err != nil {
+		log.Printf("[error][replay payment event request]%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPaymentEventResponse(event))
+}
@@ -14,7 +14,25 @@ func RegisterHTTPEndpoints(router *gin.RouterGroup, ordUC checkout.IOrderUseCase
 		c.POST("create", h.create)
 		c.POST("init-payment", h.initPayment)
 		c.POST("accept-holden-payment", h.acceptHolden)
+		c.POST("cancel-holden-payment", h.cancelHolden)
+		c.POST("refund", h.refund)
+		c.POST("confirm-manual-payment", h.confirmManualPayment)
 		c.POST("order-info", h.orderInfo)
+		c.POST("orders", h.orders)
+		c.POST("edit-order", h.editOrder)
+		c.POST("order-changes", h.orderChanges)
+		c.POST("update-delivery-status", h.updateDeliveryStatus)
+		c.POST("create-waybill", h.createWaybill)
+		c.POST("installments", h.installments)
+		c.POST("payment-events", h.paymentEvents)
+		c.POST("replay-payment-event", h.replayPaymentEvent)
+	}
+
+	// invoice document has customer and company details, storefront fetches it for the customer
+	i := router.Group("/invoice")
+	i.Use(platformAuth)
+	{
+		i.GET(":transaction_id", h.invoice)
 	}
 
 	cb := router.Group("/callback")
@@ -5,6 +5,7 @@ import (
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/wowucco/G3/internal/checkout"
 	"github.com/wowucco/G3/internal/entity"
+	"time"
 )
 
 type Client struct {
@@ -74,11 +75,59 @@ func (a Address) Validate() error {
 	return validation.ValidateStruct(&a, validation.Field(&a.Name, validation.Required))
 }
 
+type CourierAddress struct {
+	StreetCode string `json:"street_code"`
+	Street     string `json:"street"`
+	House      string `json:"house"`
+	Apartment  string `json:"apartment"`
+}
+
+func (a CourierAddress) GetStreetCode() string {
+	return a.StreetCode
+}
+func (a CourierAddress) GetStreet() string {
+	return a.Street
+}
+func (a CourierAddress) GetHouse() string {
+	return a.House
+}
+func (a CourierAddress) GetApartment() string {
+	return a.Apartment
+}
+func (a CourierAddress) Validate() error {
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.Street, validation.Required, validation.Length(1, 255)),
+		validation.Field(&a.House, validation.Required, validation.Length(1, 32)),
+		validation.Field(&a.Apartment, validation.Length(0, 32)),
+	)
+}
+
+type DeliverySlot struct {
+	Date string `json:"date"`
+	Id   string `json:"id"`
+}
+
+func (s DeliverySlot) GetDate() string {
+	return s.Date
+}
+func (s DeliverySlot) GetId() string {
+	return s.Id
+}
+func (s DeliverySlot) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.Date, validation.Required, validation.Date(entity.DeliverySlotDateLayout)),
+		validation.Field(&s.Id, validation.Required),
+	)
+}
+
 type Delivery struct {
 	Method        string  `json:"method"`
 	City          City    `json:"city"`
 	CustomAddress bool    `json:"is_custom_address"`
 	Address       Address `json:"address"`
+	// Courier is required for courier delivery, Slot is optional
+	Courier *CourierAddress `json:"courier"`
+	Slot    *DeliverySlot   `json:"slot"`
 }
 
 func (d Delivery) GetMethod() string {
@@ -101,12 +150,35 @@ func (d Delivery) GetAddress() checkout.DeliveryAddressForm {
 	return d.Address
 }
 
+func (d Delivery) GetCourier() checkout.DeliveryCourierForm {
+
+	if d.Method != entity.DeliveryMethodCourier || d.Courier == nil {
+		return nil
+	}
+
+	return d.Courier
+}
+
+func (d Delivery) GetSlot() checkout.DeliverySlotForm {
+
+	if d.Method != entity.DeliveryMethodCourier || d.Slot == nil {
+		return nil
+	}
+
+	return d.Slot
+}
+
 func (d Delivery) Validate() error {
 
+	courier := d.Method == entity.DeliveryMethodCourier
+
 	return validation.ValidateStruct(&d,
 		validation.Field(&d.Method, validation.Required, validation.In(entity.DeliveryMethodYourself, entity.DeliveryMethodNovaposhta, entity.DeliveryMethodCourier)),
 		validation.Field(&d.City),
-		validation.Field(&d.Address),
+		// courier address replaces the free text one
+		validation.Field(&d.Address, validation.Skip.When(courier)),
+		validation.Field(&d.Courier, validation.When(courier, validation.Required)),
+		validation.Field(&d.Slot, validation.Skip.When(courier == false)),
 	)
 }
 
@@ -116,6 +188,7 @@ type Payment struct {
 	PayInEmail   string `json:"pay_in_email"`
 	PayInCompany string `json:"pay_in_company"`
 	PayPartsPay  int    `json:"pay_parts_pay"`
+	Currency     string `json:"currency"`
 }
 
 func (p Payment) GetMethod() string {
@@ -138,15 +211,20 @@ func (p Payment) GetPayPartsPay() int {
 
 	return p.PayPartsPay
 }
+func (p Payment) GetCurrency() string {
+
+	return p.Currency
+}
 
 func (p Payment) Validate() error {
 
 	return validation.ValidateStruct(&p,
-		validation.Field(&p.Method, validation.Required, validation.In(entity.PaymentMethodCash, entity.PaymentMethodP2P, entity.PaymentMethodPayin, entity.PaymentMethodCashOnDelivery, entity.PaymentMethodToCard, entity.PaymentMethodPartsPay)),
+		validation.Field(&p.Method, validation.Required, validation.In(entity.PaymentMethodCash, entity.PaymentMethodP2P, entity.PaymentMethodPayin, entity.PaymentMethodCashOnDelivery, entity.PaymentMethodToCard, entity.PaymentMethodPartsPay, entity.PaymentMethodMonobank)),
 		validation.Field(&p.PayInCompany, validation.When(p.Method == entity.PaymentMethodPayin, validation.Required)),
 		validation.Field(&p.PayInEdrpou, validation.When(p.Method == entity.PaymentMethodPayin, validation.Required)),
 		validation.Field(&p.PayInEmail, validation.When(p.Method == entity.PaymentMethodPayin, validation.Required, is.Email)),
-		validation.Field(&p.PayPartsPay, validation.When(p.Method == entity.PaymentMethodPartsPay, validation.Required)),
+		validation.Field(&p.PayPartsPay, validation.When(p.Method == entity.PaymentMethodPartsPay, validation.Required, validation.Min(1))),
+		validation.Field(&p.Currency, is.CurrencyCode),
 	)
 }
 
@@ -178,14 +256,19 @@ func (o OrderItem) Validate() error {
 }
 
 type Order struct {
-	Cost  int         `json:"cost"`
-	Items []OrderItem `json:"items"`
+	Cost      int         `json:"cost"`
+	Items     []OrderItem `json:"items"`
+	PromoCode string      `json:"promo_code"`
 }
 
 func (o Order) GetCost() int {
 
 	return o.Cost
 }
+func (o Order) GetPromoCode() string {
+
+	return o.PromoCode
+}
 func (o Order) GetOrderItems() []checkout.OrderItemForm {
 
 	i := make([]checkout.OrderItemForm, len(o.Items))
@@ -200,6 +283,7 @@ func (o Order) Validate() error {
 	err := validation.ValidateStruct(&o,
 		validation.Field(&o.Cost, validation.Required, validation.Min(1)),
 		validation.Field(&o.Items, validation.Required),
+		validation.Field(&o.PromoCode, validation.Length(0, 64)),
 	)
 
 	if err != nil {
@@ -295,6 +379,31 @@ func NewOrderInfoResponse(order *entity.Order) *OrderInfoResponse {
 		}
 	}
 
+	delivery := DeliveryInfoResponse{
+		Method:    order.GetDelivery().GetMethod().GetName(),
+		Slug:      order.GetDelivery().GetMethod().GetSlug(),
+		Status:    order.GetDelivery().GetStatus(),
+		Tracking:  order.GetDelivery().GetTrackingNumber(),
+		City:      order.GetDelivery().GetWarehouse().GetCity().GetName(),
+		CityId:    order.GetDelivery().GetWarehouse().GetCity().GetId(),
+		Address:   order.GetDelivery().GetWarehouse().GetAddress().GetName(),
+		AddressId: order.GetDelivery().GetWarehouse().GetAddress().GetId(),
+		IsCustom:  order.GetDelivery().GetWarehouse().GetAddress().IsCustom(),
+	}
+
+	if courier := order.GetDelivery().GetCourier(); courier != nil {
+		delivery.Courier = &CourierAddress{
+			StreetCode: courier.GetStreetId(),
+			Street:     courier.GetStreet(),
+			House:      courier.GetHouse(),
+			Apartment:  courier.GetApartment(),
+		}
+	}
+
+	if slot := order.GetDelivery().GetSlot(); slot != nil {
+		delivery.Slot = &DeliverySlotResponse{Date: slot.GetDate(), Id: slot.GetId(), From: slot.GetFrom(), To: slot.GetTo()}
+	}
+
 	return &OrderInfoResponse{
 		OrderId:   order.GetId(),
 		Created:   order.GetCreated(),
@@ -309,16 +418,7 @@ func NewOrderInfoResponse(order *entity.Order) *OrderInfoResponse {
 			Fio:   order.GetCustomer().GetName(),
 			Phone: order.GetCustomer().GetPhone(),
 		},
-		Delivery: DeliveryInfoResponse{
-			Method:    order.GetDelivery().GetMethod().GetName(),
-			Slug:      order.GetDelivery().GetMethod().GetSlug(),
-			Status:    order.GetDelivery().GetStatus(),
-			City:      order.GetDelivery().GetWarehouse().GetCity().GetName(),
-			CityId:    order.GetDelivery().GetWarehouse().GetCity().GetId(),
-			Address:   order.GetDelivery().GetWarehouse().GetAddress().GetName(),
-			AddressId: order.GetDelivery().GetWarehouse().GetAddress().GetId(),
-			IsCustom:  order.GetDelivery().GetWarehouse().GetAddress().IsCustom(),
-		},
+		Delivery: delivery,
 		Payment: PaymentInfoResponse{
 			Method: order.GetPayment().GetMethod().GetName(),
 			Slug:   order.GetPayment().GetMethod().GetSlug(),
@@ -332,11 +432,21 @@ type DeliveryInfoResponse struct {
 	Method    string `json:"method"`
 	Slug      string `json:"slug"`
 	Status    int    `json:"status"`
+	Tracking  string `json:"tracking_number,omitempty"`
 	City      string `json:"city"`
 	CityId    string `json:"city_id"`
 	Address   string `json:"address"`
 	AddressId string `json:"address_id"`
 	IsCustom  bool   `json:"is_custom"`
+
+	Courier *CourierAddress       `json:"courier,omitempty"`
+	Slot    *DeliverySlotResponse `json:"slot,omitempty"`
+}
+type DeliverySlotResponse struct {
+	Date string `json:"date"`
+	Id   string `json:"id"`
+	From string `json:"from"`
+	To   string `json:"to"`
 }
 type PaymentInfoResponse struct {
 	Method string `json:"method"`
@@ -400,6 +510,278 @@ func (f OrderIdForm) Validate() error {
 	return validation.ValidateStruct(&f, validation.Field(&f.OrderId, validation.Required, validation.Min(1)))
 }
 
+type EditDelivery struct {
+	City          City    `json:"city"`
+	CustomAddress bool    `json:"is_custom_address"`
+	Address       Address `json:"address"`
+}
+
+func (d EditDelivery) GetCity() checkout.DeliveryCityForm {
+	return d.City
+}
+func (d EditDelivery) IsCustomAddress() bool {
+	return d.CustomAddress
+}
+func (d EditDelivery) GetAddress() checkout.DeliveryAddressForm {
+	return d.Address
+}
+func (d EditDelivery) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.City),
+		validation.Field(&d.Address),
+	)
+}
+
+type EditOrderItem struct {
+	ProductId int `json:"product_id"`
+	Count     int `json:"count"`
+}
+
+func (i EditOrderItem) GetProductId() int {
+	return i.ProductId
+}
+func (i EditOrderItem) GetCount() int {
+	return i.Count
+}
+func (i EditOrderItem) Validate() error {
+	return validation.ValidateStruct(&i,
+		validation.Field(&i.ProductId, validation.Required, validation.Min(1)),
+		validation.Field(&i.Count, validation.Required, validation.Min(1)),
+	)
+}
+
+type EditOrderForm struct {
+	OrderId  int             `json:"order_id"`
+	Operator string          `json:"operator"`
+	Comment  string          `json:"comment"`
+	Client   *Client         `json:"client"`
+	Delivery *EditDelivery   `json:"delivery"`
+	Items    []EditOrderItem `json:"items"`
+}
+
+func (f EditOrderForm) GetOrderId() int {
+	return f.OrderId
+}
+func (f EditOrderForm) GetOperator() string {
+	return f.Operator
+}
+func (f EditOrderForm) GetComment() string {
+	return f.Comment
+}
+func (f EditOrderForm) GetClient() checkout.ClientForm {
+	if f.Client == nil {
+		return nil
+	}
+
+	return *f.Client
+}
+func (f EditOrderForm) GetDelivery() checkout.EditDeliveryForm {
+	if f.Delivery == nil {
+		return nil
+	}
+
+	return *f.Delivery
+}
+func (f EditOrderForm) GetItems() []checkout.EditOrderItemForm {
+	if f.Items == nil {
+		return nil
+	}
+
+	i := make([]checkout.EditOrderItemForm, len(f.Items))
+	for k, v := range f.Items {
+		i[k] = v
+	}
+
+	return i
+}
+func (f EditOrderForm) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.OrderId, validation.Required, validation.Min(1)),
+		validation.Field(&f.Operator, validation.Required, validation.Length(1, 64)),
+		validation.Field(&f.Comment, validation.Length(0, 255)),
+		validation.Field(&f.Client),
+		validation.Field(&f.Delivery),
+		validation.Field(&f.Items, validation.When(f.Items != nil, validation.Required)),
+	)
+}
+
+func NewOrderChangeResponse(c *entity.OrderChange) *OrderChangeResponse {
+
+	return &OrderChangeResponse{
+		Field:    c.GetField(),
+		Before:   c.GetBefore(),
+		After:    c.GetAfter(),
+		Operator: c.GetOperator(),
+		Comment:  c.GetComment(),
+		Created:  c.GetCreated(),
+	}
+}
+
+type OrderChangeResponse struct {
+	Field    string `json:"field"`
+	Before   string `json:"before"`
+	After    string `json:"after"`
+	Operator string `json:"operator"`
+	Comment  string `json:"comment"`
+	Created  int64  `json:"created"`
+}
+
+type UpdateDeliveryStatusForm struct {
+	OrderId        int    `json:"order_id"`
+	Status         int    `json:"status"`
+	Comment        string `json:"comment"`
+	TrackingNumber string `json:"tracking_number"`
+}
+
+func (f UpdateDeliveryStatusForm) GetOrderId() int {
+	return f.OrderId
+}
+func (f UpdateDeliveryStatusForm) GetStatus() int {
+	return f.Status
+}
+func (f UpdateDeliveryStatusForm) GetComment() string {
+	return f.Comment
+}
+func (f UpdateDeliveryStatusForm) GetTrackingNumber() string {
+	return f.TrackingNumber
+}
+func (f UpdateDeliveryStatusForm) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.OrderId, validation.Required, validation.Min(1)),
+		validation.Field(&f.Status, validation.Required, validation.In(
+			entity.DeliveryStatusNew,
+			entity.DeliveryStatusCheck,
+			entity.DeliveryStatusWaitingDelivery,
+			entity.DeliveryStatusDelivery,
+			entity.DeliveryStatusReadyToReceive,
+			entity.DeliveryStatusCanceled,
+		)),
+		validation.Field(&f.Comment, validation.Length(0, 255)),
+		validation.Field(&f.TrackingNumber, validation.Length(0, 36)),
+	)
+}
+
+type CreateWaybillForm struct {
+	OrderId       int     `json:"order_id"`
+	Weight        float64 `json:"weight"`
+	Seats         int     `json:"seats"`
+	DeclaredValue int     `json:"declared_value"`
+	Description   string  `json:"description"`
+}
+
+func (f CreateWaybillForm) GetOrderId() int {
+	return f.OrderId
+}
+func (f CreateWaybillForm) GetWeight() float64 {
+	return f.Weight
+}
+func (f CreateWaybillForm) GetSeats() int {
+	return f.Seats
+}
+func (f CreateWaybillForm) GetDeclaredValue() int {
+	return f.DeclaredValue
+}
+func (f CreateWaybillForm) GetDescription() string {
+	return f.Description
+}
+func (f CreateWaybillForm) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.OrderId, validation.Required, validation.Min(1)),
+		validation.Field(&f.Weight, validation.Required, validation.Min(0.1), validation.Max(1000.0)),
+		validation.Field(&f.Seats, validation.Min(0), validation.Max(100)),
+		validation.Field(&f.DeclaredValue, validation.Min(0)),
+		validation.Field(&f.Description, validation.Length(0, 100)),
+	)
+}
+
+func NewWaybillResponse(w *entity.Waybill) *WaybillResponse {
+
+	cost := w.GetCost()
+
+	return &WaybillResponse{
+		Ref:               w.GetRef(),
+		TrackingNumber:    w.GetNumber(),
+		EstimatedDelivery: w.GetEstimatedDelivery(),
+		Cost: PriceInfoResponse{
+			InCent:     cost.GetInCent(),
+			InCurrency: cost.CentToCurrency(),
+			Currency:   cost.GetCurrency().GetName(),
+		},
+	}
+}
+
+type WaybillResponse struct {
+	Ref               string            `json:"ref"`
+	TrackingNumber    string            `json:"tracking_number"`
+	EstimatedDelivery string            `json:"estimated_delivery"`
+	Cost              PriceInfoResponse `json:"cost"`
+}
+
+const ordersDateLayout = "2006-01-02"
+
+type OrdersForm struct {
+	Phone          string `json:"phone"`
+	PaymentStatus  int    `json:"payment_status"`
+	DeliveryStatus int    `json:"delivery_status"`
+	PaymentMethod  string `json:"payment_method"`
+	DateFrom       string `json:"date_from"`
+	DateTo         string `json:"date_to"`
+	Page           int    `json:"page"`
+	PerPage        int    `json:"per_page"`
+}
+
+func (f OrdersForm) GetPhone() string {
+	return f.Phone
+}
+func (f OrdersForm) GetPaymentStatus() int {
+	return f.PaymentStatus
+}
+func (f OrdersForm) GetDeliveryStatus() int {
+	return f.DeliveryStatus
+}
+func (f OrdersForm) GetPaymentMethod() string {
+	return f.PaymentMethod
+}
+func (f OrdersForm) GetDateFrom() int64 {
+	if f.DateFrom == "" {
+		return 0
+	}
+
+	t, _ := time.ParseInLocation(ordersDateLayout, f.DateFrom, time.Local)
+
+	return t.Unix()
+}
+func (f OrdersForm) GetDateTo() int64 {
+	if f.DateTo == "" {
+		return 0
+	}
+
+	t, _ := time.ParseInLocation(ordersDateLayout, f.DateTo, time.Local)
+
+	// the whole last day is included
+	return t.AddDate(0, 0, 1).Unix() - 1
+}
+func (f OrdersForm) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.Phone, validation.Length(0, 20)),
+		validation.Field(&f.PaymentStatus, validation.Min(0), validation.Max(entity.PaymentStatusCanceled)),
+		validation.Field(&f.DeliveryStatus, validation.Min(0), validation.Max(entity.DeliveryStatusCanceled)),
+		validation.Field(&f.PaymentMethod, validation.In(
+			entity.PaymentMethodCash,
+			entity.PaymentMethodP2P,
+			entity.PaymentMethodPayin,
+			entity.PaymentMethodCashOnDelivery,
+			entity.PaymentMethodToCard,
+			entity.PaymentMethodPartsPay,
+			entity.PaymentMethodMonobank,
+		)),
+		validation.Field(&f.DateFrom, validation.Date(ordersDateLayout)),
+		validation.Field(&f.DateTo, validation.Date(ordersDateLayout)),
+		validation.Field(&f.Page, validation.Min(0)),
+		validation.Field(&f.PerPage, validation.Min(0)),
+	)
+}
+
 type AccentPaymentForm struct {
 	TransactionId string `json:"transaction_id"`
 }
@@ -411,6 +793,152 @@ func (f AccentPaymentForm) Validate() error {
 	return validation.ValidateStruct(&f, validation.Field(&f.TransactionId, validation.Required))
 }
 
+type CancelHoldenPaymentForm struct {
+	TransactionId string `json:"transaction_id"`
+	Comment       string `json:"comment"`
+}
+
+func (f CancelHoldenPaymentForm) GetTransactionId() string {
+	return f.TransactionId
+}
+func (f CancelHoldenPaymentForm) GetComment() string {
+	return f.Comment
+}
+func (f CancelHoldenPaymentForm) Validate() error {
+	return validation.ValidateStruct(&f, validation.Field(&f.TransactionId, validation.Required))
+}
+
+type ConfirmManualPaymentForm struct {
+	OrderId int    `json:"order_id"`
+	Amount  int    `json:"amount"`
+	Comment string `json:"comment"`
+}
+
+func (f ConfirmManualPaymentForm) GetOrderId() int {
+	return f.OrderId
+}
+func (f ConfirmManualPaymentForm) GetAmount() int {
+	return f.Amount
+}
+func (f ConfirmManualPaymentForm) GetComment() string {
+	return f.Comment
+}
+func (f ConfirmManualPaymentForm) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.OrderId, validation.Required),
+		validation.Field(&f.Amount, validation.Required, validation.Min(1)),
+	)
+}
+
+type RefundPaymentForm struct {
+	TransactionId string `json:"transaction_id"`
+	Amount        int    `json:"amount"`
+	Comment       string `json:"comment"`
+}
+
+func (f RefundPaymentForm) GetTransactionId() string {
+	return f.TransactionId
+}
+func (f RefundPaymentForm) GetAmount() int {
+	return f.Amount
+}
+func (f RefundPaymentForm) GetComment() string {
+	return f.Comment
+}
+func (f RefundPaymentForm) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.TransactionId, validation.Required),
+		validation.Field(&f.Amount, validation.Min(0)),
+	)
+}
+
+func NewRefundResponse(refund *entity.Refund) *RefundResponse {
+
+	return &RefundResponse{
+		TransactionId: refund.GetTransactionId(),
+		OrderId:       refund.GetOrderId(),
+		Status:        refund.GetStatus(),
+		Amount: PriceInfoResponse{
+			InCent:     refund.GetPrice().GetInCent(),
+			InCurrency: refund.GetPrice().CentToCurrency(),
+			Currency:   refund.GetPrice().GetCurrency().GetName(),
+		},
+	}
+}
+
+type RefundResponse struct {
+	TransactionId string            `json:"transaction_id"`
+	OrderId       int               `json:"order_id"`
+	Status        int               `json:"status"`
+	Amount        PriceInfoResponse `json:"amount"`
+}
+
+type InstallmentsForm struct {
+	ProductId int    `json:"product_id"`
+	Amount    int    `json:"amount"`
+	Type      string `json:"type"`
+}
+
+func (f InstallmentsForm) GetProductId() int {
+	return f.ProductId
+}
+func (f InstallmentsForm) GetAmount() int {
+	return f.Amount
+}
+func (f InstallmentsForm) GetType() string {
+	return f.Type
+}
+func (f InstallmentsForm) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.ProductId, validation.Required.When(f.Amount == 0), validation.Min(0)),
+		validation.Field(&f.Amount, validation.Required.When(f.ProductId == 0), validation.Min(0)),
+		validation.Field(&f.Type, validation.In(entity.InstallmentTypeParts, entity.InstallmentTypeInstant)),
+	)
+}
+
+func NewInstallmentResponse(i *entity.Installment) *InstallmentResponse {
+
+	payment := i.GetPayment()
+	commission := i.GetCommission()
+	total := i.GetTotal()
+
+	return &InstallmentResponse{
+		Type:  i.GetType(),
+		Parts: i.GetParts(),
+		Payment: PriceInfoResponse{
+			InCent:     payment.GetInCent(),
+			InCurrency: payment.CentToCurrency(),
+			Currency:   payment.GetCurrency().GetName(),
+		},
+		Commission: PriceInfoResponse{
+			InCent:     commission.GetInCent(),
+			InCurrency: commission.CentToCurrency(),
+			Currency:   commission.GetCurrency().GetName(),
+		},
+		Total: PriceInfoResponse{
+			InCent:     total.GetInCent(),
+			InCurrency: total.CentToCurrency(),
+			Currency:   total.GetCurrency().GetName(),
+		},
+	}
+}
+
+type InstallmentResponse struct {
+	Type       string            `json:"type"`
+	Parts      int               `json:"parts"`
+	Payment    PriceInfoResponse `json:"payment"`
+	Commission PriceInfoResponse `json:"commission"`
+	Total      PriceInfoResponse `json:"total"`
+}
+
+type InvoiceForm struct {
+	TransactionId string
+}
+
+func (f InvoiceForm) GetTransactionId() string {
+	return f.TransactionId
+}
+
 type ProviderCallbackPaymentForm struct {
 	Provider string
 }
@@ -418,3 +946,60 @@ type ProviderCallbackPaymentForm struct {
 func (f ProviderCallbackPaymentForm) GetProvider() string {
 	return f.Provider
 }
+
+type PaymentEventsForm struct {
+	TransactionId string `json:"transaction_id"`
+	Limit         int    `json:"limit"`
+}
+
+func (f PaymentEventsForm) GetTransactionId() string {
+	return f.TransactionId
+}
+func (f PaymentEventsForm) GetLimit() int {
+	return f.Limit
+}
+func (f PaymentEventsForm) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.Limit, validation.Min(0), validation.Max(500)),
+	)
+}
+
+type ReplayPaymentEventForm struct {
+	EventId int `json:"event_id"`
+}
+
+func (f ReplayPaymentEventForm) GetEventId() int {
+	return f.EventId
+}
+func (f ReplayPaymentEventForm) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.EventId, validation.Required),
+	)
+}
+
+func NewPaymentEventResponse(event *entity.PaymentEvent) *PaymentEventResponse {
+
+	return &PaymentEventResponse{
+		Id:             event.GetId(),
+		Provider:       event.GetProvider(),
+		TransactionId:  event.GetTransactionId(),
+		ValidSignature: event.IsValidSignature(),
+		Outcome:        event.GetOutcome(),
+		Message:        event.GetMessage(),
+		Body:           event.GetBody(),
+		Created:        event.GetCreatedTime().Format(time.RFC3339),
+		Updated:        event.GetUpdatedTime().Format(time.RFC3339),
+	}
+}
+
+type PaymentEventResponse struct {
+	Id             int    `json:"id"`
+	Provider       string `json:"provider"`
+	TransactionId  string `json:"transaction_id"`
+	ValidSignature bool   `json:"valid_signature"`
+	Outcome        string `json:"outcome"`
+	Message        string `json:"message"`
+	Body           string `json:"body"`
+	Created        string `json:"created_at"`
+	Updated        string `json:"updated_at"`
+}
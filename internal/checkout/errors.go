@@ -0,0 +1,100 @@
+package checkout
+
+import "fmt"
+
+type ItemPriceMismatch struct {
+	ProductId int `json:"product_id"`
+	Given     int `json:"given"`
+	Expected  int `json:"expected"`
+}
+
+// PriceMismatchError is returned when prices sent by the client differ from the calculated ones
+type PriceMismatchError struct {
+	Items        []ItemPriceMismatch `json:"items"`
+	GivenCost    int                 `json:"given_cost"`
+	ExpectedCost int                 `json:"expected_cost"`
+}
+
+func (e *PriceMismatchError) Error() string {
+	return fmt.Sprintf("[price mismatch][cost given: %d, expected: %d][items: %v]", e.GivenCost, e.ExpectedCost, e.Items)
+}
+
+func (e *PriceMismatchError) HasMismatch() bool {
+	return len(e.Items) > 0 || e.GivenCost != e.ExpectedCost
+}
+
+const StockReasonNotFound = "not_found"
+const StockReasonDisabled = "disabled"
+const StockReasonOutOfStock = "out_of_stock"
+
+type ItemStockError struct {
+	ProductId int    `json:"product_id"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+}
+
+// StockError lists every order item that can't be sold
+type StockError struct {
+	Items []ItemStockError `json:"items"`
+}
+
+func (e *StockError) Error() string {
+	return fmt.Sprintf("[stock][unavailable items: %v]", e.Items)
+}
+
+func (e *StockError) Add(item ItemStockError) {
+	e.Items = append(e.Items, item)
+}
+
+func (e *StockError) HasItems() bool {
+	return len(e.Items) > 0
+}
+
+// PaymentMethodError is returned when the order doesn't match rules of the chosen payment method
+type PaymentMethodError struct {
+	Method string `json:"method"`
+	Reason string `json:"reason"`
+}
+
+func (e *PaymentMethodError) Error() string {
+	return fmt.Sprintf("[payment method %s isn't eligible][%s]", e.Method, e.Reason)
+}
+
+// PromoError is returned when the promo code can't be applied to the order
+type PromoError struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+func (e *PromoError) Error() string {
+	return fmt.Sprintf("[promo code %s isn't applicable][%s]", e.Code, e.Reason)
+}
+
+// OrderNotEditableError is returned when the order is paid, the payment is being confirmed
+// or the amount is already sent to the payment provider
+type OrderNotEditableError struct {
+	OrderId       int    `json:"order_id"`
+	PaymentStatus int    `json:"payment_status"`
+	Provider      string `json:"provider,omitempty"`
+}
+
+func (e *OrderNotEditableError) Error() string {
+	return fmt.Sprintf("[order %d can't be edited with payment status %d]", e.OrderId, e.PaymentStatus)
+}
+
+const DeliverySlotReasonUnknown = "unknown"
+const DeliverySlotReasonPast = "past"
+const DeliverySlotReasonFull = "full"
+
+// DeliverySlotError is returned when the courier time slot can't be booked for the order
+type DeliverySlotError struct {
+	Date   string `json:"date"`
+	Slot   string `json:"slot"`
+	Reason string `json:"reason"`
+}
+
+func (e *DeliverySlotError) Error() string {
+	return fmt.Sprintf("[delivery slot %s %s isn't available][%s]", e.Date, e.Slot, e.Reason)
+}
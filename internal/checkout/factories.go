@@ -15,4 +15,28 @@ type CreateOrderBuilder struct {
 	Comment        string
 	DoNotCall      bool
 	Cost           int
+	// Currency is the one order is paid in, Cost is in it while item prices stay in the base currency
+	Currency *entity.Currency
+	// Discount is the applied promo code, Cost is already reduced by it
+	Discount *entity.OrderDiscount
+	// Courier and Slot are set for courier delivery only, slot is booked by the caller
+	Courier *entity.OrderDeliveryCourier
+	Slot    *entity.OrderDeliverySlot
+}
+
+// OrderFilter narrows orders list, zero values are not filtered by
+type OrderFilter struct {
+	// Phone is compared by digits with the end of customer phone
+	Phone          string
+	PaymentStatus  int
+	DeliveryStatus int
+	// DeliveryStatuses lists allowed delivery statuses in addition to DeliveryStatus
+	DeliveryStatuses []int
+	// Tracked limits orders to ones with waybill tracking number
+	Tracked        bool
+	PaymentMethod  string
+	DeliveryMethod string
+	// From and To limit order creation time in unix seconds, both inclusive
+	From int64
+	To   int64
 }
@@ -15,11 +15,28 @@ type DeliveryAddressForm interface {
 	GetName() string
 }
 
+// DeliveryCourierForm is the structured courier address, street code is empty for street typed by hand
+type DeliveryCourierForm interface {
+	GetStreetCode() string
+	GetStreet() string
+	GetHouse() string
+	GetApartment() string
+}
+
+// DeliverySlotForm is the courier time slot, date is in entity.DeliverySlotDateLayout
+type DeliverySlotForm interface {
+	GetDate() string
+	GetId() string
+}
+
 type DeliveryForm interface {
 	GetMethod() string
 	GetCity() DeliveryCityForm
 	IsCustomAddress() bool
 	GetAddress() DeliveryAddressForm
+	// GetCourier and GetSlot are nil unless courier delivery is chosen
+	GetCourier() DeliveryCourierForm
+	GetSlot() DeliverySlotForm
 }
 
 type PaymentForm interface {
@@ -28,6 +45,8 @@ type PaymentForm interface {
 	GetPayInEmail() string
 	GetPayInCompany() string
 	GetPayPartsPay() int
+	// GetCurrency returns ISO code of the currency order is paid in, empty means the base currency
+	GetCurrency() string
 }
 
 type OrderItemForm interface {
@@ -37,8 +56,10 @@ type OrderItemForm interface {
 }
 
 type OrderForm interface {
+	// GetCost returns cost of items before the promo code discount
 	GetCost() int
 	GetOrderItems() []OrderItemForm
+	GetPromoCode() string
 }
 
 type CreateOrderForm interface {
@@ -59,10 +80,99 @@ type OrderIdForm interface {
 	GetOrderId() int
 }
 
+type EditDeliveryForm interface {
+	GetCity() DeliveryCityForm
+	IsCustomAddress() bool
+	GetAddress() DeliveryAddressForm
+}
+
+type EditOrderItemForm interface {
+	GetProductId() int
+	GetCount() int
+}
+
+// IEditOrderForm changes the order by operator, nil parts are left as is, items replace the whole list
+type IEditOrderForm interface {
+	GetOrderId() int
+	GetOperator() string
+	GetComment() string
+	GetClient() ClientForm
+	GetDelivery() EditDeliveryForm
+	GetItems() []EditOrderItemForm
+}
+
+// IUpdateDeliveryStatusForm moves the order delivery to status, empty tracking number keeps the current one
+type IUpdateDeliveryStatusForm interface {
+	GetOrderId() int
+	GetStatus() int
+	GetComment() string
+	GetTrackingNumber() string
+}
+
+// ICreateWaybillForm describes the parcel, weight is in kilograms, declared value in cents.
+// Zero declared value means the order cost, empty description means the item names.
+type ICreateWaybillForm interface {
+	GetOrderId() int
+	GetWeight() float64
+	GetSeats() int
+	GetDeclaredValue() int
+	GetDescription() string
+}
+
+// IOrdersFilterForm narrows orders list, empty values mean no filter, dates are unix seconds
+type IOrdersFilterForm interface {
+	GetPhone() string
+	GetPaymentStatus() int
+	GetDeliveryStatus() int
+	GetPaymentMethod() string
+	GetDateFrom() int64
+	GetDateTo() int64
+}
+
+// IInstallmentsForm takes either product or cart total in cents, empty type means every installment type
+type IInstallmentsForm interface {
+	GetProductId() int
+	GetAmount() int
+	GetType() string
+}
+
+type IInvoiceForm interface {
+	GetTransactionId() string
+}
+
 type IAcceptHoldenPaymentForm interface {
 	GetTransactionId() string
 }
 
+type ICancelHoldenPaymentForm interface {
+	GetTransactionId() string
+	GetComment() string
+}
+
+type IConfirmManualPaymentForm interface {
+	GetOrderId() int
+	// GetAmount returns received amount in cents
+	GetAmount() int
+	GetComment() string
+}
+
 type IProviderCallbackPaymentForm interface {
 	GetProvider() string
 }
+
+type IRefundPaymentForm interface {
+	GetTransactionId() string
+	// GetAmount returns refunded amount in cents, zero means refund of the whole remaining amount
+	GetAmount() int
+	GetComment() string
+}
+
+type IPaymentEventsForm interface {
+	// GetTransactionId returns payment transaction, empty value means events of all payments
+	GetTransactionId() string
+	GetLimit() int
+}
+
+type IReplayPaymentEventForm interface {
+	GetEventId() int
+}
@@ -0,0 +1,146 @@
+package promo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/wowucco/G3/internal/checkout"
+	"github.com/wowucco/G3/internal/entity"
+	"strings"
+	"time"
+)
+
+func NewPromoService(r checkout.IPromoRepository) *Service {
+
+	return &Service{repository: r}
+}
+
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Service applies promo codes to order items and counts their usage
+type Service struct {
+	repository checkout.IPromoRepository
+}
+
+// Apply sets discount of every item in scope of the code and returns the applied discount.
+// It doesn't count the usage, Redeem should be called within the order creation transaction.
+func (s *Service) Apply(ctx context.Context, code string, cost int, items []*entity.OrderProduct, lines []entity.PromoLine) (*entity.OrderDiscount, error) {
+
+	code = NormalizeCode(code)
+
+	p, err := s.repository.GetByCode(ctx, code)
+
+	if err == sql.ErrNoRows {
+		return nil, &checkout.PromoError{Code: code, Reason: entity.PromoReasonNotFound}
+	}
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[apply promo code][%s][%v]", code, err))
+	}
+
+	if reason := p.Check(cost, time.Now()); reason != "" {
+		return nil, &checkout.PromoError{Code: code, Reason: reason}
+	}
+
+	return apply(p, items, lines)
+}
+
+// Reapply recalculates the discount of the code redeemed by the order for its changed items.
+// Usage and expiry were checked when the order was created, the cart still has to reach the minimum value.
+func (s *Service) Reapply(ctx context.Context, discount *entity.OrderDiscount, cost int, items []*entity.OrderProduct, lines []entity.PromoLine) (*entity.OrderDiscount, error) {
+
+	p, err := s.repository.GetByCode(ctx, discount.GetCode())
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[reapply promo code][%s][%v]", discount.GetCode(), err))
+	}
+
+	if reason := p.CheckCartValue(cost); reason != "" {
+		return nil, &checkout.PromoError{Code: p.GetCode(), Reason: reason}
+	}
+
+	return apply(p, items, lines)
+}
+
+// apply sets discount of every item, items out of the code scope get no discount
+func apply(p *entity.PromoCode, items []*entity.OrderProduct, lines []entity.PromoLine) (*entity.OrderDiscount, error) {
+
+	discounts := p.Calculate(lines)
+
+	var amount int
+
+	for _, v := range items {
+		d := discounts[v.GetProduct().ID]
+		v.SetDiscount(d)
+		amount += d
+	}
+
+	if amount == 0 {
+		return nil, &checkout.PromoError{Code: p.GetCode(), Reason: entity.PromoReasonNotApplicable}
+	}
+
+	return entity.NewOrderDiscount(p.GetCode(), amount), nil
+}
+
+// Redeem locks the code and counts one more usage. Should run inside a transaction.
+func (s *Service) Redeem(ctx context.Context, discount *entity.OrderDiscount) error {
+
+	p, err := s.repository.GetForUpdate(ctx, discount.GetCode())
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[redeem promo code][%s][%v]", discount.GetCode(), err))
+	}
+
+	// usage could be exhausted by concurrent orders after Apply
+	if reason := p.Availability(time.Now()); reason != "" {
+		return &checkout.PromoError{Code: discount.GetCode(), Reason: reason}
+	}
+
+	p.Use()
+
+	return s.repository.Save(ctx, p)
+}
+
+// Settle gives back the usage of the closed order, like stock reservation is released.
+// The order keeps the flag, so it should be saved after. Should run inside a transaction.
+func (s *Service) Settle(ctx context.Context, order *entity.Order) error {
+
+	discount := order.GetDiscount()
+
+	if discount == nil || discount.IsReleased() == true || order.IsClosed() == false {
+		return nil
+	}
+
+	p, err := s.repository.GetForUpdate(ctx, discount.GetCode())
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[release promo code][%s][%v]", discount.GetCode(), err))
+	}
+
+	p.Release()
+	discount.SetReleased(true)
+
+	return s.repository.Save(ctx, p)
+}
+
+// Restore counts the released usage again when the order is going to be paid, the code could be exhausted
+// meanwhile. Returns false when nothing was changed. Should run inside a transaction.
+func (s *Service) Restore(ctx context.Context, order *entity.Order) (bool, error) {
+
+	discount := order.GetDiscount()
+
+	if discount == nil || discount.IsReleased() == false {
+		return false, nil
+	}
+
+	if err := s.Redeem(ctx, discount); err != nil {
+		return false, err
+	}
+
+	discount.SetReleased(false)
+
+	return true, nil
+}
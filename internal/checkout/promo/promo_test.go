@@ -0,0 +1,103 @@
+package promo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wowucco/G3/internal/checkout"
+	"github.com/wowucco/G3/internal/entity"
+)
+
+type promoRepositoryStub struct {
+	code  *entity.PromoCode
+	saved int
+}
+
+func (r *promoRepositoryStub) GetByCode(ctx context.Context, code string) (*entity.PromoCode, error) {
+	return r.code, nil
+}
+
+func (r *promoRepositoryStub) GetForUpdate(ctx context.Context, code string) (*entity.PromoCode, error) {
+	return r.code, nil
+}
+
+func (r *promoRepositoryStub) Save(ctx context.Context, p *entity.PromoCode) error {
+	r.saved++
+	return nil
+}
+
+func newPromoOrder(paymentStatus int, discount *entity.OrderDiscount) *entity.Order {
+
+	order := entity.NewOrder(
+		1, 0, "", false, 9000, nil,
+		entity.NewOrderCustomer("customer", "380501234567"),
+		entity.NewOrderDelivery(entity.DeliveryStatusNew, entity.NewDeliveryMethod(1, "novaposhta", entity.DeliveryMethodNovaposhta), nil, nil),
+		entity.NewOrderPayment(paymentStatus, entity.NewPaymentMethod(1, "p2p", entity.PaymentMethodP2P), nil, "", "", "", 0),
+		nil,
+	)
+	order.SetDiscount(discount)
+
+	return order
+}
+
+func TestService_Settle(t *testing.T) {
+
+	tests := []struct {
+		tag      string
+		status   int
+		released bool
+		usage    int
+	}{
+		{"t1", entity.PaymentStatusPending, false, 3},
+		{"t2", entity.PaymentStatusDone, false, 3},
+		{"t3", entity.PaymentStatusFailed, true, 2},
+		{"t4", entity.PaymentStatusCanceled, true, 2},
+	}
+
+	for _, test := range tests {
+		r := &promoRepositoryStub{code: entity.NewPromoCode(1, "SALE", entity.PromoTypeFixed, 1000, entity.PromoScopeCart, nil, 0, 3, 3, 0, true)}
+		s := NewPromoService(r)
+		order := newPromoOrder(test.status, entity.NewOrderDiscount("SALE", 1000))
+
+		assert.NoError(t, s.Settle(context.Background(), order), test.tag)
+		assert.Equal(t, test.released, order.GetDiscount().IsReleased(), test.tag)
+		assert.Equal(t, test.usage, r.code.GetUsageCount(), test.tag)
+
+		// the released usage isn't given back twice
+		assert.NoError(t, s.Settle(context.Background(), order), test.tag)
+		assert.Equal(t, test.usage, r.code.GetUsageCount(), test.tag)
+	}
+
+	assert.NoError(t, NewPromoService(nil).Settle(context.Background(), newPromoOrder(entity.PaymentStatusFailed, nil)), "t5")
+}
+
+func TestService_Restore(t *testing.T) {
+	r := &promoRepositoryStub{code: entity.NewPromoCode(1, "SALE", entity.PromoTypeFixed, 1000, entity.PromoScopeCart, nil, 0, 3, 3, 0, true)}
+	s := NewPromoService(r)
+	order := newPromoOrder(entity.PaymentStatusFailed, entity.NewOrderDiscount("SALE", 1000))
+
+	restored, err := s.Restore(context.Background(), order)
+	assert.NoError(t, err, "t1")
+	assert.False(t, restored, "t2")
+
+	assert.NoError(t, s.Settle(context.Background(), order), "t3")
+
+	restored, err = s.Restore(context.Background(), order)
+	assert.NoError(t, err, "t4")
+	assert.True(t, restored, "t5")
+	assert.False(t, order.GetDiscount().IsReleased(), "t6")
+	assert.Equal(t, 3, r.code.GetUsageCount(), "t7")
+
+	// the usage was taken by another order while this one was released
+	assert.NoError(t, s.Settle(context.Background(), order), "t8")
+	r.code.Use()
+
+	var promoErr *checkout.PromoError
+
+	_, err = s.Restore(context.Background(), order)
+	assert.True(t, errors.As(err, &promoErr), "t9")
+	assert.Equal(t, entity.PromoReasonUsageLimit, promoErr.Reason, "t10")
+	assert.True(t, order.GetDiscount().IsReleased(), "t11")
+}
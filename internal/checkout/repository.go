@@ -3,18 +3,106 @@ package checkout
 import (
 	"context"
 	"github.com/wowucco/G3/internal/entity"
+	"time"
 )
 
 type IOrderRepository interface {
 	NextId() (int, error)
 	Get(ctx context.Context, orderId int) (*entity.Order, error)
+	// Find returns orders matching the filter, newest first
+	Find(ctx context.Context, filter OrderFilter, offset, limit int) ([]*entity.Order, error)
+	Count(ctx context.Context, filter OrderFilter) (int, error)
+	// FindLeastTracked returns orders matching the filter, never tracked and tracked longest ago first
+	FindLeastTracked(ctx context.Context, filter OrderFilter, limit int) ([]*entity.Order, error)
+	// SetTracked stores the time carrier was asked for statuses of the orders
+	SetTracked(ctx context.Context, orderIds []int, at time.Time) error
+	// GetForUpdate locks the order row till the end of transaction started by IUnitOfWork and returns the order
+	GetForUpdate(ctx context.Context, orderId int) (*entity.Order, error)
 	Save(ctx context.Context, order *entity.Order) error
+	// SaveItems replaces stored order items with the current ones
+	SaveItems(ctx context.Context, order *entity.Order) error
 	Create(ctx context.Context, builder *CreateOrderBuilder) (*entity.Order, error)
+	// GetCurrency returns the currency with its current rate by ISO code
+	GetCurrency(ctx context.Context, iso string) (*entity.Currency, error)
+}
+
+type IOrderChangeRepository interface {
+	Create(ctx context.Context, c *entity.OrderChange) error
+	// Find returns changes of the order, oldest first
+	Find(ctx context.Context, orderId int) ([]*entity.OrderChange, error)
+}
+
+type IDeliverySlotRepository interface {
+	// Book stores the slot of the order unless orders of the city booked capacity of it already,
+	// concurrent bookings of the same slot wait for each other till the end of transaction
+	Book(ctx context.Context, orderId int, cityId string, slot entity.OrderDeliverySlot, capacity int) error
+	// Release frees slots booked by the order
+	Release(ctx context.Context, orderId int) error
 }
 
 type IPaymentRepository interface {
 	NextId() (int, error)
 	Get(ctx context.Context, transactionId string) (*entity.Payment, error)
+	// GetForUpdate locks the payment row till the end of transaction started by IUnitOfWork and returns the payment
+	GetForUpdate(ctx context.Context, transactionId string) (*entity.Payment, error)
+	// GetLastByOrder returns the latest payment of the order or sql.ErrNoRows
+	GetLastByOrder(ctx context.Context, orderId int) (*entity.Payment, error)
 	Save(ctx context.Context, p *entity.Payment) error
 	Create(ctx context.Context, p *entity.Payment) error
+	// GetStale returns payments of providers with one of statuses created after createdAfter and not updated since updatedBefore
+	GetStale(ctx context.Context, providers []string, statuses []int, createdAfter, updatedBefore time.Time, limit int) ([]*entity.Payment, error)
+}
+
+// IUnitOfWork runs fn in one transaction shared by every repository called with the ctx passed to fn
+type IUnitOfWork interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type IStockRepository interface {
+	// GetAvailable locks product rows till the end of transaction and returns stock left after active reservations
+	GetAvailable(ctx context.Context, productIds []int) (map[int]int, error)
+	HasReservation(ctx context.Context, orderId int) (bool, error)
+	Reserve(ctx context.Context, orderId int, items []entity.OrderProduct) error
+	Release(ctx context.Context, orderId int) error
+	// Consume marks the active reservation of the paid order as sold, it doesn't hold the stock anymore
+	Consume(ctx context.Context, orderId int) error
+	// ReleaseExpired releases reservations created before the time by orders nobody has paid or processed,
+	// returns ids of the orders
+	ReleaseExpired(ctx context.Context, createdBefore time.Time) ([]int, error)
+}
+
+type IPromoRepository interface {
+	// GetByCode returns the code or sql.ErrNoRows, codes are stored in upper case
+	GetByCode(ctx context.Context, code string) (*entity.PromoCode, error)
+	GetForUpdate(ctx context.Context, code string) (*entity.PromoCode, error)
+	Save(ctx context.Context, p *entity.PromoCode) error
+}
+
+type IRefundRepository interface {
+	NextId() (int, error)
+	Create(ctx context.Context, r *entity.Refund) error
+	Save(ctx context.Context, r *entity.Refund) error
+	// GetRefundedAmount returns sum of done refunds of the payment in cents
+	GetRefundedAmount(ctx context.Context, paymentId int) (int, error)
+	// GetPendingAmount returns sum of refunds of the payment sent to provider and not finished yet in cents
+	GetPendingAmount(ctx context.Context, paymentId int) (int, error)
+}
+
+type IPaymentEventRepository interface {
+	NextId() (int, error)
+	Get(ctx context.Context, id int) (*entity.PaymentEvent, error)
+	// GetByHash returns the event with the same provider and body received before or sql.ErrNoRows
+	GetByHash(ctx context.Context, hash string) (*entity.PaymentEvent, error)
+	// Find returns latest events of the transaction, empty transactionId means events of all transactions
+	Find(ctx context.Context, transactionId string, limit int) ([]*entity.PaymentEvent, error)
+	// Create returns false without inserting when the event with the same hash exists
+	Create(ctx context.Context, e *entity.PaymentEvent) (bool, error)
+	Save(ctx context.Context, e *entity.PaymentEvent) error
+}
+
+type IInvoiceRepository interface {
+	NextNumber() (int, error)
+	// Get returns invoice of the payment by payment transaction id
+	Get(ctx context.Context, transactionId string) (*entity.Invoice, error)
+	Create(ctx context.Context, i *entity.Invoice) error
 }
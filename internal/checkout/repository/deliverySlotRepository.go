@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/wowucco/G3/internal/checkout"
+	"github.com/wowucco/G3/internal/entity"
+	"time"
+)
+
+func NewDeliverySlotRepository(db *dbx.DB) *DeliverySlotRepository {
+
+	return &DeliverySlotRepository{db: db}
+}
+
+type DeliverySlotRepository struct {
+	db *dbx.DB
+}
+
+// Book takes transaction advisory lock of the city slot, so capacity is checked and booked atomically.
+// Slots of orders with canceled delivery don't count.
+func (r DeliverySlotRepository) Book(ctx context.Context, orderId int, cityId string, slot entity.OrderDeliverySlot, capacity int) error {
+
+	db := conn(ctx, r.db)
+
+	key := fmt.Sprintf("%s|%s|%s", cityId, slot.GetDate(), slot.GetId())
+
+	if _, err := db.NewQuery("SELECT pg_advisory_xact_lock(hashtext({:key}))").Bind(dbx.Params{"key": key}).Execute(); err != nil {
+		return errors.New(fmt.Sprintf("[book delivery slot][order %d][lock][%v]", orderId, err))
+	}
+
+	var booked int
+
+	err := db.Select("COUNT(*)").
+		From(tableNameOrderDeliverySlots+" s").
+		InnerJoin(tableNameOrder+" o", dbx.NewExp("o.id = s.order_id")).
+		Where(dbx.HashExp{"s.city_token": cityId, "s.date": slot.GetDate(), "s.slot": slot.GetId()}).
+		AndWhere(dbx.NewExp("o.delivery_status <> {:canceled}", dbx.Params{"canceled": entity.DeliveryStatusCanceled})).
+		Row(&booked)
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[book delivery slot][order %d][count][%v]", orderId, err))
+	}
+
+	if booked >= capacity {
+		return &checkout.DeliverySlotError{Date: slot.GetDate(), Slot: slot.GetId(), Reason: checkout.DeliverySlotReasonFull}
+	}
+
+	_, err = db.Insert(tableNameOrderDeliverySlots, dbx.Params{
+		"order_id":   orderId,
+		"city_token": cityId,
+		"date":       slot.GetDate(),
+		"slot":       slot.GetId(),
+		"created_at": time.Now().Unix(),
+	}).Execute()
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[book delivery slot][order %d][%v]", orderId, err))
+	}
+
+	return nil
+}
+
+func (r DeliverySlotRepository) Release(ctx context.Context, orderId int) error {
+
+	_, err := conn(ctx, r.db).Delete(tableNameOrderDeliverySlots, dbx.HashExp{"order_id": orderId}).Execute()
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[release delivery slot][order %d][%v]", orderId, err))
+	}
+
+	return nil
+}
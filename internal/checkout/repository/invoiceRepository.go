@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/wowucco/G3/internal/entity"
+	"time"
+)
+
+func NewInvoiceRepository(db *dbx.DB) *InvoiceRepository {
+
+	return &InvoiceRepository{db: db}
+}
+
+type InvoiceRepository struct {
+	db *dbx.DB
+}
+
+func (r InvoiceRepository) NextNumber() (int, error) {
+
+	var seq NextId
+
+	err := r.db.NewQuery(fmt.Sprintf("SELECT nextval('%s') as id", tableInvoiceSeqNextValNumber)).One(&seq)
+
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("[get invoice next number][%v]", err))
+	}
+
+	return seq.Id, nil
+}
+
+func (r InvoiceRepository) Get(ctx context.Context, transactionId string) (*entity.Invoice, error) {
+
+	var row Invoice
+
+	err := conn(ctx, r.db).Select("*").
+		From(tableNameInvoices).
+		Where(dbx.HashExp{"transaction_id": transactionId}).
+		OrderBy("number desc").
+		Limit(1).
+		One(&row)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var created time.Time
+
+	if row.Created.Valid == true {
+		created, _ = time.Parse(time.RFC3339, row.Created.String)
+	} else {
+		created = time.Now()
+	}
+
+	return entity.NewInvoice(row.Number, row.TransactionID, row.PaymentId, row.OrderId, row.Document, created.Unix()), nil
+}
+
+func (r InvoiceRepository) Create(ctx context.Context, i *entity.Invoice) error {
+
+	_, err := conn(ctx, r.db).Insert(tableNameInvoices, dbx.Params{
+		"number":         i.GetNumber(),
+		"transaction_id": i.GetTransactionId(),
+		"payment_id":     i.GetPaymentId(),
+		"order_id":       i.GetOrderId(),
+		"document":       i.GetDocument(),
+		"created_at":     i.GetCreatedTime(),
+	}).Execute()
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[create invoice][%v]", err))
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/wowucco/G3/internal/entity"
+)
+
+func NewOrderChangeRepository(db *dbx.DB) *OrderChangeRepository {
+
+	return &OrderChangeRepository{db: db}
+}
+
+type OrderChangeRepository struct {
+	db *dbx.DB
+}
+
+func (r OrderChangeRepository) Create(ctx context.Context, c *entity.OrderChange) error {
+
+	_, err := conn(ctx, r.db).Insert(tableNameOrderChanges, dbx.Params{
+		"order_id":   c.GetOrderId(),
+		"field":      c.GetField(),
+		"before":     c.GetBefore(),
+		"after":      c.GetAfter(),
+		"operator":   c.GetOperator(),
+		"comment":    c.GetComment(),
+		"created_at": c.GetCreated(),
+	}).Execute()
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[create order change][order %d][%v]", c.GetOrderId(), err))
+	}
+
+	return nil
+}
+
+func (r OrderChangeRepository) Find(ctx context.Context, orderId int) ([]*entity.OrderChange, error) {
+
+	var rows []OrderChange
+
+	err := conn(ctx, r.db).Select("*").
+		From(tableNameOrderChanges).
+		Where(dbx.HashExp{"order_id": orderId}).
+		OrderBy("id").
+		All(&rows)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[find order changes][order %d][%v]", orderId, err))
+	}
+
+	changes := make([]*entity.OrderChange, len(rows))
+
+	for k, v := range rows {
+		changes[k] = entity.NewOrderChange(v.ID, v.OrderId, v.Field, v.Before, v.After, v.Operator, v.Comment.String, v.Created)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/wowucco/G3/internal/entity"
+	"time"
+)
+
+func NewPaymentEventRepository(db *dbx.DB) *PaymentEventRepository {
+
+	return &PaymentEventRepository{db: db}
+}
+
+type PaymentEventRepository struct {
+	db *dbx.DB
+}
+
+func (r PaymentEventRepository) NextId() (int, error) {
+
+	var seq NextId
+
+	err := r.db.NewQuery(fmt.Sprintf("SELECT nextval('%s') as id", tablePaymentEventSeqNextValID)).One(&seq)
+
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("[get payment event next sequence][%v]", err))
+	}
+
+	return seq.Id, nil
+}
+
+func (r PaymentEventRepository) Get(ctx context.Context, id int) (*entity.PaymentEvent, error) {
+
+	var row PaymentEvent
+
+	err := conn(ctx, r.db).Select("*").
+		From(tableNamePaymentEvents).
+		Where(dbx.HashExp{"id": id}).
+		One(&row)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return toPaymentEventEntity(row), nil
+}
+
+func (r PaymentEventRepository) GetByHash(ctx context.Context, hash string) (*entity.PaymentEvent, error) {
+
+	var row PaymentEvent
+
+	err := conn(ctx, r.db).Select("*").
+		From(tableNamePaymentEvents).
+		Where(dbx.HashExp{"hash": hash}).
+		One(&row)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return toPaymentEventEntity(row), nil
+}
+
+func (r PaymentEventRepository) Find(ctx context.Context, transactionId string, limit int) ([]*entity.PaymentEvent, error) {
+
+	var rows []PaymentEvent
+
+	q := conn(ctx, r.db).Select("*").
+		From(tableNamePaymentEvents)
+
+	if transactionId != "" {
+		q.Where(dbx.HashExp{"transaction_id": transactionId})
+	}
+
+	err := q.OrderBy("id desc").
+		Limit(int64(limit)).
+		All(&rows)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[find payment events][%v]", err))
+	}
+
+	events := make([]*entity.PaymentEvent, len(rows))
+
+	for k, v := range rows {
+		events[k] = toPaymentEventEntity(v)
+	}
+
+	return events, nil
+}
+
+// Create inserts the event unless the event with the same hash exists, hash column has unique index.
+// Returns false when the event was received before.
+func (r PaymentEventRepository) Create(ctx context.Context, e *entity.PaymentEvent) (bool, error) {
+
+	headers, err := json.Marshal(e.GetHeaders())
+
+	if err != nil {
+		return false, errors.New(fmt.Sprintf("[create payment event][encode headers][%v]", err))
+	}
+
+	db := conn(ctx, r.db)
+
+	q := db.Insert(tableNamePaymentEvents, dbx.Params{
+		"id":              e.GetId(),
+		"provider":        e.GetProvider(),
+		"transaction_id":  e.GetTransactionId(),
+		"content_type":    e.GetContentType(),
+		"headers":         string(headers),
+		"body":            e.GetBody(),
+		"hash":            e.GetHash(),
+		"valid_signature": e.IsValidSignature(),
+		"outcome":         e.GetOutcome(),
+		"message":         e.GetMessage(),
+		"created_at":      e.GetCreatedTime(),
+		"updated_at":      e.GetUpdatedTime(),
+	})
+
+	res, err := db.NewQuery(q.SQL() + " ON CONFLICT (hash) DO NOTHING").Bind(q.Params()).Execute()
+
+	if err != nil {
+		return false, errors.New(fmt.Sprintf("[create payment event][%v]", err))
+	}
+
+	inserted, err := res.RowsAffected()
+
+	if err != nil {
+		return false, errors.New(fmt.Sprintf("[create payment event][%v]", err))
+	}
+
+	return inserted > 0, nil
+}
+
+func (r PaymentEventRepository) Save(ctx context.Context, e *entity.PaymentEvent) error {
+
+	e.TouchUpdated()
+
+	_, err := conn(ctx, r.db).Update(tableNamePaymentEvents, dbx.Params{
+		"transaction_id":  e.GetTransactionId(),
+		"valid_signature": e.IsValidSignature(),
+		"outcome":         e.GetOutcome(),
+		"message":         e.GetMessage(),
+		"updated_at":      e.GetUpdatedTime(),
+	}, dbx.HashExp{"id": e.GetId()}).
+		Execute()
+
+	return err
+}
+
+func toPaymentEventEntity(row PaymentEvent) *entity.PaymentEvent {
+
+	var (
+		created, updated time.Time
+		headers          map[string]string
+	)
+
+	if row.Headers.Valid == true {
+		_ = json.Unmarshal([]byte(row.Headers.String), &headers)
+	}
+
+	if row.Created.Valid == true {
+		created, _ = time.Parse(time.RFC3339, row.Created.String)
+	} else {
+		created = time.Now()
+	}
+
+	if row.Updated.Valid == true {
+		updated, _ = time.Parse(time.RFC3339, row.Updated.String)
+	} else {
+		updated = time.Now()
+	}
+
+	return entity.NewPaymentEvent(
+		row.ID,
+		row.Provider,
+		row.TransactionID.String,
+		row.ContentType,
+		headers,
+		row.Body,
+		row.Hash,
+		row.ValidSignature,
+		row.Outcome,
+		row.Message.String,
+		created.Unix(),
+		updated.Unix(),
+	)
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/wowucco/G3/internal/entity"
+	"time"
+)
+
+func NewPromoRepository(db *dbx.DB) *PromoRepository {
+
+	return &PromoRepository{db: db}
+}
+
+type PromoRepository struct {
+	db *dbx.DB
+}
+
+func (r PromoRepository) GetByCode(ctx context.Context, code string) (*entity.PromoCode, error) {
+
+	var row PromoCode
+
+	err := conn(ctx, r.db).Select("*").
+		From(tableNamePromoCodes).
+		Where(dbx.HashExp{"code": code}).
+		One(&row)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return toPromoCodeEntity(row), nil
+}
+
+func (r PromoRepository) GetForUpdate(ctx context.Context, code string) (*entity.PromoCode, error) {
+
+	err := lockRow(ctx, r.db, tableNamePromoCodes, "code", code)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByCode(ctx, code)
+}
+
+func (r PromoRepository) Save(ctx context.Context, p *entity.PromoCode) error {
+
+	_, err := conn(ctx, r.db).Update(tableNamePromoCodes, dbx.Params{
+		"usage_count": p.GetUsageCount(),
+		"updated_at":  time.Now(),
+	}, dbx.HashExp{"id": p.GetId()}).
+		Execute()
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[save promo code][%s][%v]", p.GetCode(), err))
+	}
+
+	return nil
+}
+
+func toPromoCodeEntity(row PromoCode) *entity.PromoCode {
+
+	var (
+		scopeIds []int
+		expires  int64
+	)
+
+	if row.ScopeIds.Valid == true {
+		_ = json.Unmarshal([]byte(row.ScopeIds.String), &scopeIds)
+	}
+
+	if row.Expires.Valid == true {
+		t, _ := time.Parse(time.RFC3339, row.Expires.String)
+		expires = t.Unix()
+	}
+
+	return entity.NewPromoCode(
+		row.ID,
+		row.Code,
+		row.Type,
+		row.Value,
+		row.Scope,
+		scopeIds,
+		int(row.MinCartValue.Int64),
+		int(row.UsageLimit.Int64),
+		row.UsageCount,
+		expires,
+		row.Status,
+	)
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/wowucco/G3/internal/entity"
+)
+
+func NewRefundRepository(db *dbx.DB) *RefundRepository {
+
+	return &RefundRepository{db: db}
+}
+
+type RefundRepository struct {
+	db *dbx.DB
+}
+
+func (r RefundRepository) NextId() (int, error) {
+
+	var seq NextId
+
+	err := r.db.NewQuery(fmt.Sprintf("SELECT nextval('%s') as id", tableRefundSeqNextValID)).One(&seq)
+
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("[get refund next sequence][%v]", err))
+	}
+
+	return seq.Id, nil
+}
+
+func (r RefundRepository) Create(ctx context.Context, refund *entity.Refund) error {
+
+	_, err := conn(ctx, r.db).Insert(tableNameRefunds, dbx.Params{
+		"id":             refund.GetId(),
+		"payment_id":     refund.GetPaymentId(),
+		"order_id":       refund.GetOrderId(),
+		"transaction_id": refund.GetTransactionId(),
+		"amount":         refund.GetPrice().GetInCent(),
+		"status":         refund.GetStatus(),
+		"comment":        refund.GetComment(),
+		"created_at":     refund.GetCreatedTime(),
+		"updated_at":     refund.GetUpdatedTime(),
+	}).Execute()
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[create refund][%v]", err))
+	}
+
+	return nil
+}
+
+func (r RefundRepository) Save(ctx context.Context, refund *entity.Refund) error {
+
+	refund.TouchUpdated()
+
+	_, err := conn(ctx, r.db).Update(tableNameRefunds, dbx.Params{
+		"status":     refund.GetStatus(),
+		"comment":    refund.GetComment(),
+		"updated_at": refund.GetUpdatedTime(),
+	}, dbx.HashExp{"id": refund.GetId()}).
+		Execute()
+
+	return err
+}
+
+func (r RefundRepository) GetRefundedAmount(ctx context.Context, paymentId int) (int, error) {
+
+	return r.getAmount(ctx, paymentId, entity.RefundStatusDone)
+}
+
+func (r RefundRepository) GetPendingAmount(ctx context.Context, paymentId int) (int, error) {
+
+	return r.getAmount(ctx, paymentId, entity.RefundStatusNew)
+}
+
+func (r RefundRepository) getAmount(ctx context.Context, paymentId, status int) (int, error) {
+
+	var amount sql.NullInt64
+
+	err := conn(ctx, r.db).Select("SUM(amount)").
+		From(tableNameRefunds).
+		Where(dbx.HashExp{"payment_id": paymentId, "status": status}).
+		Row(&amount)
+
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("[refunds amount][payment %d][status %d][%v]", paymentId, status, err))
+	}
+
+	return int(amount.Int64), nil
+}
@@ -22,6 +22,16 @@ const tableNameOrderItems = "shop_order_items"
 const tableNamePayments = "payment"
 const tableNameDeliveryMethods = "shop_delivery_method"
 const tableNamePaymentMethods = "shop_payment_method"
+const tableNameStockReservation = "shop_stock_reservation"
+const tableNameRefunds = "payment_refund"
+const tableRefundSeqNextValID = "payment_refund_id_seq"
+const tableNamePaymentEvents = "payment_event"
+const tablePaymentEventSeqNextValID = "payment_event_id_seq"
+const tableNameInvoices = "payment_invoice"
+const tableInvoiceSeqNextValNumber = "payment_invoice_number_seq"
+const tableNamePromoCodes = "shop_promo_code"
+const tableNameOrderChanges = "shop_order_change"
+const tableNameOrderDeliverySlots = "shop_order_delivery_slot"
 
 func NewOrderRepository(db *dbx.DB) *OrderRepository {
 
@@ -32,25 +42,24 @@ type OrderRepository struct {
 	db *dbx.DB
 }
 
+func (r OrderRepository) GetForUpdate(ctx context.Context, orderId int) (*entity.Order, error) {
+
+	err := lockRow(ctx, r.db, tableNameOrder, "id", orderId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Get(ctx, orderId)
+}
+
 func (r OrderRepository) Get(ctx context.Context, orderId int) (*entity.Order, error) {
 
-	var (
-		deliveryInfo DeliveryInfo
-		row          Order
-		rowsOI       []OrderItem
-		ds           []DeliveryStatus
-		ps           []PaymentStatus
+	var row Order
 
-		order *entity.Order
-	)
+	db := conn(ctx, r.db)
 
-	err := r.db.Select(
-		"dm.id delivery_id", "dm.slug delivery_slug", "dm.name delivery_name",
-		"pm.id payment_id", "pm.slug payment_slug", "pm.name payment_name",
-		"o.*").
-		From(tableWithAlias(tableNameOrder, "o")).
-		InnerJoin(tableWithAlias(tableNameDeliveryMethods, "dm"), dbx.NewExp("dm.id = o.delivery_method_id")).
-		InnerJoin(tableWithAlias(tableNamePaymentMethods, "pm"), dbx.NewExp("pm.id = o.payment_method_id")).
+	err := selectOrders(db).
 		Where(dbx.NewExp("o.id={:id}", dbx.Params{"id": orderId})).
 		One(&row)
 
@@ -64,22 +73,225 @@ func (r OrderRepository) Get(ctx context.Context, orderId int) (*entity.Order, e
 		return nil, fmt.Errorf("order %d not found", orderId)
 	}
 
-	err = r.db.Select(
-		"p.id product_id", "p.name product_name", "p.code product_code", "p.exist product_exist", "p.status product_status",
+	items, err := orderItems(db, orderId)
+
+	if err != nil {
+		log.Printf("[Get order %v][items query error] err: %v", orderId, err)
+		return nil, err
+	}
+
+	return toOrderEntity(row, items[orderId])
+}
+
+// Find returns orders matching the filter, newest first
+func (r OrderRepository) Find(ctx context.Context, filter checkout.OrderFilter, offset, limit int) ([]*entity.Order, error) {
+
+	var rows []Order
+
+	db := conn(ctx, r.db)
+
+	err := selectOrders(db).
+		Where(orderFilterExp(filter)).
+		OrderBy("o.id DESC").
+		Offset(int64(offset)).
+		Limit(int64(limit)).
+		All(&rows)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[find orders][%v]", err))
+	}
+
+	return toOrderEntities(db, rows)
+}
+
+// FindLeastTracked orders by tracked_at, nullable unix seconds of the last carrier poll,
+// so every tracked order is polled in turn however many of them are in delivery
+func (r OrderRepository) FindLeastTracked(ctx context.Context, filter checkout.OrderFilter, limit int) ([]*entity.Order, error) {
+
+	var rows []Order
+
+	db := conn(ctx, r.db)
+
+	err := selectOrders(db).
+		Where(orderFilterExp(filter)).
+		OrderBy("COALESCE(o.tracked_at, 0) ASC", "o.id ASC").
+		Limit(int64(limit)).
+		All(&rows)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[find least tracked orders][%v]", err))
+	}
+
+	return toOrderEntities(db, rows)
+}
+
+func (r OrderRepository) SetTracked(ctx context.Context, orderIds []int, at time.Time) error {
+
+	ids := make([]interface{}, len(orderIds))
+
+	for k, v := range orderIds {
+		ids[k] = v
+	}
+
+	_, err := conn(ctx, r.db).Update(tableNameOrder, dbx.Params{"tracked_at": at.Unix()}, dbx.In("id", ids...)).Execute()
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[set orders tracked][%v]", err))
+	}
+
+	return nil
+}
+
+// toOrderEntities builds orders of the rows, items of all orders are loaded by one query
+func toOrderEntities(db dbx.Builder, rows []Order) ([]*entity.Order, error) {
+
+	ids := make([]int, len(rows))
+
+	for k, v := range rows {
+		ids[k] = v.Id
+	}
+
+	items, err := orderItems(db, ids...)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[order items][%v]", err))
+	}
+
+	orders := make([]*entity.Order, len(rows))
+
+	for k, v := range rows {
+		if orders[k], err = toOrderEntity(v, items[v.Id]); err != nil {
+			return nil, err
+		}
+	}
+
+	return orders, nil
+}
+
+func (r OrderRepository) Count(ctx context.Context, filter checkout.OrderFilter) (int, error) {
+
+	var count int
+
+	err := conn(ctx, r.db).Select("COUNT(*)").
+		From(tableWithAlias(tableNameOrder, "o")).
+		InnerJoin(tableWithAlias(tableNameDeliveryMethods, "dm"), dbx.NewExp("dm.id = o.delivery_method_id")).
+		InnerJoin(tableWithAlias(tableNamePaymentMethods, "pm"), dbx.NewExp("pm.id = o.payment_method_id")).
+		Where(orderFilterExp(filter)).
+		Row(&count)
+
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("[count orders][%v]", err))
+	}
+
+	return count, nil
+}
+
+func selectOrders(db dbx.Builder) *dbx.SelectQuery {
+
+	return db.Select(
+		"dm.id delivery_id", "dm.slug delivery_slug", "dm.name delivery_name",
+		"pm.id payment_id", "pm.slug payment_slug", "pm.name payment_name",
+		"o.*").
+		From(tableWithAlias(tableNameOrder, "o")).
+		InnerJoin(tableWithAlias(tableNameDeliveryMethods, "dm"), dbx.NewExp("dm.id = o.delivery_method_id")).
+		InnerJoin(tableWithAlias(tableNamePaymentMethods, "pm"), dbx.NewExp("pm.id = o.payment_method_id"))
+}
+
+func orderFilterExp(f checkout.OrderFilter) dbx.Expression {
+
+	var exps []dbx.Expression
+
+	if f.Phone != "" {
+		// phones are stored as customers typed them, so only digits are compared
+		exps = append(exps, dbx.NewExp("regexp_replace(o.customer_phone, '[^0-9]', '', 'g') LIKE {:phone}", dbx.Params{"phone": "%" + f.Phone}))
+	}
+	if f.PaymentStatus > 0 {
+		exps = append(exps, dbx.HashExp{"o.payment_status": f.PaymentStatus})
+	}
+	if f.DeliveryStatus > 0 {
+		exps = append(exps, dbx.HashExp{"o.delivery_status": f.DeliveryStatus})
+	}
+	if len(f.DeliveryStatuses) > 0 {
+		statuses := make([]interface{}, len(f.DeliveryStatuses))
+
+		for k, v := range f.DeliveryStatuses {
+			statuses[k] = v
+		}
+
+		exps = append(exps, dbx.In("o.delivery_status", statuses...))
+	}
+	if f.Tracked == true {
+		exps = append(exps, dbx.NewExp("COALESCE(o.tracking_number, '') <> ''"))
+	}
+	if f.PaymentMethod != "" {
+		exps = append(exps, dbx.HashExp{"pm.slug": f.PaymentMethod})
+	}
+	if f.DeliveryMethod != "" {
+		exps = append(exps, dbx.HashExp{"dm.slug": f.DeliveryMethod})
+	}
+	if f.From > 0 {
+		exps = append(exps, dbx.NewExp("o.created_at >= {:from}", dbx.Params{"from": f.From}))
+	}
+	if f.To > 0 {
+		exps = append(exps, dbx.NewExp("o.created_at <= {:to}", dbx.Params{"to": f.To}))
+	}
+
+	return dbx.And(exps...)
+}
+
+// orderItems returns items of the orders grouped by order id
+func orderItems(db dbx.Builder, orderIds ...int) (map[int][]OrderItem, error) {
+
+	var rows []OrderItem
+
+	items := make(map[int][]OrderItem, len(orderIds))
+
+	if len(orderIds) == 0 {
+		return items, nil
+	}
+
+	ids := make([]interface{}, len(orderIds))
+
+	for k, v := range orderIds {
+		ids[k] = v
+	}
+
+	err := db.Select(
+		"p.id product_id", "p.name product_name", "p.code product_code", "p.exist product_exist", "p.quantity product_quantity", "p.status product_status",
 		"p.price product_price", "p.sale_price product_sale_price", "p.sale_count product_sale_count",
 		"c.id currency_id", "c.name currency_name", "c.rate currency_rate", "c.iso currency_iso",
-		"oi.id", "oi.price", "oi.quantity").
+		"oi.id", "oi.order_id", "oi.price", "oi.quantity", "oi.discount", "oi.currency_iso snapshot_currency_iso", "oi.currency_rate snapshot_currency_rate").
 		From(tableWithAlias(tableNameOrderItems, "oi")).
 		InnerJoin(tableWithAlias(tableNameProducts, "p"), dbx.NewExp("oi.product_id=p.id")).
 		InnerJoin(tableWithAlias(tableNameCurrency, "c"), dbx.NewExp("p.currency_id=c.id")).
-		Where(dbx.NewExp("oi.order_id={:order_id}", dbx.Params{"order_id": orderId})).
-		All(&rowsOI)
+		Where(dbx.In("oi.order_id", ids...)).
+		OrderBy("oi.id").
+		All(&rows)
 
 	if err != nil {
-		log.Printf("[Get order %v][items query error] err: %v", orderId, err)
 		return nil, err
 	}
 
+	for _, v := range rows {
+		items[v.OrderId] = append(items[v.OrderId], v)
+	}
+
+	return items, nil
+}
+
+func toOrderEntity(row Order, rowsOI []OrderItem) (*entity.Order, error) {
+
+	var (
+		deliveryInfo DeliveryInfo
+		ds           []DeliveryStatus
+		ps           []PaymentStatus
+		err          error
+
+		order *entity.Order
+	)
+
+	orderId := row.Id
+
 	if err = json.Unmarshal([]byte(row.DeliveryInfo), &deliveryInfo); err != nil {
 		log.Printf("[Get order %v][decode delivery info] err: %v", orderId, err)
 		return nil, err
@@ -117,16 +329,26 @@ func (r OrderRepository) Get(ctx context.Context, orderId int) (*entity.Order, e
 			sc, _ = strconv.Atoi(v.ProductSaleCount.String)
 		}
 
-		price := entity.NewPrice(v.ProductPrice, sp, sc, entity.NewCurrency(v.CurrencyID, v.CurrencyName, v.CurrencyRate, v.CurrencyISO))
+		currency := entity.NewCurrency(v.CurrencyID, v.CurrencyName, v.CurrencyRate, v.CurrencyISO)
+
+		if v.SnapshotCurrencyISO.Valid == true && v.SnapshotCurrencyISO.String == v.CurrencyISO {
+			currency.Rate = float32(v.SnapshotCurrencyRate.Float64)
+		} else if v.SnapshotCurrencyISO.Valid == true {
+			currency = entity.NewCurrencySnapshot(v.SnapshotCurrencyISO.String, float32(v.SnapshotCurrencyRate.Float64))
+		}
+
+		price := entity.NewPrice(v.ProductPrice, sp, sc, currency)
 		pr := entity.NewSimpleProduct(
 			v.ProductId,
 			v.ProductName,
 			v.ProductCode,
 			v.ProductExist,
+			v.ProductQuantity,
 			v.ProductStatus,
 			*price,
 		)
 		oi[k] = entity.NewOrderProduct(v.Quantity, v.Cost, *pr)
+		oi[k].SetDiscount(int(v.Discount.Int64))
 	}
 
 	order = entity.NewOrder(
@@ -135,6 +357,7 @@ func (r OrderRepository) Get(ctx context.Context, orderId int) (*entity.Order, e
 		row.Comment,
 		row.DoNotCall,
 		row.Cost,
+		entity.NewCurrencySnapshot(row.CurrencyISO.String, float32(row.CurrencyRate.Float64)),
 		entity.NewOrderCustomer(row.Customer.Name, row.Customer.Phone),
 		entity.NewOrderDelivery(
 			row.DeliveryStatus,
@@ -154,6 +377,29 @@ func (r OrderRepository) Get(ctx context.Context, orderId int) (*entity.Order, e
 		oi,
 	)
 
+	if row.PromoCode.Valid == true {
+		discount := entity.NewOrderDiscount(row.PromoCode.String, int(row.Discount.Int64))
+		discount.SetReleased(row.PromoReleased.Bool)
+		order.SetDiscount(discount)
+	}
+
+	order.SetTrackingNumber(row.TrackingNumber.String)
+
+	if deliveryInfo.Waybill != nil {
+		order.SetWaybill(deliveryInfo.Waybill.Ref, row.TrackingNumber.String)
+		order.SetWaybillRequest(deliveryInfo.Waybill.RequestedAt)
+	}
+
+	if deliveryInfo.Courier != nil {
+		var slot *entity.OrderDeliverySlot
+
+		if deliveryInfo.Slot != nil {
+			slot = entity.NewOrderDeliverySlot(deliveryInfo.Slot.Date, deliveryInfo.Slot.Id, deliveryInfo.Slot.From, deliveryInfo.Slot.To)
+		}
+
+		order.SetCourier(entity.NewOrderDeliveryCourier(deliveryInfo.Courier.StreetCode, deliveryInfo.Courier.Street, deliveryInfo.Courier.House, deliveryInfo.Courier.Apartment), slot)
+	}
+
 	return order, nil
 }
 
@@ -177,6 +423,9 @@ func (r OrderRepository) Save(ctx context.Context, order *entity.Order) error {
 			Email:    order.GetPayment().GetExtra().GetEmail(),
 			PartsPay: order.GetPayment().GetExtra().GetPartsPay(),
 		},
+		Courier: toCourierRow(order.GetDelivery().GetCourier()),
+		Slot:    toDeliverySlotRow(order.GetDelivery().GetSlot()),
+		Waybill: toWaybillRow(order.GetDelivery()),
 	})
 
 	if err != nil {
@@ -211,7 +460,7 @@ func (r OrderRepository) Save(ctx context.Context, order *entity.Order) error {
 		return err
 	}
 
-	_, err = r.db.Update(tableNameOrder, dbx.Params{
+	params := dbx.Params{
 		"customer_phone": order.GetCustomer().GetPhone(),
 		"customer_name":  order.GetCustomer().GetName(),
 
@@ -228,12 +477,84 @@ func (r OrderRepository) Save(ctx context.Context, order *entity.Order) error {
 		"delivery_info":          string(dInfo),
 		"delivery_statuses_json": string(dStatuses),
 		"payment_statuses_json":  string(pStatuses),
-	}, dbx.NewExp("id={:id}", dbx.Params{"id": order.GetId()})).
+	}
+
+	if order.GetDelivery().GetTrackingNumber() != "" {
+		params["tracking_number"] = order.GetDelivery().GetTrackingNumber()
+	}
+
+	// discount changes when operator edits items, usage is released when payment fails
+	if order.GetDiscount() != nil {
+		params["discount"] = order.GetDiscount().GetAmount()
+		params["promo_released"] = order.GetDiscount().IsReleased()
+	}
+
+	_, err = conn(ctx, r.db).Update(tableNameOrder, params, dbx.NewExp("id={:id}", dbx.Params{"id": order.GetId()})).
 		Execute()
 
 	return err
 }
 
+func (r OrderRepository) SaveItems(ctx context.Context, order *entity.Order) error {
+
+	db := conn(ctx, r.db)
+
+	_, err := db.Delete(tableNameOrderItems, dbx.HashExp{"order_id": order.GetId()}).Execute()
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[delete order items][order %d][%v]", order.GetId(), err))
+	}
+
+	items := order.GetItems()
+	products := make([]*entity.OrderProduct, len(items))
+
+	for k := range items {
+		products[k] = &items[k]
+	}
+
+	return insertOrderItems(db, order.GetId(), products)
+}
+
+func insertOrderItems(db dbx.Builder, orderId int, products []*entity.OrderProduct) error {
+
+	for _, p := range products {
+		price := p.GetPrice()
+		currency := p.GetCurrency()
+		iDiscount := p.GetDiscount()
+		_, err := db.Insert(tableNameOrderItems, dbx.Params{
+			"order_id":      orderId,
+			"product_id":    p.GetProduct().ID,
+			"price":         price.GetInCent(),
+			"quantity":      p.GetQuantity(),
+			"discount":      iDiscount.GetInCent(),
+			"currency_iso":  currency.GetISO(),
+			"currency_rate": currency.GetRate(),
+		}).Execute()
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("[insert order item][product %d][%v]", p.GetProduct().ID, err))
+		}
+	}
+
+	return nil
+}
+
+func (r OrderRepository) GetCurrency(ctx context.Context, iso string) (*entity.Currency, error) {
+
+	var row Currency
+
+	err := conn(ctx, r.db).Select("c.id currency_id", "c.name currency_name", "c.rate currency_rate", "c.iso currency_iso").
+		From(tableWithAlias(tableNameCurrency, "c")).
+		Where(dbx.HashExp{"c.iso": iso}).
+		One(&row)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[get currency][%s][%v]", iso, err))
+	}
+
+	return entity.NewCurrency(row.CurrencyID, row.CurrencyName, row.CurrencyRate, row.CurrencyISO), nil
+}
+
 func (r OrderRepository) NextId() (int, error) {
 
 	var seq NextId
@@ -247,11 +568,38 @@ func (r OrderRepository) NextId() (int, error) {
 	return seq.Id, nil
 }
 
+// Create writes the order with its items and initial status history in one transaction
 func (r OrderRepository) Create(ctx context.Context, builder *checkout.CreateOrderBuilder) (*entity.Order, error) {
 
+	var order *entity.Order
+
+	err := NewUnitOfWork(r.db).Transaction(ctx, func(ctx context.Context) error {
+
+		o, err := r.create(ctx, builder)
+
+		if err != nil {
+			return err
+		}
+
+		order = o
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[create order][%v]", err))
+	}
+
+	return order, nil
+}
+
+func (r OrderRepository) create(ctx context.Context, builder *checkout.CreateOrderBuilder) (*entity.Order, error) {
+
 	var seq NextId
 
-	err := r.db.NewQuery(fmt.Sprintf("SELECT nextval('%s') as id", tableOrderSeqNextValID)).One(&seq)
+	db := conn(ctx, r.db)
+
+	err := db.NewQuery(fmt.Sprintf("SELECT nextval('%s') as id", tableOrderSeqNextValID)).One(&seq)
 
 	if err != nil {
 		return nil, err
@@ -275,6 +623,8 @@ func (r OrderRepository) Create(ctx context.Context, builder *checkout.CreateOrd
 			Email:    builder.PayInEmail,
 			PartsPay: builder.PayPartsPay,
 		},
+		Courier: toCourierRow(builder.Courier),
+		Slot:    toDeliverySlotRow(builder.Slot),
 	})
 
 	if err != nil {
@@ -301,7 +651,17 @@ func (r OrderRepository) Create(ctx context.Context, builder *checkout.CreateOrd
 		return nil, err
 	}
 
-	_, err = r.db.Insert(tableNameOrder, dbx.Params{
+	var (
+		promoCode interface{}
+		discount  int
+	)
+
+	if builder.Discount != nil {
+		promoCode = builder.Discount.GetCode()
+		discount = builder.Discount.GetAmount()
+	}
+
+	_, err = db.Insert(tableNameOrder, dbx.Params{
 		"id":             seq.Id,
 		"customer_phone": builder.Customer.GetPhone(),
 		"customer_name":  builder.Customer.GetName(),
@@ -316,6 +676,12 @@ func (r OrderRepository) Create(ctx context.Context, builder *checkout.CreateOrd
 		"comment":     builder.Comment,
 		"do_not_call": builder.DoNotCall,
 
+		"currency_iso":  builder.Currency.GetISO(),
+		"currency_rate": builder.Currency.GetRate(),
+
+		"promo_code": promoCode,
+		"discount":   discount,
+
 		"delivery_info":          string(dInfo),
 		"delivery_statuses_json": string(dStatuses),
 		"payment_statuses_json":  string(pStatuses),
@@ -327,19 +693,8 @@ func (r OrderRepository) Create(ctx context.Context, builder *checkout.CreateOrd
 		return nil, err
 	}
 
-	for _, p := range builder.Products {
-		pr := p.GetProduct()
-		price := (&pr).Price.GetPriceByQuantity(p.GetQuantity())
-		_, e := r.db.Insert(tableNameOrderItems, dbx.Params{
-			"order_id":   seq.Id,
-			"product_id": p.GetProduct().ID,
-			"price":      price,
-			"quantity":   p.GetQuantity(),
-		}).Execute()
-
-		if e != nil {
-			log.Printf("order item insert err: %v", err)
-		}
+	if err = insertOrderItems(db, seq.Id, builder.Products); err != nil {
+		return nil, err
 	}
 
 	odsh := make([]*entity.OrderDeliveryStatusHistory, 1)
@@ -360,7 +715,9 @@ func (r OrderRepository) Create(ctx context.Context, builder *checkout.CreateOrd
 		builder.PayPartsPay,
 	)
 
-	order := entity.NewOrder(seq.Id, now, builder.Comment, builder.DoNotCall, builder.Cost, builder.Customer, oDelivery, oPayment, builder.Products)
+	order := entity.NewOrder(seq.Id, now, builder.Comment, builder.DoNotCall, builder.Cost, builder.Currency, builder.Customer, oDelivery, oPayment, builder.Products)
+	order.SetDiscount(builder.Discount)
+	order.SetCourier(builder.Courier, builder.Slot)
 
 	return order, nil
 }
@@ -376,12 +733,9 @@ type PaymentRepository struct {
 
 func (r PaymentRepository) Get(ctx context.Context, transactionId string) (*entity.Payment, error) {
 
-	var (
-		row              Payment
-		created, updated time.Time
-	)
+	var row Payment
 
-	err := r.db.Select("*").
+	err := conn(ctx, r.db).Select("*").
 		From(tableNamePayments).
 		Where(dbx.NewExp("transaction_id={:id}", dbx.Params{"id": transactionId})).
 		One(&row)
@@ -390,6 +744,81 @@ func (r PaymentRepository) Get(ctx context.Context, transactionId string) (*enti
 		return nil, err
 	}
 
+	return toPaymentEntity(row), nil
+}
+
+func (r PaymentRepository) GetLastByOrder(ctx context.Context, orderId int) (*entity.Payment, error) {
+
+	var row Payment
+
+	err := conn(ctx, r.db).Select("*").
+		From(tableNamePayments).
+		Where(dbx.HashExp{"order_id": orderId}).
+		OrderBy("id desc").
+		Limit(1).
+		One(&row)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return toPaymentEntity(row), nil
+}
+
+func (r PaymentRepository) GetForUpdate(ctx context.Context, transactionId string) (*entity.Payment, error) {
+
+	err := lockRow(ctx, r.db, tableNamePayments, "transaction_id", transactionId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Get(ctx, transactionId)
+}
+
+func (r PaymentRepository) GetStale(ctx context.Context, providers []string, statuses []int, createdAfter, updatedBefore time.Time, limit int) ([]*entity.Payment, error) {
+
+	var rows []Payment
+
+	st := make([]interface{}, len(statuses))
+
+	for k, v := range statuses {
+		st[k] = v
+	}
+
+	pr := make([]interface{}, len(providers))
+
+	for k, v := range providers {
+		pr[k] = v
+	}
+
+	err := conn(ctx, r.db).Select("*").
+		From(tableNamePayments).
+		Where(dbx.In("provider", pr...)).
+		AndWhere(dbx.In("status", st...)).
+		AndWhere(dbx.NewExp("created_at>{:created}", dbx.Params{"created": createdAfter})).
+		AndWhere(dbx.NewExp("updated_at<{:updated}", dbx.Params{"updated": updatedBefore})).
+		OrderBy("updated_at asc").
+		Limit(int64(limit)).
+		All(&rows)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[get stale payments][%v]", err))
+	}
+
+	payments := make([]*entity.Payment, len(rows))
+
+	for k, v := range rows {
+		payments[k] = toPaymentEntity(v)
+	}
+
+	return payments, nil
+}
+
+func toPaymentEntity(row Payment) *entity.Payment {
+
+	var created, updated time.Time
+
 	if row.Created.Valid == true {
 		created, _ = time.Parse(time.RFC3339, row.Created.String)
 	} else {
@@ -402,16 +831,20 @@ func (r PaymentRepository) Get(ctx context.Context, transactionId string) (*enti
 		updated = time.Now()
 	}
 
-	return entity.NewPayment(
+	p := entity.NewPayment(
 		row.ID,
 		row.TransactionID,
 		row.OrderId,
 		row.Provider,
-		entity.NewPrice(row.Amount, 0, 0, nil),
+		entity.NewPrice(row.Amount, 0, 0, entity.NewCurrencySnapshot(row.CurrencyISO.String, 0)),
 		row.Status,
 		created.Unix(),
 		updated.Unix(),
-	), nil
+	)
+
+	p.SetExternalId(row.ExternalId.String)
+
+	return p
 }
 
 func (r PaymentRepository) NextId() (int, error) {
@@ -431,11 +864,12 @@ func (r PaymentRepository) Save(ctx context.Context, p *entity.Payment) error {
 
 	p.TouchUpdated()
 
-	_, err := r.db.Update(tableNamePayments, dbx.Params{
+	_, err := conn(ctx, r.db).Update(tableNamePayments, dbx.Params{
 		"order_id":       p.GetOrderId(),
 		"amount":         p.GetPrice().GetInCent(),
 		"transaction_id": p.GetTransactionId(),
 		"provider":       p.GetProvider(),
+		"external_id":    p.GetExternalId(),
 		"status":         p.GetStatus(),
 		"created_at":     p.GetCreatedTime(),
 		"updated_at":     p.GetUpdatedTime(),
@@ -447,10 +881,11 @@ func (r PaymentRepository) Save(ctx context.Context, p *entity.Payment) error {
 
 func (r PaymentRepository) Create(ctx context.Context, p *entity.Payment) error {
 
-	_, err := r.db.Insert(tableNamePayments, dbx.Params{
+	_, err := conn(ctx, r.db).Insert(tableNamePayments, dbx.Params{
 		"id":             p.GetId(),
 		"order_id":       p.GetOrderId(),
 		"amount":         p.GetPrice().GetInCent(),
+		"currency_iso":   p.GetPrice().GetCurrency().GetISO(),
 		"transaction_id": p.GetTransactionId(),
 		"provider":       "",
 		"status":         p.GetStatus(),
@@ -468,3 +903,30 @@ func (r PaymentRepository) Create(ctx context.Context, p *entity.Payment) error
 func tableWithAlias(tableName, alias string) string {
 	return tableName + " " + alias
 }
+
+func toCourierRow(c *entity.OrderDeliveryCourier) *Courier {
+
+	if c == nil {
+		return nil
+	}
+
+	return &Courier{StreetCode: c.GetStreetId(), Street: c.GetStreet(), House: c.GetHouse(), Apartment: c.GetApartment()}
+}
+
+func toDeliverySlotRow(s *entity.OrderDeliverySlot) *DeliverySlot {
+
+	if s == nil {
+		return nil
+	}
+
+	return &DeliverySlot{Date: s.GetDate(), Id: s.GetId(), From: s.GetFrom(), To: s.GetTo()}
+}
+
+func toWaybillRow(d entity.OrderDelivery) *Waybill {
+
+	if d.GetWaybillRef() == "" && d.GetWaybillRequestedAt() == 0 {
+		return nil
+	}
+
+	return &Waybill{Ref: d.GetWaybillRef(), RequestedAt: d.GetWaybillRequestedAt()}
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/wowucco/G3/internal/entity"
+	"strings"
+	"time"
+)
+
+const stockReservationStatusActive = 1
+const stockReservationStatusReleased = 2
+const stockReservationStatusConsumed = 3
+
+func NewStockRepository(db *dbx.DB) *StockRepository {
+
+	return &StockRepository{db: db}
+}
+
+type StockRepository struct {
+	db *dbx.DB
+}
+
+func (r StockRepository) GetAvailable(ctx context.Context, productIds []int) (map[int]int, error) {
+
+	var rows []StockAvailable
+
+	available := make(map[int]int, len(productIds))
+
+	if len(productIds) == 0 {
+		return available, nil
+	}
+
+	params := dbx.Params{"status": stockReservationStatusActive}
+	placeholders := make([]string, len(productIds))
+
+	for k, v := range productIds {
+		name := fmt.Sprintf("p%d", k)
+		params[name] = v
+		placeholders[k] = fmt.Sprintf("{:%s}", name)
+	}
+
+	// rows are locked in id order, so checkouts of overlapping carts don't deadlock
+	q := fmt.Sprintf(
+		"SELECT p.id product_id, CASE WHEN p.exist = 0 THEN 0 ELSE p.quantity END - COALESCE((SELECT SUM(r.quantity) FROM %s r WHERE r.product_id = p.id AND r.status = {:status}), 0) available "+
+			"FROM %s p WHERE p.id IN (%s) ORDER BY p.id FOR UPDATE",
+		tableNameStockReservation,
+		tableNameProducts,
+		strings.Join(placeholders, ","),
+	)
+
+	err := conn(ctx, r.db).NewQuery(q).Bind(params).All(&rows)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[get available stock][%v]", err))
+	}
+
+	for _, v := range rows {
+		available[v.ProductId] = v.Available
+	}
+
+	return available, nil
+}
+
+func (r StockRepository) HasReservation(ctx context.Context, orderId int) (bool, error) {
+
+	var count int
+
+	err := conn(ctx, r.db).Select("COUNT(*)").
+		From(tableNameStockReservation).
+		Where(dbx.HashExp{"order_id": orderId, "status": stockReservationStatusActive}).
+		Row(&count)
+
+	if err != nil {
+		return false, errors.New(fmt.Sprintf("[has stock reservation][%d][%v]", orderId, err))
+	}
+
+	return count > 0, nil
+}
+
+func (r StockRepository) Reserve(ctx context.Context, orderId int, items []entity.OrderProduct) error {
+
+	db := conn(ctx, r.db)
+	now := time.Now().Unix()
+
+	for _, v := range items {
+		_, err := db.Insert(tableNameStockReservation, dbx.Params{
+			"order_id":   orderId,
+			"product_id": v.GetProduct().ID,
+			"quantity":   v.GetQuantity(),
+			"status":     stockReservationStatusActive,
+			"created_at": now,
+			"updated_at": now,
+		}).Execute()
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("[reserve stock][order %d][product %d][%v]", orderId, v.GetProduct().ID, err))
+		}
+	}
+
+	return nil
+}
+
+func (r StockRepository) Release(ctx context.Context, orderId int) error {
+
+	_, err := conn(ctx, r.db).Update(tableNameStockReservation, dbx.Params{
+		"status":     stockReservationStatusReleased,
+		"updated_at": time.Now().Unix(),
+	}, dbx.HashExp{"order_id": orderId, "status": stockReservationStatusActive}).
+		Execute()
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[release stock][order %d][%v]", orderId, err))
+	}
+
+	return nil
+}
+
+func (r StockRepository) Consume(ctx context.Context, orderId int) error {
+
+	_, err := conn(ctx, r.db).Update(tableNameStockReservation, dbx.Params{
+		"status":     stockReservationStatusConsumed,
+		"updated_at": time.Now().Unix(),
+	}, dbx.HashExp{"order_id": orderId, "status": stockReservationStatusActive}).
+		Execute()
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[consume stock][order %d][%v]", orderId, err))
+	}
+
+	return nil
+}
+
+// ReleaseExpired takes orders with new payment and new delivery, so cash on delivery orders operators work on
+// keep their stock
+func (r StockRepository) ReleaseExpired(ctx context.Context, createdBefore time.Time) ([]int, error) {
+
+	var rows []StockReleased
+
+	q := fmt.Sprintf(
+		"UPDATE %s r SET status = {:released}, updated_at = {:now} "+
+			"FROM %s o WHERE o.id = r.order_id AND r.status = {:active} AND r.created_at < {:before} "+
+			"AND o.payment_status = {:payment} AND o.delivery_status = {:delivery} "+
+			"RETURNING r.order_id",
+		tableNameStockReservation,
+		tableNameOrder,
+	)
+
+	err := conn(ctx, r.db).NewQuery(q).Bind(dbx.Params{
+		"released": stockReservationStatusReleased,
+		"now":      time.Now().Unix(),
+		"active":   stockReservationStatusActive,
+		"before":   createdBefore.Unix(),
+		"payment":  entity.PaymentStatusNew,
+		"delivery": entity.DeliveryStatusNew,
+	}).All(&rows)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[release expired stock][%v]", err))
+	}
+
+	ids := make([]int, 0, len(rows))
+	seen := make(map[int]bool, len(rows))
+
+	for _, v := range rows {
+		if seen[v.OrderId] == false {
+			seen[v.OrderId] = true
+			ids = append(ids, v.OrderId)
+		}
+	}
+
+	return ids, nil
+}
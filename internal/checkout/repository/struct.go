@@ -15,6 +15,18 @@ type Address struct {
 	Address  string `json:"address"`
 	Code     string `json:"code"`
 }
+type Courier struct {
+	StreetCode string `json:"street_code"`
+	Street     string `json:"street"`
+	House      string `json:"house"`
+	Apartment  string `json:"apartment"`
+}
+type DeliverySlot struct {
+	Date string `json:"date"`
+	Id   string `json:"id"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
 type PaymentExtra struct {
 	Edrpou   string `json:"edrpou"`
 	Company  string `json:"company"`
@@ -29,13 +41,71 @@ type Payment struct {
 	Amount        int            `db:"amount"`
 	Status        int            `db:"status"`
 	Meta          sql.NullString `db:"meta"`
+	ExternalId    sql.NullString `db:"external_id"`
+	CurrencyISO   sql.NullString `db:"currency_iso"`
 	Created       sql.NullString `db:"created_at"`
 	Updated       sql.NullString `db:"updated_at"`
 }
+type Invoice struct {
+	Number        int            `db:"number"`
+	TransactionID string         `db:"transaction_id"`
+	PaymentId     int            `db:"payment_id"`
+	OrderId       int            `db:"order_id"`
+	Document      string         `db:"document"`
+	Created       sql.NullString `db:"created_at"`
+}
+type PromoCode struct {
+	ID           int            `db:"id"`
+	Code         string         `db:"code"`
+	Type         string         `db:"type"`
+	Value        int            `db:"value"`
+	Scope        string         `db:"scope"`
+	ScopeIds     sql.NullString `db:"scope_ids"`
+	MinCartValue sql.NullInt64  `db:"min_cart_value"`
+	UsageLimit   sql.NullInt64  `db:"usage_limit"`
+	UsageCount   int            `db:"usage_count"`
+	Expires      sql.NullString `db:"expires_at"`
+	Status       bool           `db:"status"`
+	Created      sql.NullString `db:"created_at"`
+	Updated      sql.NullString `db:"updated_at"`
+}
+type OrderChange struct {
+	ID       int            `db:"id"`
+	OrderId  int            `db:"order_id"`
+	Field    string         `db:"field"`
+	Before   string         `db:"before"`
+	After    string         `db:"after"`
+	Operator string         `db:"operator"`
+	Comment  sql.NullString `db:"comment"`
+	Created  int64          `db:"created_at"`
+}
+type PaymentEvent struct {
+	ID             int            `db:"id"`
+	Provider       string         `db:"provider"`
+	TransactionID  sql.NullString `db:"transaction_id"`
+	ContentType    string         `db:"content_type"`
+	Headers        sql.NullString `db:"headers"`
+	Body           string         `db:"body"`
+	Hash           string         `db:"hash"`
+	ValidSignature bool           `db:"valid_signature"`
+	Outcome        string         `db:"outcome"`
+	Message        sql.NullString `db:"message"`
+	Created        sql.NullString `db:"created_at"`
+	Updated        sql.NullString `db:"updated_at"`
+}
 type DeliveryInfo struct {
-	City    City         `json:"city"`
-	Address Address      `json:"address"`
-	Payment PaymentExtra `json:"payment"`
+	City    City          `json:"city"`
+	Address Address       `json:"address"`
+	Payment PaymentExtra  `json:"payment"`
+	Courier *Courier      `json:"courier,omitempty"`
+	Slot    *DeliverySlot `json:"slot,omitempty"`
+	Waybill *Waybill      `json:"waybill,omitempty"`
+}
+
+// Waybill is the carrier document of the order, RequestedAt is set while it's being created
+type Waybill struct {
+	Ref         string `json:"ref,omitempty"`
+	RequestedAt int64  `json:"requested_at,omitempty"`
 }
 
 type DeliveryStatus struct {
@@ -71,10 +141,20 @@ type Order struct {
 	DoNotCall bool   `db:"do_not_call"`
 	Cost      int    `db:"cost"`
 
+	CurrencyISO  sql.NullString  `db:"currency_iso"`
+	CurrencyRate sql.NullFloat64 `db:"currency_rate"`
+
+	PromoCode sql.NullString `db:"promo_code"`
+	Discount  sql.NullInt64  `db:"discount"`
+	// PromoReleased is set when the code usage was given back, nullable boolean column
+	PromoReleased sql.NullBool `db:"promo_released"`
+
 	DeliveryStatus       int    `db:"delivery_status"`
 	DeliveryStatusesJson string `db:"delivery_statuses_json"`
 	DeliveryInfo         string `db:"delivery_info"`
 
+	TrackingNumber sql.NullString `db:"tracking_number"`
+
 	PaymentStatus       int    `db:"payment_status"`
 	PaymentStatusesJson string `db:"payment_statuses_json"`
 
@@ -83,11 +163,12 @@ type Order struct {
 	PaymentMethod
 }
 type Product struct {
-	ProductId     int    `db:"product_id"`
-	ProductName   string `db:"product_name"`
-	ProductCode   int    `db:"product_code"`
-	ProductExist  int    `db:"product_exist"`
-	ProductStatus int    `db:"product_status"`
+	ProductId       int    `db:"product_id"`
+	ProductName     string `db:"product_name"`
+	ProductCode     int    `db:"product_code"`
+	ProductExist    int    `db:"product_exist"`
+	ProductQuantity int    `db:"product_quantity"`
+	ProductStatus   int    `db:"product_status"`
 
 	ProductPrice     int            `db:"product_price"`
 	ProductSalePrice sql.NullString `db:"product_sale_price"`
@@ -100,9 +181,24 @@ type Currency struct {
 	CurrencyISO  string  `db:"currency_iso"`
 }
 type OrderItem struct {
-	ID       int `db:"id"`
-	Quantity int `db:"quantity"`
-	Cost     int `db:"price"`
+	ID       int           `db:"id"`
+	OrderId  int           `db:"order_id"`
+	Quantity int           `db:"quantity"`
+	Cost     int           `db:"price"`
+	Discount sql.NullInt64 `db:"discount"`
 	Product
 	Currency
+
+	// rate the item price was converted by, it's empty for items of orders created before the snapshot
+	SnapshotCurrencyISO  sql.NullString  `db:"snapshot_currency_iso"`
+	SnapshotCurrencyRate sql.NullFloat64 `db:"snapshot_currency_rate"`
+}
+
+type StockAvailable struct {
+	ProductId int `db:"product_id"`
+	Available int `db:"available"`
+}
+
+type StockReleased struct {
+	OrderId int `db:"order_id"`
 }
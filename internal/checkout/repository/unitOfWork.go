@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	dbx "github.com/go-ozzo/ozzo-dbx"
+)
+
+type txCtxKey struct{}
+
+func NewUnitOfWork(db *dbx.DB) *UnitOfWork {
+
+	return &UnitOfWork{db: db}
+}
+
+type UnitOfWork struct {
+	db *dbx.DB
+}
+
+// Transaction runs fn inside one database transaction. Repositories called with the ctx passed to fn
+// share the transaction; it is committed when fn returns nil and rolled back otherwise.
+// Nested calls join the outer transaction.
+func (u UnitOfWork) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	if _, ok := ctx.Value(txCtxKey{}).(*dbx.Tx); ok == true {
+		return fn(ctx)
+	}
+
+	return u.db.TransactionalContext(ctx, nil, func(tx *dbx.Tx) error {
+		return fn(context.WithValue(ctx, txCtxKey{}, tx))
+	})
+}
+
+// conn returns the transaction bound to ctx, if any, or db itself
+func conn(ctx context.Context, db *dbx.DB) dbx.Builder {
+
+	if tx, ok := ctx.Value(txCtxKey{}).(*dbx.Tx); ok == true {
+		return tx
+	}
+
+	return db
+}
+
+// lockRow takes row lock with SELECT ... FOR UPDATE, the lock is held till the end of ctx transaction
+func lockRow(ctx context.Context, db *dbx.DB, table, column string, value interface{}) error {
+
+	var id int
+
+	q := fmt.Sprintf("SELECT id FROM %s WHERE %s={:value} FOR UPDATE", table, column)
+
+	err := conn(ctx, db).NewQuery(q).Bind(dbx.Params{"value": value}).Row(&id)
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[lock %s row][%v]", table, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package stock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/wowucco/G3/internal/checkout"
+	"github.com/wowucco/G3/internal/entity"
+	"time"
+)
+
+func NewStockService(r checkout.IStockRepository) *Service {
+
+	return &Service{repository: r}
+}
+
+// Service checks product availability and keeps ordered quantities reserved until the order is paid or closed.
+// Stock is product Quantity, products with Exist flag off are out of stock whatever the Quantity is.
+type Service struct {
+	repository checkout.IStockRepository
+}
+
+// Check validates requested items against loaded products without locking them
+func (s *Service) Check(items []checkout.OrderItemForm, products []*entity.OrderProduct) error {
+
+	found := make(map[int]entity.OrderProduct, len(products))
+
+	for _, v := range products {
+		found[v.GetProduct().ID] = *v
+	}
+
+	stockErr := &checkout.StockError{}
+
+	for _, v := range items {
+		p, ok := found[v.GetProductId()]
+
+		if ok == false {
+			stockErr.Add(checkout.ItemStockError{ProductId: v.GetProductId(), Reason: checkout.StockReasonNotFound, Requested: v.GetCount()})
+			continue
+		}
+
+		product := p.GetProduct()
+
+		if product.IsActive() == false {
+			stockErr.Add(checkout.ItemStockError{ProductId: product.ID, Name: product.Name, Reason: checkout.StockReasonDisabled, Requested: v.GetCount()})
+			continue
+		}
+
+		if product.GetInStock() < v.GetCount() {
+			stockErr.Add(checkout.ItemStockError{ProductId: product.ID, Name: product.Name, Reason: checkout.StockReasonOutOfStock, Requested: v.GetCount(), Available: product.GetInStock()})
+		}
+	}
+
+	if stockErr.HasItems() == true {
+		return stockErr
+	}
+
+	return nil
+}
+
+// Reserve locks stock of order items and reserves them. Should run inside a transaction.
+// It does nothing if the order already has an active reservation.
+func (s *Service) Reserve(ctx context.Context, order *entity.Order) error {
+
+	reserved, err := s.repository.HasReservation(ctx, order.GetId())
+
+	if err != nil {
+		return err
+	}
+
+	if reserved == true {
+		return nil
+	}
+
+	items := order.GetItems()
+	ids := make([]int, len(items))
+
+	for k, v := range items {
+		ids[k] = v.GetProduct().ID
+	}
+
+	available, err := s.repository.GetAvailable(ctx, ids)
+
+	if err != nil {
+		return err
+	}
+
+	stockErr := &checkout.StockError{}
+
+	for _, v := range items {
+		product := v.GetProduct()
+
+		if a := available[product.ID]; a < v.GetQuantity() {
+			stockErr.Add(checkout.ItemStockError{ProductId: product.ID, Name: product.Name, Reason: checkout.StockReasonOutOfStock, Requested: v.GetQuantity(), Available: a})
+		}
+	}
+
+	if stockErr.HasItems() == true {
+		return stockErr
+	}
+
+	if err := s.repository.Reserve(ctx, order.GetId(), items); err != nil {
+		return errors.New(fmt.Sprintf("[stock reserve][%d]%v", order.GetId(), err))
+	}
+
+	return nil
+}
+
+func (s *Service) Release(ctx context.Context, order *entity.Order) error {
+
+	return s.repository.Release(ctx, order.GetId())
+}
+
+// Update replaces the active reservation with the current order items, orders without reservation are skipped.
+// Should run inside a transaction.
+func (s *Service) Update(ctx context.Context, order *entity.Order) error {
+
+	reserved, err := s.repository.HasReservation(ctx, order.GetId())
+
+	if err != nil {
+		return err
+	}
+
+	if reserved == false {
+		return nil
+	}
+
+	if err := s.Release(ctx, order); err != nil {
+		return err
+	}
+
+	return s.Reserve(ctx, order)
+}
+
+// Settle follows the order state: reservation of the paid order is consumed, canceled delivery and canceled,
+// failed or refunded payment release it. Should run inside a transaction.
+func (s *Service) Settle(ctx context.Context, order *entity.Order) error {
+
+	if order.IsClosed() {
+		return s.Release(ctx, order)
+	}
+
+	if order.GetPayment().GetStatus() == entity.PaymentStatusDone {
+		return s.repository.Consume(ctx, order.GetId())
+	}
+
+	return nil
+}
+
+// ReleaseExpired releases reservations of orders left unpaid for longer than maxAge and returns their ids.
+// Payment initialized later reserves the stock again.
+func (s *Service) ReleaseExpired(ctx context.Context, maxAge time.Duration) ([]int, error) {
+
+	return s.repository.ReleaseExpired(ctx, time.Now().Add(-maxAge))
+}
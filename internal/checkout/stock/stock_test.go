@@ -0,0 +1,186 @@
+package stock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wowucco/G3/internal/checkout"
+	"github.com/wowucco/G3/internal/entity"
+)
+
+const (
+	reservationActive = iota + 1
+	reservationReleased
+	reservationConsumed
+)
+
+type reservation struct {
+	orderId   int
+	productId int
+	quantity  int
+	status    int
+	created   time.Time
+}
+
+// stockRepositoryStub keeps reservations in memory, quantity is the warehouse stock by product id
+type stockRepositoryStub struct {
+	quantity     map[int]int
+	reservations []*reservation
+	// unpaid lists orders ReleaseExpired may take
+	unpaid map[int]bool
+}
+
+func (r *stockRepositoryStub) GetAvailable(ctx context.Context, productIds []int) (map[int]int, error) {
+
+	available := make(map[int]int, len(productIds))
+
+	for _, id := range productIds {
+		available[id] = r.quantity[id]
+
+		for _, v := range r.reservations {
+			if v.productId == id && v.status == reservationActive {
+				available[id] -= v.quantity
+			}
+		}
+	}
+
+	return available, nil
+}
+
+func (r *stockRepositoryStub) HasReservation(ctx context.Context, orderId int) (bool, error) {
+
+	for _, v := range r.reservations {
+		if v.orderId == orderId && v.status == reservationActive {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *stockRepositoryStub) Reserve(ctx context.Context, orderId int, items []entity.OrderProduct) error {
+
+	for _, v := range items {
+		r.reservations = append(r.reservations, &reservation{orderId: orderId, productId: v.GetProduct().ID, quantity: v.GetQuantity(), status: reservationActive, created: time.Now()})
+	}
+
+	return nil
+}
+
+func (r *stockRepositoryStub) Release(ctx context.Context, orderId int) error {
+	r.setStatus(orderId, reservationReleased)
+	return nil
+}
+
+func (r *stockRepositoryStub) Consume(ctx context.Context, orderId int) error {
+	r.setStatus(orderId, reservationConsumed)
+	return nil
+}
+
+func (r *stockRepositoryStub) ReleaseExpired(ctx context.Context, createdBefore time.Time) ([]int, error) {
+
+	var ids []int
+
+	for _, v := range r.reservations {
+		if v.status == reservationActive && v.created.Before(createdBefore) && r.unpaid[v.orderId] {
+			v.status = reservationReleased
+			ids = append(ids, v.orderId)
+		}
+	}
+
+	return ids, nil
+}
+
+func (r *stockRepositoryStub) setStatus(orderId, status int) {
+
+	for _, v := range r.reservations {
+		if v.orderId == orderId && v.status == reservationActive {
+			v.status = status
+		}
+	}
+}
+
+func newStockOrder(id, quantity, paymentStatus, deliveryStatus int) *entity.Order {
+
+	return entity.NewOrder(
+		id, 0, "", false, 1000*quantity, nil,
+		entity.NewOrderCustomer("customer", "380501234567"),
+		entity.NewOrderDelivery(deliveryStatus, entity.NewDeliveryMethod(1, "novaposhta", entity.DeliveryMethodNovaposhta), nil, nil),
+		entity.NewOrderPayment(paymentStatus, entity.NewPaymentMethod(1, "p2p", entity.PaymentMethodP2P), nil, "", "", "", 0),
+		[]*entity.OrderProduct{entity.NewOrderProduct(quantity, 1000, entity.SimpleProduct{ID: 1, Name: "product"})},
+	)
+}
+
+func TestService_Reserve(t *testing.T) {
+	r := &stockRepositoryStub{quantity: map[int]int{1: 5}}
+	s := NewStockService(r)
+
+	assert.NoError(t, s.Reserve(context.Background(), newStockOrder(1, 3, entity.PaymentStatusNew, entity.DeliveryStatusNew)), "t1")
+	assert.NoError(t, s.Reserve(context.Background(), newStockOrder(1, 3, entity.PaymentStatusNew, entity.DeliveryStatusNew)), "t2")
+	assert.Equal(t, 1, len(r.reservations), "t3")
+
+	err := s.Reserve(context.Background(), newStockOrder(2, 3, entity.PaymentStatusNew, entity.DeliveryStatusNew))
+
+	var stockErr *checkout.StockError
+
+	assert.True(t, errors.As(err, &stockErr), "t4")
+	assert.Equal(t, 2, stockErr.Items[0].Available, "t5")
+}
+
+func TestService_Settle(t *testing.T) {
+
+	tests := []struct {
+		tag            string
+		paymentStatus  int
+		deliveryStatus int
+		expected       int
+		available      int
+	}{
+		{"t1", entity.PaymentStatusNew, entity.DeliveryStatusNew, reservationActive, 2},
+		{"t2", entity.PaymentStatusPending, entity.DeliveryStatusNew, reservationActive, 2},
+		{"t3", entity.PaymentStatusDone, entity.DeliveryStatusNew, reservationConsumed, 5},
+		{"t4", entity.PaymentStatusFailed, entity.DeliveryStatusNew, reservationReleased, 5},
+		{"t5", entity.PaymentStatusCanceled, entity.DeliveryStatusNew, reservationReleased, 5},
+		{"t6", entity.PaymentStatusRefund, entity.DeliveryStatusDelivery, reservationReleased, 5},
+		{"t7", entity.PaymentStatusNew, entity.DeliveryStatusCanceled, reservationReleased, 5},
+	}
+
+	for _, test := range tests {
+		r := &stockRepositoryStub{quantity: map[int]int{1: 5}}
+		s := NewStockService(r)
+
+		assert.NoError(t, s.Reserve(context.Background(), newStockOrder(1, 3, entity.PaymentStatusNew, entity.DeliveryStatusNew)), test.tag)
+		assert.NoError(t, s.Settle(context.Background(), newStockOrder(1, 3, test.paymentStatus, test.deliveryStatus)), test.tag)
+
+		available, _ := r.GetAvailable(context.Background(), []int{1})
+
+		assert.Equal(t, test.expected, r.reservations[0].status, test.tag)
+		assert.Equal(t, test.available, available[1], test.tag)
+	}
+}
+
+func TestService_ReleaseExpired(t *testing.T) {
+	r := &stockRepositoryStub{quantity: map[int]int{1: 10}, unpaid: map[int]bool{1: true, 3: true}}
+	s := NewStockService(r)
+
+	for id := 1; id <= 3; id++ {
+		assert.NoError(t, s.Reserve(context.Background(), newStockOrder(id, 1, entity.PaymentStatusNew, entity.DeliveryStatusNew)), "t1")
+	}
+
+	r.reservations[0].created = time.Now().Add(-2 * time.Hour)
+	r.reservations[1].created = time.Now().Add(-2 * time.Hour)
+
+	ids, err := s.ReleaseExpired(context.Background(), time.Hour)
+
+	assert.NoError(t, err, "t2")
+	assert.Equal(t, []int{1}, ids, "t3")
+
+	// payment initialized after the release reserves the stock again
+	assert.NoError(t, s.Reserve(context.Background(), newStockOrder(1, 1, entity.PaymentStatusNew, entity.DeliveryStatusNew)), "t4")
+
+	available, _ := r.GetAvailable(context.Background(), []int{1})
+	assert.Equal(t, 7, available[1], "t5")
+}
@@ -0,0 +1,336 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/wowucco/G3/internal/checkout"
+	"github.com/wowucco/G3/internal/entity"
+	"github.com/wowucco/G3/pkg/payments/monobank"
+	"github.com/wowucco/G3/pkg/payments/monobank/api"
+	"io/ioutil"
+	"net/http"
+)
+
+const providerMonobank = "monobank"
+const providerMonobankCtxKey = "monobank_ctx_key"
+const monobankSignHeader = "X-Sign"
+
+func init() {
+	RegisterProvider(providerMonobank, func(d ProviderDeps) (*Provider, error) {
+
+		c := monobank.NewClient(monobank.Config{
+			Token:       d.Config.GetString("payments.monobank.token"),
+			WebHookUrl:  d.Config.GetString("payments.monobank.callback_url"),
+			RedirectUrl: d.Config.GetString("payments.monobank.return_url"),
+			PublicKey:   d.Config.GetString("payments.monobank.public_key"),
+		})
+
+		// no Currencies, invoices are in the base currency only
+		return &Provider{
+			Name:    providerMonobank,
+			Methods: []string{entity.PaymentMethodMonobank},
+			Capabilities: []Capability{
+				CapabilityInit,
+				CapabilityAccept,
+				CapabilityCallback,
+				CapabilityCancel,
+				CapabilityStatus,
+			},
+			Strategy: NewMonobankStrategy(d.Repository, c),
+		}, nil
+	})
+}
+
+func NewMonobankStrategy(r checkout.IPaymentRepository, c *monobank.Client) *MonobankStrategy {
+
+	return &MonobankStrategy{r, c}
+}
+
+// MonobankStrategy holds the amount on customer card by invoice, the hold is finalized on accept
+type MonobankStrategy struct {
+	repository checkout.IPaymentRepository
+	provider   *monobank.Client
+}
+
+func (s *MonobankStrategy) Init(ctx context.Context, order *entity.Order, payment *entity.Payment) (IInitPaymentStrategyResponse, error) {
+
+	ccy, ok := api.CurrencyCode(payment.GetPrice().GetCurrency().GetISO())
+
+	if ok == false {
+		return nil, errors.New(fmt.Sprintf("[monobank init][unsupported currency %s]", payment.GetPrice().GetCurrency().GetISO()))
+	}
+
+	var basket []api.BasketItem
+
+	// item prices are in the base currency, basket sums are in the invoice one
+	currency := order.GetCurrency()
+
+	for _, p := range order.GetItems() {
+		for _, part := range p.GetDiscountedParts() {
+			basket = append(basket, api.NewBasketItem(p.GetProduct().Name, part.Quantity, currency.FromBase(part.Price)))
+		}
+	}
+
+	res, err := s.provider.Invoice.Create(
+		s.provider.Invoice.Create.WithParams(payment.GetTransactionId(), payment.GetPrice().GetInCent(), payment.GetDescription(), basket),
+		s.provider.Invoice.Create.WithCurrency(ccy),
+		s.provider.Invoice.Create.WithHold(),
+		s.provider.Invoice.Create.WithContext(ctx),
+	)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[monobank init][create invoice][%v]", err))
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("[monobank init][create invoice]%v", monobankError(res)))
+	}
+
+	var result api.CreateInvoiceResponse
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, errors.New(fmt.Sprintf("[monobank init][decode create invoice response][%v]", err))
+	}
+
+	// invoice id is needed to finalize the hold, webhook refers to the payment by our transaction id
+	payment.SetExternalId(result.InvoiceId)
+
+	return NewIniPaymentStrategyResponse(entity.PaymentInitActionRedirect, result.PageUrl, providerMonobank), nil
+}
+
+func (s *MonobankStrategy) Accept(ctx context.Context, order *entity.Order, payment *entity.Payment) (IAcceptHoldenPaymentStrategyResponse, error) {
+
+	res, err := s.provider.Invoice.Finalize(
+		s.provider.Invoice.Finalize.WithParams(payment.GetExternalId(), 0),
+		s.provider.Invoice.Finalize.WithContext(ctx),
+	)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[monobank accept][response][%v]", err))
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("[monobank accept]%v", monobankError(res)))
+	}
+
+	var (
+		status int
+		desc   string
+		result api.FinalizeInvoiceResponse
+	)
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, errors.New(fmt.Sprintf("[monobank accept][decode response][%v]", err))
+	}
+
+	switch result.Status {
+	case api.StatusSuccess:
+		status = entity.PaymentStatusDone
+		desc = "Payment was accepted"
+	case api.StatusProcessing:
+		status = entity.PaymentStatusPending
+		desc = "Payment accept is processing"
+	case api.StatusFailure:
+		status = entity.PaymentStatusFailed
+		desc = "Payment accept failed"
+	default:
+		return nil, errors.New(fmt.Sprintf("[monobank accept][unhandled response status][%v]", result.Status))
+	}
+
+	return NewAcceptHoldenPaymentStrategyResponse(status, desc, result.Stack()), nil
+}
+
+// Cancel releases the holden amount by cancelling the invoice
+func (s *MonobankStrategy) Cancel(ctx context.Context, order *entity.Order, payment *entity.Payment) (ICancelHoldenPaymentStrategyResponse, error) {
+
+	res, err := s.provider.Invoice.Cancel(
+		s.provider.Invoice.Cancel.WithParams(payment.GetExternalId(), 0),
+		s.provider.Invoice.Cancel.WithContext(ctx),
+	)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[monobank cancel][response][%v]", err))
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("[monobank cancel]%v", monobankError(res)))
+	}
+
+	var (
+		status int
+		desc   string
+		result api.CancelInvoiceResponse
+	)
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, errors.New(fmt.Sprintf("[monobank cancel][decode response][%v]", err))
+	}
+
+	switch result.Status {
+	case api.StatusSuccess:
+		status = entity.PaymentStatusCanceled
+		desc = "Holden payment was canceled"
+	case api.StatusProcessing:
+		status = entity.PaymentStatusPending
+		desc = "Holden payment cancel is processing"
+	case api.StatusFailure:
+		return nil, errors.New(fmt.Sprintf("[monobank cancel][provider declined][%v]", result.Stack()))
+	default:
+		return nil, errors.New(fmt.Sprintf("[monobank cancel][unhandled response status][%v]", result.Status))
+	}
+
+	return NewCancelHoldenPaymentStrategyResponse(status, desc, result.Stack()), nil
+}
+
+// Status requests the invoice state, it has the same fields as webhook
+func (s *MonobankStrategy) Status(ctx context.Context, payment *entity.Payment) (IProcessingCallbackPaymentStrategyResponse, error) {
+
+	// invoice wasn't created yet
+	if payment.GetExternalId() == "" {
+		return NewProcessingCallbackPaymentStrategyResponse(0, "", nil, true), nil
+	}
+
+	res, err := s.provider.Invoice.Status(
+		s.provider.Invoice.Status.WithParams(payment.GetExternalId()),
+		s.provider.Invoice.Status.WithContext(ctx),
+	)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[monobank status][response][%v]", err))
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("[monobank status]%v", monobankError(res)))
+	}
+
+	var result api.InvoiceStatusResponse
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, errors.New(fmt.Sprintf("[monobank status][decode response][%v]", err))
+	}
+
+	status, desc, skip, err := mapMonobankStatus(result.Status, result.FailureReason)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[monobank status][%v]", err))
+	}
+
+	return NewProcessingCallbackPaymentStrategyResponse(status, desc, result.Stack(), skip), nil
+}
+
+func (s *MonobankStrategy) IsValidSignature(ctx *gin.Context) bool {
+
+	cb, err := retrieveMonobankCallback(ctx)
+
+	if err != nil {
+		return false
+	}
+
+	return s.provider.Sign.WebhookCheck(
+		s.provider.Sign.WebhookCheck.WithParams(cb.body, ctx.GetHeader(monobankSignHeader)),
+		s.provider.Sign.WebhookCheck.WithContext(ctx),
+	)
+}
+func (s *MonobankStrategy) GetTransactionId(ctx *gin.Context) string {
+
+	cb, err := retrieveMonobankCallback(ctx)
+
+	if err != nil {
+		return ""
+	}
+
+	return cb.callback.Reference
+}
+func (s *MonobankStrategy) ProcessingCallback(ctx *gin.Context) (IProcessingCallbackPaymentStrategyResponse, error) {
+
+	cb, err := retrieveMonobankCallback(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	status, desc, skip, err := mapMonobankStatus(cb.callback.Status, cb.callback.FailureReason)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProcessingCallbackPaymentStrategyResponse(status, desc, cb.callback.Stack(), skip), nil
+}
+
+func mapMonobankStatus(status, failureReason string) (int, string, bool, error) {
+	switch status {
+	case api.StatusHold:
+		return entity.PaymentStatusWaitingConfirmation, "Payment is holden", false, nil
+	case api.StatusProcessing:
+		return entity.PaymentStatusPending, "Payment is processing", false, nil
+	case api.StatusSuccess:
+		return entity.PaymentStatusDone, "Payment was done", false, nil
+	case api.StatusFailure:
+		return entity.PaymentStatusFailed, fmt.Sprintf("Payment failed: %s", failureReason), false, nil
+	case api.StatusExpired:
+		return entity.PaymentStatusCanceled, "Invoice was expired", false, nil
+	case api.StatusReversed:
+		return entity.PaymentStatusRefund, "Payment was reversed", false, nil
+	case api.StatusCreated:
+		return 0, "", true, nil
+	default:
+		return 0, "", false, errors.New(fmt.Sprintf("unknow monobank status '%s'", status))
+	}
+}
+
+func monobankError(res *api.Response) error {
+
+	var e api.ErrorResponse
+
+	b, _ := ioutil.ReadAll(res.Body)
+
+	if err := json.Unmarshal(b, &e); err != nil || e.ErrCode == "" {
+		return errors.New(fmt.Sprintf("[response status %d][%s]", res.StatusCode, string(b)))
+	}
+
+	return errors.New(fmt.Sprintf("[response status %d][%s][%s]", res.StatusCode, e.ErrCode, e.ErrText))
+}
+
+type monobankCallback struct {
+	body     []byte
+	callback api.Callback
+}
+
+// retrieveMonobankCallback keeps the raw body in context, signature is calculated over the exact bytes
+func retrieveMonobankCallback(ctx *gin.Context) (monobankCallback, error) {
+
+	var cb monobankCallback
+
+	if d, exist := ctx.Get(providerMonobankCtxKey); exist == true {
+
+		cb = d.(monobankCallback)
+	} else {
+		b, err := ioutil.ReadAll(ctx.Request.Body)
+
+		if err != nil {
+			return cb, errors.New(fmt.Sprintf("[failet to read request body][%v]", err))
+		}
+
+		if err := json.Unmarshal(b, &cb.callback); err != nil {
+			return cb, errors.New(fmt.Sprintf("[failed to unmarshal body][%v]", err))
+		}
+
+		cb.body = b
+
+		ctx.Set(providerMonobankCtxKey, cb)
+	}
+
+	return cb, nil
+}
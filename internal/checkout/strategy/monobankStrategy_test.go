@@ -0,0 +1,148 @@
+package strategy
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wowucco/G3/internal/entity"
+	"github.com/wowucco/G3/pkg/payments/monobank"
+)
+
+func newMonobankStandIn(t *testing.T, key *ecdsa.PrivateKey) (*httptest.Server, map[string][]byte) {
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+
+	pubKey := base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	requests := make(map[string][]byte)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errCode":"FORBIDDEN","errText":"forbidden"}`))
+			return
+		}
+
+		b, _ := ioutil.ReadAll(r.Body)
+		requests[r.URL.Path] = b
+
+		switch r.URL.Path {
+		case "/api/merchant/pubkey":
+			_ = json.NewEncoder(w).Encode(map[string]string{"key": pubKey})
+		case "/api/merchant/invoice/create":
+			_, _ = w.Write([]byte(`{"invoiceId":"inv-1","pageUrl":"https://pay.mbnk.biz/inv-1"}`))
+		case "/api/merchant/invoice/finalize":
+			_, _ = w.Write([]byte(`{"status":"success"}`))
+		case "/api/merchant/invoice/cancel":
+			_, _ = w.Write([]byte(`{"status":"success","createdDate":"","modifiedDate":""}`))
+		case "/api/merchant/invoice/status":
+			requests[r.URL.Path] = []byte(r.URL.RawQuery)
+			_, _ = w.Write([]byte(`{"invoiceId":"inv-1","status":"hold","amount":10050,"ccy":980}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return srv, requests
+}
+
+func newMonobankCallbackContext(body []byte, sign string) *gin.Context {
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/callback/monobank", bytes.NewReader(body))
+	c.Request.Header.Set("X-Sign", sign)
+
+	return c
+}
+
+func signMonobank(t *testing.T, key *ecdsa.PrivateKey, body []byte) string {
+
+	hash := sha256.Sum256(body)
+	r, ss, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	assert.NoError(t, err)
+
+	sign, err := asn1.Marshal(struct{ R, S *big.Int }{r, ss})
+	assert.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(sign)
+}
+
+func TestMonobankStrategy(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	srv, requests := newMonobankStandIn(t, key)
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	s := NewMonobankStrategy(nil, monobank.NewClient(monobank.Config{Token: "token", BaseUrl: u}))
+
+	order := entity.NewOrder(1, 0, "", false, 10050, nil, nil, nil, nil, []*entity.OrderProduct{
+		entity.NewOrderProduct(1, 10050, entity.SimpleProduct{ID: 1, Name: "product"}),
+	})
+	payment := entity.CreateNewPaymentByOrder(1, order)
+
+	r, err := s.Init(context.Background(), order, payment)
+	assert.NoError(t, err, "t1")
+	assert.Equal(t, "https://pay.mbnk.biz/inv-1", r.GetResource(), "t2")
+	assert.Equal(t, "inv-1", payment.GetExternalId(), "t3")
+	assert.Contains(t, string(requests["/api/merchant/invoice/create"]), `"paymentType":"hold"`, "t4")
+
+	body := []byte(`{"invoiceId":"inv-1","status":"hold","amount":10050,"ccy":980,"reference":"` + payment.GetTransactionId() + `"}`)
+
+	c := newMonobankCallbackContext(body, signMonobank(t, key, body))
+	assert.True(t, s.IsValidSignature(c), "t5")
+	assert.Equal(t, payment.GetTransactionId(), s.GetTransactionId(c), "t6")
+
+	cb, err := s.ProcessingCallback(c)
+	assert.NoError(t, err, "t7")
+	assert.Equal(t, entity.PaymentStatusWaitingConfirmation, cb.GetStatus(), "t8")
+
+	tampered := bytes.Replace(body, []byte("hold"), []byte("success"), 1)
+	assert.False(t, s.IsValidSignature(newMonobankCallbackContext(tampered, signMonobank(t, key, body))), "t9")
+
+	a, err := s.Accept(context.Background(), order, payment)
+	assert.NoError(t, err, "t10")
+	assert.Equal(t, entity.PaymentStatusDone, a.GetStatus(), "t11")
+	assert.Contains(t, string(requests["/api/merchant/invoice/finalize"]), `"invoiceId":"inv-1"`, "t12")
+
+	st, err := s.Status(context.Background(), payment)
+	assert.NoError(t, err, "t13")
+	assert.Equal(t, entity.PaymentStatusWaitingConfirmation, st.GetStatus(), "t14")
+	assert.Equal(t, "invoiceId=inv-1", string(requests["/api/merchant/invoice/status"]), "t15")
+
+	cl, err := s.Cancel(context.Background(), order, payment)
+	assert.NoError(t, err, "t16")
+	assert.Equal(t, entity.PaymentStatusCanceled, cl.GetStatus(), "t17")
+	assert.Contains(t, string(requests["/api/merchant/invoice/cancel"]), `"invoiceId":"inv-1"`, "t18")
+
+	st, err = s.Status(context.Background(), entity.CreateNewPaymentByOrder(2, order))
+	assert.NoError(t, err, "t19")
+	assert.True(t, st.Skip(), "t20")
+
+	usd := entity.NewOrder(2, 0, "", false, 367, entity.NewCurrencySnapshot("USD", 27.35), nil, nil, nil, []*entity.OrderProduct{
+		entity.NewOrderProduct(1, 10050, entity.SimpleProduct{ID: 1, Name: "product"}),
+	})
+
+	_, err = s.Init(context.Background(), usd, entity.CreateNewPaymentByOrder(3, usd))
+	assert.NoError(t, err, "t21")
+	assert.Contains(t, string(requests["/api/merchant/invoice/create"]), `"ccy":840`, "t22")
+	assert.Contains(t, string(requests["/api/merchant/invoice/create"]), `"sum":367`, "t23")
+}
@@ -12,6 +12,33 @@ import (
 
 const providerLiqpay = "liqpay"
 
+func init() {
+	RegisterProvider(providerLiqpay, func(d ProviderDeps) (*Provider, error) {
+
+		c := liqpay.NewClient(
+			d.Config.GetString("payments.liqpay.public"),
+			d.Config.GetString("payments.liqpay.private"),
+			d.Config.GetString("payments.liqpay.callback_url"),
+			d.Config.GetString("payments.liqpay.return_url"),
+		)
+
+		return &Provider{
+			Name:    providerLiqpay,
+			Methods: []string{entity.PaymentMethodP2P},
+			Capabilities: []Capability{
+				CapabilityInit,
+				CapabilityAccept,
+				CapabilityCallback,
+				CapabilityRefund,
+				CapabilityCancel,
+				CapabilityStatus,
+			},
+			Currencies: liqpay.Currencies,
+			Strategy:   NewP2PStrategy(d.Repository, c),
+		}, nil
+	})
+}
+
 func NewP2PStrategy(r checkout.IPaymentRepository, p *liqpay.Client) *P2PStrategy {
 
 	return &P2PStrategy{r, p}
@@ -25,7 +52,7 @@ type P2PStrategy struct {
 
 func (s *P2PStrategy) Init(ctx context.Context, order *entity.Order, payment *entity.Payment) (IInitPaymentStrategyResponse, error) {
 
-	form, err := s.provider.Hold(payment.GetTransactionId(), payment.GetPrice().CentToCurrency(), payment.GetDescription())
+	form, err := s.provider.Hold(payment.GetTransactionId(), payment.GetPrice().CentToCurrency(), payment.GetPrice().GetCurrency().GetISO(), payment.GetDescription())
 
 	if err != nil {
 		return nil, errors.New(fmt.Sprintf("[p2p init][hold payment][%v]", err))
@@ -67,6 +94,59 @@ func (s *P2PStrategy) Accept(ctx context.Context, order *entity.Order, payment *
 	return NewAcceptHoldenPaymentStrategyResponse(status, desc, r), nil
 }
 
+func (s *P2PStrategy) Cancel(ctx context.Context, order *entity.Order, payment *entity.Payment) (ICancelHoldenPaymentStrategyResponse, error) {
+
+	r, e := s.provider.CancelHolden(payment.GetTransactionId(), payment.GetPrice().CentToCurrency())
+
+	if e != nil {
+		return nil, errors.New(fmt.Sprintf("[p2p cancel holden error][%v]", e))
+	}
+
+	var (
+		status int
+		desc   string
+	)
+
+	switch r["status"] {
+	case liqpay.StatusReversed:
+		status = entity.PaymentStatusCanceled
+		desc = "Holden payment was canceled"
+	case liqpay.StatusError, liqpay.StatusFail:
+		return nil, errors.New(fmt.Sprintf("[p2p cancel holden][err_code: %v, err_desc: %v]", r["err_code"], r["err_description"]))
+	default:
+		return nil, errors.New(fmt.Sprintf("[unhandled liqpay status][%v]", r["status"]))
+	}
+
+	return NewCancelHoldenPaymentStrategyResponse(status, desc, r), nil
+}
+
+func (s *P2PStrategy) Refund(ctx context.Context, order *entity.Order, payment *entity.Payment, refund *entity.Refund) (IRefundPaymentStrategyResponse, error) {
+
+	r, e := s.provider.Refund(payment.GetTransactionId(), refund.GetPrice().CentToCurrency())
+
+	if e != nil {
+		return nil, errors.New(fmt.Sprintf("[p2p refund error][%v]", e))
+	}
+
+	var (
+		status int
+		desc   string
+	)
+
+	switch r["status"] {
+	case liqpay.StatusReversed:
+		status = entity.RefundStatusDone
+		desc = "Payment was refunded"
+	case liqpay.StatusError, liqpay.StatusFail:
+		status = entity.RefundStatusFailed
+		desc = fmt.Sprintf("err_code: %v, err_desc: %v", r["err_code"], r["err_description"])
+	default:
+		return nil, errors.New(fmt.Sprintf("[unhandled liqpay refund status][%v]", r["status"]))
+	}
+
+	return NewRefundPaymentStrategyResponse(status, desc, r), nil
+}
+
 func (s *P2PStrategy) IsValidSignature(ctx *gin.Context) bool {
 	cb := make(map[string]interface{})
 	cb["data"] = ctx.PostForm("data")
@@ -104,6 +184,36 @@ func (s *P2PStrategy) ProcessingCallback(ctx *gin.Context) (IProcessingCallbackP
 	return NewProcessingCallbackPaymentStrategyResponse(status, res.Desc, res.Stack, skip), nil
 }
 
+func (s *P2PStrategy) Status(ctx context.Context, payment *entity.Payment) (IProcessingCallbackPaymentStrategyResponse, error) {
+
+	r, err := s.provider.Status(payment.GetTransactionId())
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[p2p status][%v]", err))
+	}
+
+	// customer didn't submit the payment form yet
+	if r["err_code"] == liqpay.ErrCodePaymentNotFound {
+		return NewProcessingCallbackPaymentStrategyResponse(0, "", r, true), nil
+	}
+
+	st, _ := r["status"].(string)
+	status, err := mapLiqpayStatuses(st)
+
+	// intermediate liqpay statuses, wait for the final one
+	if err != nil {
+		return NewProcessingCallbackPaymentStrategyResponse(0, "", r, true), nil
+	}
+
+	desc := "updated by status request"
+
+	if status == entity.PaymentStatusFailed {
+		desc = fmt.Sprintf("code: %v | description: %v", r["err_code"], r["err_description"])
+	}
+
+	return NewProcessingCallbackPaymentStrategyResponse(status, desc, r, false), nil
+}
+
 func mapLiqpayStatuses(s string) (int, error) {
 	switch s {
 	case liqpay.StatusHoldWait:
@@ -16,6 +16,41 @@ import (
 const providerPrivatPartsPay = "privat_parts_pay"
 const providerPrivatPartsPayCtxKey = "privat_parts_pay_ctx_key"
 
+func init() {
+	RegisterProvider(providerPrivatPartsPay, func(d ProviderDeps) (*Provider, error) {
+
+		c := privatPay.NewClient(privatPay.Config{
+			StoreId:     d.Config.GetString("payments.privat_pay.store_id"),
+			Password:    d.Config.GetString("payments.privat_pay.passport"),
+			Min:         d.Config.GetInt("payments.privat_pay.min_parts"),
+			Max:         d.Config.GetInt("payments.privat_pay.max_parts"),
+			ResponseUrl: d.Config.GetString("payments.privat_pay.callback_url"),
+			RedirectUrl: d.Config.GetString("payments.privat_pay.return_url"),
+
+			MinPartAmount: d.Config.GetInt("payments.privat_pay.min_part_amount"),
+			Commission: privatPay.Commission{
+				PP: d.Config.GetFloat64("payments.privat_pay.commission.pp"),
+				II: d.Config.GetFloat64("payments.privat_pay.commission.ii"),
+			},
+		})
+
+		return &Provider{
+			Name:    providerPrivatPartsPay,
+			Methods: []string{entity.PaymentMethodPartsPay},
+			Capabilities: []Capability{
+				CapabilityInit,
+				CapabilityAccept,
+				CapabilityCallback,
+				CapabilityRefund,
+				CapabilityCancel,
+				CapabilityStatus,
+				CapabilityInstallment,
+			},
+			Strategy: NewPartsPayStrategy(d.Repository, c),
+		}, nil
+	})
+}
+
 func NewPartsPayStrategy(r checkout.IPaymentRepository, c *privatPay.Client) *PartsPayStrategy {
 
 	return &PartsPayStrategy{r, c}
@@ -26,19 +61,61 @@ type PartsPayStrategy struct {
 	provider   *privatPay.Client
 }
 
+func (s *PartsPayStrategy) Installments(ctx context.Context, amount int, installmentType string) ([]*entity.Installment, error) {
+
+	var merchantType string
+
+	switch installmentType {
+	case entity.InstallmentTypeParts:
+		merchantType = api.MerchantTypePP
+	case entity.InstallmentTypeInstant:
+		merchantType = api.MerchantTypeII
+	default:
+		return nil, errors.New(fmt.Sprintf("[privat pay installments][unknown installment type %s]", installmentType))
+	}
+
+	res, err := s.provider.Calculate(amount, merchantType)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[privat pay installments]%v", err))
+	}
+
+	installments := make([]*entity.Installment, len(res))
+
+	for k, v := range res {
+		installments[k] = entity.NewInstallment(installmentType, v.Parts, v.Payment, v.Commission, v.Total)
+	}
+
+	return installments, nil
+}
+
 func (s *PartsPayStrategy) Init(ctx context.Context, order *entity.Order, payment *entity.Payment) (IInitPaymentStrategyResponse, error) {
 
+	// parts pay is available in hryvnia only
+	if currency := payment.GetPrice().GetCurrency(); currency.IsBase() == false {
+		return nil, errors.New(fmt.Sprintf("[privat pay init][unsupported currency %s]", currency.GetISO()))
+	}
+
+	parts := order.GetPayment().GetExtra().GetPartsPay()
+
+	if parts < s.provider.GetMin() || parts > s.provider.GetMax() {
+		return nil, errors.New(fmt.Sprintf("[privat pay init][parts count %d is out of range %d-%d]", parts, s.provider.GetMin(), s.provider.GetMax()))
+	}
+
 	tPrice := order.GetPrice()
 
-	products := make([]api.Product, len(order.GetItems()))
+	// bank checks the amount against products sum, discounted lines are sent by discounted unit prices
+	var products []api.Product
 
-	for k, p := range order.GetItems() {
-		pPrice := p.GetPrice()
-		products[k] = api.NewProduct(p.GetProduct().Name, p.GetQuantity(), pPrice.CentToFloatValue())
+	for _, p := range order.GetItems() {
+		for _, part := range p.GetDiscountedParts() {
+			pPrice := entity.NewPrice(part.Price, 0, 0, nil)
+			products = append(products, api.NewProduct(p.GetProduct().Name, part.Quantity, pPrice.CentToFloatValue()))
+		}
 	}
 
 	res, err := s.provider.Pay.Hold(
-		s.provider.Pay.Hold.WithParams(payment.GetTransactionId(), tPrice.CentToFloatValue(), order.GetPayment().GetExtra().GetPartsPay(), products, api.MerchantTypePP),
+		s.provider.Pay.Hold.WithParams(payment.GetTransactionId(), tPrice.CentToFloatValue(), parts, products, api.MerchantTypePP),
 		s.provider.Pay.Hold.WithContext(ctx),
 	)
 
@@ -96,6 +173,69 @@ func (s *PartsPayStrategy) Accept(ctx context.Context, order *entity.Order, paym
 
 	return NewAcceptHoldenPaymentStrategyResponse(status, desc, result.Stack()), nil
 }
+func (s *PartsPayStrategy) Cancel(ctx context.Context, order *entity.Order, payment *entity.Payment) (ICancelHoldenPaymentStrategyResponse, error) {
+
+	res, err := s.provider.Pay.Cancel(s.provider.Pay.Cancel.WithContext(ctx), s.provider.Pay.Cancel.WithParams(payment.GetTransactionId()))
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[privat pay cancel holden][response][%v]", err))
+	}
+
+	defer res.Body.Close()
+
+	var result api.CancelHoldenPaymentResponse
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, errors.New(fmt.Sprintf("[privat pay cancel holden][decode response][%v]", err))
+	}
+
+	switch result.State {
+	case api.StateSuccess, api.StateCanceled:
+		return NewCancelHoldenPaymentStrategyResponse(entity.PaymentStatusCanceled, "Holden payment was canceled", result.Stack()), nil
+	case api.StateFail:
+		return nil, errors.New(fmt.Sprintf("[privat pay cancel holden][err_desc: %v]", result.Message))
+	default:
+		return nil, errors.New(fmt.Sprintf("[privat pay cancel holden][unhandled response status][%v]", result.State))
+	}
+}
+
+func (s *PartsPayStrategy) Refund(ctx context.Context, order *entity.Order, payment *entity.Payment, refund *entity.Refund) (IRefundPaymentStrategyResponse, error) {
+
+	res, err := s.provider.Pay.Refund(
+		s.provider.Pay.Refund.WithContext(ctx),
+		s.provider.Pay.Refund.WithParams(payment.GetTransactionId(), refund.GetPrice().CentToFloatValue()),
+	)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[privat pay refund][response][%v]", err))
+	}
+
+	defer res.Body.Close()
+
+	var (
+		status int
+		desc   string
+		result api.RefundPaymentResponse
+	)
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, errors.New(fmt.Sprintf("[privat pay refund][decode response][%v]", err))
+	}
+
+	switch result.State {
+	case api.StateFail:
+		status = entity.RefundStatusFailed
+		desc = fmt.Sprintf("err_code: none,err_desc: %v", result.Message)
+	case api.StateSuccess:
+		status = entity.RefundStatusDone
+		desc = "Payment was refunded"
+	default:
+		return nil, errors.New(fmt.Sprintf("[privat pay refund][unhandled response status][%v]", result.State))
+	}
+
+	return NewRefundPaymentStrategyResponse(status, desc, result.Stack()), nil
+}
+
 func (s *PartsPayStrategy) IsValidSignature(ctx *gin.Context) bool {
 
 	cb, err := retrievePaymentCallback(ctx)
@@ -118,30 +258,61 @@ func (s *PartsPayStrategy) GetTransactionId(ctx *gin.Context) string {
 }
 func (s *PartsPayStrategy) ProcessingCallback(ctx *gin.Context) (IProcessingCallbackPaymentStrategyResponse, error) {
 
-	var (
-		status int
-		desc   string
-		skip   bool
-	)
 	cb, err := retrievePaymentCallback(ctx)
 
 	if err != nil {
 		return nil, err
 	}
 
-	switch cb.PaymentState {
+	status, desc, skip, err := mapPrivatPartsPayState(cb.PaymentState, cb.Message, "updated by callback")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProcessingCallbackPaymentStrategyResponse(status, desc, cb.Stack(), skip), nil
+}
+
+func (s *PartsPayStrategy) Status(ctx context.Context, payment *entity.Payment) (IProcessingCallbackPaymentStrategyResponse, error) {
+
+	res, err := s.provider.Pay.State(s.provider.Pay.State.WithContext(ctx), s.provider.Pay.State.WithParams(payment.GetTransactionId()))
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[privat pay state][response][%v]", err))
+	}
+
+	defer res.Body.Close()
+
+	var result api.PaymentStateResponse
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, errors.New(fmt.Sprintf("[privat pay state][decode response][%v]", err))
+	}
+
+	if result.State != api.StateSuccess {
+		return nil, errors.New(fmt.Sprintf("[privat pay state][response state is not success][%s][%s]", result.State, result.Message))
+	}
+
+	status, desc, skip, err := mapPrivatPartsPayState(result.PaymentState, result.Message, "updated by status request")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProcessingCallbackPaymentStrategyResponse(status, desc, result.Stack(), skip), nil
+}
+
+func mapPrivatPartsPayState(state, message, desc string) (int, string, bool, error) {
+
+	switch state {
 	case api.StateSuccess:
-		status = entity.PaymentStatusDone
-		desc = "updated by callback"
+		return entity.PaymentStatusDone, desc, false, nil
 	case api.StateFail:
-		status = entity.PaymentStatusFailed
-		desc = cb.Message
+		return entity.PaymentStatusFailed, message, false, nil
 	case api.StateCanceled:
-		status = entity.PaymentStatusCanceled
-		desc = "updated by callback"
+		return entity.PaymentStatusCanceled, desc, false, nil
 	case api.StateLocked:
-		status = entity.PaymentStatusWaitingConfirmation
-		desc = "updated by callback"
+		return entity.PaymentStatusWaitingConfirmation, desc, false, nil
 	case api.StateClientWait:
 		fallthrough
 	case api.StateOtpWaiting:
@@ -149,12 +320,10 @@ func (s *PartsPayStrategy) ProcessingCallback(ctx *gin.Context) (IProcessingCall
 	case api.StatePpCreation:
 		fallthrough
 	case api.StateCreated:
-		skip = true
+		return 0, "", true, nil
 	default:
-		return nil, errors.New(fmt.Sprintf("unknow privat part pay status '%s'", cb.PaymentState))
+		return 0, "", false, errors.New(fmt.Sprintf("unknow privat part pay status '%s'", state))
 	}
-
-	return NewProcessingCallbackPaymentStrategyResponse(status, desc, cb.Stack(), skip), nil
 }
 
 func retrievePaymentCallback(ctx *gin.Context) (api.Callback, error) {
@@ -0,0 +1,198 @@
+package strategy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/wowucco/G3/internal/checkout"
+	"github.com/wowucco/G3/internal/entity"
+	"html/template"
+	"time"
+)
+
+const providerPayIn = "pay_in"
+
+func init() {
+	RegisterProvider(providerPayIn, func(d ProviderDeps) (*Provider, error) {
+
+		return &Provider{
+			Name:         providerPayIn,
+			Methods:      []string{entity.PaymentMethodPayin},
+			Capabilities: []Capability{CapabilityInit},
+			Strategy: NewPayInStrategy(d.InvoiceRepository, PayInConfig{
+				InvoiceUrlMask: d.Config.GetString("payments.pay_in.invoice_url_mask"),
+				ValidDays:      d.Config.GetInt("payments.pay_in.valid_days"),
+				Seller: PayInSeller{
+					Name:    d.Config.GetString("payments.pay_in.seller.name"),
+					Edrpou:  d.Config.GetString("payments.pay_in.seller.edrpou"),
+					Iban:    d.Config.GetString("payments.pay_in.seller.iban"),
+					Bank:    d.Config.GetString("payments.pay_in.seller.bank"),
+					Address: d.Config.GetString("payments.pay_in.seller.address"),
+				},
+			}),
+		}, nil
+	})
+}
+
+type PayInSeller struct {
+	Name    string
+	Edrpou  string
+	Iban    string
+	Bank    string
+	Address string
+}
+
+type PayInConfig struct {
+	// InvoiceUrlMask is storefront url of the invoice document with %s for payment transaction id,
+	// the api invoice route requires platform auth
+	InvoiceUrlMask string
+	ValidDays      int
+	Seller         PayInSeller
+}
+
+func NewPayInStrategy(r checkout.IInvoiceRepository, cfg PayInConfig) *PayInStrategy {
+
+	tpl := template.Must(template.New("invoice").Parse(payInInvoiceTemplate))
+
+	return &PayInStrategy{r, tpl, cfg}
+}
+
+// PayInStrategy issues the invoice for the bank transfer of a company, the payment stays pending
+// till the operator confirms the transfer
+type PayInStrategy struct {
+	repository checkout.IInvoiceRepository
+	template   *template.Template
+	config     PayInConfig
+}
+
+func (s *PayInStrategy) Init(ctx context.Context, order *entity.Order, payment *entity.Payment) (IInitPaymentStrategyResponse, error) {
+
+	number, err := s.repository.NextNumber()
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[pay in init]%v", err))
+	}
+
+	document, err := s.render(number, order, payment)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[pay in init][render invoice][%v]", err))
+	}
+
+	invoice := entity.CreateNewInvoiceByPayment(number, payment, document)
+
+	if err := s.repository.Create(ctx, invoice); err != nil {
+		return nil, errors.New(fmt.Sprintf("[pay in init]%v", err))
+	}
+
+	if err := payment.UpdateStatus(entity.PaymentStatusPending); err != nil {
+		return nil, errors.New(fmt.Sprintf("[pay in init][%v]", err))
+	}
+
+	return NewIniPaymentStrategyResponse(entity.PaymentInitActionRedirect, fmt.Sprintf(s.config.InvoiceUrlMask, payment.GetTransactionId()), providerPayIn), nil
+}
+
+type payInInvoiceItem struct {
+	Number   int
+	Name     string
+	Quantity int
+	Price    string
+	Total    string
+}
+
+type payInInvoice struct {
+	Number     int
+	Date       string
+	ValidUntil string
+	OrderId    int
+	Seller     PayInSeller
+	Company    string
+	Edrpou     string
+	Email      string
+	Items      []payInInvoiceItem
+	Total      string
+	Currency   string
+}
+
+func (s *PayInStrategy) render(number int, order *entity.Order, payment *entity.Payment) (string, error) {
+
+	now := time.Now()
+	extra := order.GetPayment().GetExtra()
+
+	data := payInInvoice{
+		Number:     number,
+		Date:       now.Format("02.01.2006"),
+		ValidUntil: now.AddDate(0, 0, s.config.ValidDays).Format("02.01.2006"),
+		OrderId:    order.GetId(),
+		Seller:     s.config.Seller,
+		Company:    extra.GetCompany(),
+		Edrpou:     extra.GetEdrpou(),
+		Email:      extra.GetEmail(),
+		Items:      make([]payInInvoiceItem, 0, len(order.GetItems())),
+		Total:      payment.GetPrice().CentToCurrency(),
+		Currency:   payment.GetPrice().GetCurrency().GetName(),
+	}
+
+	// item prices are in the base currency, invoice lines are in the payment one like the total
+	currency := order.GetCurrency()
+
+	for _, v := range order.GetItems() {
+		for _, part := range v.GetDiscountedParts() {
+			cents := currency.FromBase(part.Price)
+			price := entity.NewPrice(cents, 0, 0, &currency)
+			total := entity.NewPrice(cents*part.Quantity, 0, 0, &currency)
+
+			data.Items = append(data.Items, payInInvoiceItem{
+				Number:   len(data.Items) + 1,
+				Name:     v.GetProduct().Name,
+				Quantity: part.Quantity,
+				Price:    price.CentToCurrency(),
+				Total:    total.CentToCurrency(),
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+
+	if err := s.template.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+const payInInvoiceTemplate = `<!DOCTYPE html>
+<html lang="uk">
+<head>
+<meta charset="utf-8">
+<title>Рахунок на оплату № {{.Number}}</title>
+<style>
+body { font-family: Arial, sans-serif; font-size: 14px; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #000; padding: 4px 6px; }
+td.num { text-align: right; }
+</style>
+</head>
+<body>
+<h2>Рахунок на оплату № {{.Number}} від {{.Date}}</h2>
+<p>
+<b>Постачальник:</b> {{.Seller.Name}}, ЄДРПОУ {{.Seller.Edrpou}}<br>
+IBAN {{.Seller.Iban}}, {{.Seller.Bank}}<br>
+{{.Seller.Address}}
+</p>
+<p>
+<b>Покупець:</b> {{.Company}}, ЄДРПОУ {{.Edrpou}}<br>
+{{.Email}}
+</p>
+<p><b>Замовлення:</b> № {{.OrderId}}</p>
+<table>
+<tr><th>№</th><th>Товар</th><th>Кількість</th><th>Ціна, {{.Currency}}</th><th>Сума, {{.Currency}}</th></tr>
+{{range .Items}}<tr><td>{{.Number}}</td><td>{{.Name}}</td><td class="num">{{.Quantity}}</td><td class="num">{{.Price}}</td><td class="num">{{.Total}}</td></tr>
+{{end}}<tr><td colspan="4"><b>Всього до сплати</b></td><td class="num"><b>{{.Total}}</b></td></tr>
+</table>
+<p>Призначення платежу: оплата за рахунком № {{.Number}} від {{.Date}}</p>
+<p>Рахунок дійсний до {{.ValidUntil}}</p>
+</body>
+</html>
+`
@@ -0,0 +1,29 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wowucco/G3/internal/entity"
+)
+
+func TestPayInStrategy_render(t *testing.T) {
+	s := NewPayInStrategy(nil, PayInConfig{ValidDays: 3})
+
+	order := entity.NewOrder(1, 0, "", false, 20100, nil, nil, nil, entity.NewOrderPayment(entity.PaymentStatusNew, nil, nil, "", "", "", 0), []*entity.OrderProduct{
+		entity.NewOrderProduct(2, 10050, entity.SimpleProduct{ID: 1, Name: "product"}),
+	})
+
+	document, err := s.render(1, order, entity.CreateNewPaymentByOrder(1, order))
+	assert.NoError(t, err, "t1")
+	assert.Contains(t, document, `<td class="num">100.50</td><td class="num">201.00</td>`, "t2")
+
+	usd := entity.NewOrder(2, 0, "", false, 735, entity.NewCurrencySnapshot("USD", 27.35), nil, nil, entity.NewOrderPayment(entity.PaymentStatusNew, nil, nil, "", "", "", 0), []*entity.OrderProduct{
+		entity.NewOrderProduct(2, 10050, entity.SimpleProduct{ID: 1, Name: "product"}),
+	})
+
+	document, err = s.render(2, usd, entity.CreateNewPaymentByOrder(2, usd))
+	assert.NoError(t, err, "t3")
+	assert.Contains(t, document, `<td class="num">3.67</td><td class="num">7.34</td>`, "t4")
+	assert.Contains(t, document, "Ціна, USD", "t5")
+}
@@ -0,0 +1,212 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"github.com/wowucco/G3/internal/checkout"
+	"github.com/wowucco/G3/internal/entity"
+	"sort"
+	"sync"
+)
+
+type Capability string
+
+const CapabilityInit Capability = "init"
+const CapabilityAccept Capability = "accept"
+const CapabilityCallback Capability = "callback"
+const CapabilityRefund Capability = "refund"
+const CapabilityCancel Capability = "cancel"
+const CapabilityStatus Capability = "status"
+const CapabilityInstallment Capability = "installment"
+
+// Provider is a payment gateway registered in PaymentContext
+type Provider struct {
+	Name string
+	// Methods are slugs of payment methods initialized by the provider
+	Methods      []string
+	Capabilities []Capability
+	// Currencies are ISO codes of currencies the provider accepts besides the base one
+	Currencies []string
+	// Strategy implements the interface of every declared capability, e.g. IRefundStrategy for CapabilityRefund
+	Strategy interface{}
+}
+
+func (p *Provider) Has(c Capability) bool {
+	for _, v := range p.Capabilities {
+		if v == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Accepts reports whether payments in the currency can be made with the provider
+func (p *Provider) Accepts(iso string) bool {
+	if entity.IsBaseISO(iso) {
+		return true
+	}
+
+	for _, v := range p.Currencies {
+		if v == iso {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IProviderConfig is a source of provider settings, viper satisfies it
+type IProviderConfig interface {
+	GetString(key string) string
+	GetInt(key string) int
+	GetFloat64(key string) float64
+}
+
+type ProviderDeps struct {
+	Repository        checkout.IPaymentRepository
+	InvoiceRepository checkout.IInvoiceRepository
+	Config            IProviderConfig
+}
+
+type ProviderFactory func(deps ProviderDeps) (*Provider, error)
+
+var (
+	factoriesMu sync.Mutex
+	factories   = make(map[string]ProviderFactory)
+)
+
+// RegisterProvider makes the gateway available for NewRegistryFromFactories.
+// It is meant to be called from init of the file implementing the gateway strategy.
+func RegisterProvider(name string, f ProviderFactory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if _, exist := factories[name]; exist {
+		panic(fmt.Sprintf("payment provider %s is already registered", name))
+	}
+
+	factories[name] = f
+}
+
+func NewRegistry() *Registry {
+
+	return &Registry{
+		providers: make(map[string]*Provider),
+		methods:   make(map[string]*Provider),
+	}
+}
+
+// NewRegistryFromFactories builds registry with every provider registered by RegisterProvider
+func NewRegistryFromFactories(deps ProviderDeps) (*Registry, error) {
+
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	names := make([]string, 0, len(factories))
+
+	for name := range factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	r := NewRegistry()
+
+	for _, name := range names {
+		p, err := factories[name](deps)
+
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("[payment provider %s][%v]", name, err))
+		}
+
+		if err := r.Register(p); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+type Registry struct {
+	providers map[string]*Provider
+	methods   map[string]*Provider
+}
+
+// Register adds provider after checking its strategy implements every declared capability
+func (r *Registry) Register(p *Provider) error {
+
+	if _, exist := r.providers[p.Name]; exist {
+		return errors.New(fmt.Sprintf("[payment provider %s is already registered]", p.Name))
+	}
+
+	for _, c := range p.Capabilities {
+		if implements(p.Strategy, c) == false {
+			return errors.New(fmt.Sprintf("[payment provider %s doesn't implement %s capability]", p.Name, c))
+		}
+	}
+
+	for _, m := range p.Methods {
+		if e, exist := r.methods[m]; exist {
+			return errors.New(fmt.Sprintf("[payment method %s is already served by %s]", m, e.Name))
+		}
+	}
+
+	r.providers[p.Name] = p
+
+	for _, m := range p.Methods {
+		r.methods[m] = p
+	}
+
+	return nil
+}
+
+func (r *Registry) GetByName(name string) (*Provider, bool) {
+	p, exist := r.providers[name]
+	return p, exist
+}
+
+func (r *Registry) GetByMethod(slug string) (*Provider, bool) {
+	p, exist := r.methods[slug]
+	return p, exist
+}
+
+// GetNamesWith returns sorted names of providers declaring the capability
+func (r *Registry) GetNamesWith(c Capability) []string {
+
+	names := make([]string, 0, len(r.providers))
+
+	for name, p := range r.providers {
+		if p.Has(c) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func implements(s interface{}, c Capability) bool {
+
+	var ok bool
+
+	switch c {
+	case CapabilityInit:
+		_, ok = s.(IInitPaymentStrategy)
+	case CapabilityAccept:
+		_, ok = s.(IAcceptHoldenStrategy)
+	case CapabilityCallback:
+		_, ok = s.(IProviderCallbackStrategy)
+	case CapabilityRefund:
+		_, ok = s.(IRefundStrategy)
+	case CapabilityCancel:
+		_, ok = s.(ICancelHoldenStrategy)
+	case CapabilityStatus:
+		_, ok = s.(IPaymentStatusStrategy)
+	case CapabilityInstallment:
+		_, ok = s.(IInstallmentStrategy)
+	}
+
+	return ok
+}
@@ -0,0 +1,76 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wowucco/G3/internal/entity"
+)
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+
+	err := r.Register(&Provider{
+		Name:         "none_refund",
+		Capabilities: []Capability{CapabilityInit, CapabilityRefund},
+		Strategy:     NewDefaultStrategy(nil),
+	})
+	assert.Error(t, err, "t1")
+
+	err = r.Register(&Provider{
+		Name:         "p2p",
+		Methods:      []string{entity.PaymentMethodP2P},
+		Capabilities: []Capability{CapabilityInit, CapabilityRefund, CapabilityStatus},
+		Strategy:     NewP2PStrategy(nil, nil),
+	})
+	assert.NoError(t, err, "t2")
+
+	err = r.Register(&Provider{
+		Name:         "p2p_copy",
+		Methods:      []string{entity.PaymentMethodP2P},
+		Capabilities: []Capability{CapabilityInit},
+		Strategy:     NewP2PStrategy(nil, nil),
+	})
+	assert.Error(t, err, "t3")
+
+	c := NewPaymentContext(r, NewDefaultStrategy(nil))
+
+	_, err = c.GetRefundPaymentStrategy("p2p")
+	assert.NoError(t, err, "t4")
+
+	_, err = c.GetAcceptHoldenPaymentStrategy("p2p")
+	assert.Error(t, err, "t5")
+
+	_, err = c.GetRefundPaymentStrategy(providerNone)
+	assert.Error(t, err, "t6")
+
+	assert.IsType(t, &DefaultStrategy{}, c.GetInitPaymentStrategy(entity.NewPaymentMethod(0, "cash", "cash")), "t7")
+	assert.IsType(t, &P2PStrategy{}, c.GetInitPaymentStrategy(entity.NewPaymentMethod(0, "p2p", entity.PaymentMethodP2P)), "t8")
+
+	assert.Equal(t, []string{"p2p"}, c.GetStatusProviders(), "t9")
+	assert.Equal(t, []string{}, r.GetNamesWith(CapabilityCancel), "t10")
+}
+
+func TestPaymentContext_IsCurrencySupported(t *testing.T) {
+	r := NewRegistry()
+
+	err := r.Register(&Provider{
+		Name:         "p2p",
+		Methods:      []string{entity.PaymentMethodP2P},
+		Capabilities: []Capability{CapabilityInit},
+		Currencies:   []string{"USD"},
+		Strategy:     NewP2PStrategy(nil, nil),
+	})
+	assert.NoError(t, err)
+
+	c := NewPaymentContext(r, NewDefaultStrategy(nil))
+	p2p := entity.NewPaymentMethod(0, "p2p", entity.PaymentMethodP2P)
+	cash := entity.NewPaymentMethod(0, "cash", entity.PaymentMethodCash)
+
+	assert.True(t, c.IsCurrencySupported(p2p, "USD"), "t1")
+	assert.True(t, c.IsCurrencySupported(p2p, "UAH"), "t2")
+	assert.True(t, c.IsCurrencySupported(p2p, ""), "t3")
+	assert.False(t, c.IsCurrencySupported(p2p, "EUR"), "t4")
+	assert.True(t, c.IsCurrencySupported(cash, "UAH"), "t5")
+	assert.False(t, c.IsCurrencySupported(cash, "USD"), "t6")
+}
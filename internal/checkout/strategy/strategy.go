@@ -27,6 +27,18 @@ type IProcessingCallbackPaymentStrategyResponse interface {
 	Skip() bool
 }
 
+type ICancelHoldenPaymentStrategyResponse interface {
+	GetStatus() int
+	GetDescription() string
+	GetData() map[string]interface{}
+}
+
+type IRefundPaymentStrategyResponse interface {
+	GetStatus() int
+	GetDescription() string
+	GetData() map[string]interface{}
+}
+
 type IInitPaymentStrategy interface {
 	Init(ctx context.Context, order *entity.Order, payment *entity.Payment) (IInitPaymentStrategyResponse, error)
 }
@@ -35,56 +47,151 @@ type IAcceptHoldenStrategy interface {
 	Accept(ctx context.Context, order *entity.Order, payment *entity.Payment) (IAcceptHoldenPaymentStrategyResponse, error)
 }
 
+type ICancelHoldenStrategy interface {
+	Cancel(ctx context.Context, order *entity.Order, payment *entity.Payment) (ICancelHoldenPaymentStrategyResponse, error)
+}
+
+// IPaymentStatusStrategy asks provider for the current payment state, e.g. when callback was lost
+type IPaymentStatusStrategy interface {
+	Status(ctx context.Context, payment *entity.Payment) (IProcessingCallbackPaymentStrategyResponse, error)
+}
+
+type IRefundStrategy interface {
+	Refund(ctx context.Context, order *entity.Order, payment *entity.Payment, refund *entity.Refund) (IRefundPaymentStrategyResponse, error)
+}
+
+// IInstallmentStrategy calculates monthly payments before the order is created
+type IInstallmentStrategy interface {
+	Installments(ctx context.Context, amount int, installmentType string) ([]*entity.Installment, error)
+}
+
 type IProviderCallbackStrategy interface {
 	IsValidSignature(ctx *gin.Context) bool
 	GetTransactionId(ctx *gin.Context) string
 	ProcessingCallback(ctx *gin.Context) (IProcessingCallbackPaymentStrategyResponse, error)
 }
 
-func NewPaymentContext(p2pStrategy *P2PStrategy, partsPayStrategy *PartsPayStrategy, defaultStrategy *DefaultStrategy) *PaymentContext {
+func NewPaymentContext(registry *Registry, defaultStrategy *DefaultStrategy) *PaymentContext {
 
-	return &PaymentContext{p2pStrategy, partsPayStrategy, defaultStrategy}
+	return &PaymentContext{registry, defaultStrategy}
 }
 
 type PaymentContext struct {
 	// todo LOGGER
-	p2pStrategy      *P2PStrategy
-	partsPayStrategy *PartsPayStrategy
-	defaultStrategy  *DefaultStrategy
+	registry        *Registry
+	defaultStrategy *DefaultStrategy
 }
 
 func (c *PaymentContext) GetInitPaymentStrategy(method *entity.PaymentMethod) IInitPaymentStrategy {
 
-	switch method.GetSlug() {
-	case entity.PaymentMethodP2P:
-		return c.p2pStrategy
-	case entity.PaymentMethodPartsPay:
-		return c.partsPayStrategy
-	default:
-		return c.defaultStrategy
+	if p, exist := c.registry.GetByMethod(method.GetSlug()); exist && p.Has(CapabilityInit) {
+		return p.Strategy.(IInitPaymentStrategy)
 	}
+
+	return c.defaultStrategy
+}
+
+// IsCurrencySupported reports whether the method can be paid in the currency, methods without provider
+// are paid in the base currency only
+func (c *PaymentContext) IsCurrencySupported(method *entity.PaymentMethod, iso string) bool {
+
+	if p, exist := c.registry.GetByMethod(method.GetSlug()); exist {
+		return p.Accepts(iso)
+	}
+
+	return entity.IsBaseISO(iso)
+}
+
+// IsProviderPayment reports whether the payment was initialized by a registered provider,
+// payments of the default strategy are handled by operators only
+func (c *PaymentContext) IsProviderPayment(provider string) bool {
+
+	_, exist := c.registry.GetByName(provider)
+
+	return exist
 }
 
 func (c *PaymentContext) GetAcceptHoldenPaymentStrategy(provider string) (IAcceptHoldenStrategy, error) {
-	switch provider {
-	case providerLiqpay:
-		return c.p2pStrategy, nil
-	case providerPrivatPartsPay:
-		return c.partsPayStrategy, nil
-	default:
-		return nil, errors.New(fmt.Sprintf("provider %s don't have accept interface", provider))
+
+	s, err := c.getStrategy(provider, CapabilityAccept)
+
+	if err != nil {
+		return nil, err
 	}
+
+	return s.(IAcceptHoldenStrategy), nil
 }
 
 func (c *PaymentContext) GetProviderCallbackPaymentStrategy(provider string) (IProviderCallbackStrategy, error) {
-	switch provider {
-	case providerLiqpay:
-		return c.p2pStrategy, nil
-	case providerPrivatPartsPay:
-		return c.partsPayStrategy, nil
-	default:
-		return nil, errors.New(fmt.Sprintf("provider %s don't have accept interface", provider))
+
+	s, err := c.getStrategy(provider, CapabilityCallback)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.(IProviderCallbackStrategy), nil
+}
+
+func (c *PaymentContext) GetCancelHoldenPaymentStrategy(provider string) (ICancelHoldenStrategy, error) {
+
+	s, err := c.getStrategy(provider, CapabilityCancel)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.(ICancelHoldenStrategy), nil
+}
+
+func (c *PaymentContext) GetPaymentStatusStrategy(provider string) (IPaymentStatusStrategy, error) {
+
+	s, err := c.getStrategy(provider, CapabilityStatus)
+
+	if err != nil {
+		return nil, err
 	}
+
+	return s.(IPaymentStatusStrategy), nil
+}
+
+// GetStatusProviders returns names of providers which can be asked for the payment state
+func (c *PaymentContext) GetStatusProviders() []string {
+
+	return c.registry.GetNamesWith(CapabilityStatus)
+}
+
+func (c *PaymentContext) GetRefundPaymentStrategy(provider string) (IRefundStrategy, error) {
+
+	s, err := c.getStrategy(provider, CapabilityRefund)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.(IRefundStrategy), nil
+}
+
+func (c *PaymentContext) GetInstallmentStrategy(method string) (IInstallmentStrategy, error) {
+
+	p, exist := c.registry.GetByMethod(method)
+
+	if exist == false || p.Has(CapabilityInstallment) == false {
+		return nil, errors.New(fmt.Sprintf("payment method %s don't have %s interface", method, CapabilityInstallment))
+	}
+
+	return p.Strategy.(IInstallmentStrategy), nil
+}
+
+func (c *PaymentContext) getStrategy(provider string, capability Capability) (interface{}, error) {
+
+	p, exist := c.registry.GetByName(provider)
+
+	if exist == false || p.Has(capability) == false {
+		return nil, errors.New(fmt.Sprintf("provider %s don't have %s interface", provider, capability))
+	}
+
+	return p.Strategy, nil
 }
 
 func NewIniPaymentStrategyResponse(action, resource, provider string) IInitPaymentStrategyResponse {
@@ -157,3 +264,43 @@ func (r *ProcessingCallbackPaymentStrategyResponse) GetData() map[string]interfa
 func (r *ProcessingCallbackPaymentStrategyResponse) Skip() bool {
 	return r.skip
 }
+
+type CancelHoldenPaymentStrategyResponse struct {
+	status int
+	desc   string
+	stack  map[string]interface{}
+}
+
+func NewCancelHoldenPaymentStrategyResponse(status int, desc string, stack map[string]interface{}) ICancelHoldenPaymentStrategyResponse {
+
+	return &CancelHoldenPaymentStrategyResponse{status, desc, stack}
+}
+func (r *CancelHoldenPaymentStrategyResponse) GetStatus() int {
+	return r.status
+}
+func (r *CancelHoldenPaymentStrategyResponse) GetDescription() string {
+	return r.desc
+}
+func (r *CancelHoldenPaymentStrategyResponse) GetData() map[string]interface{} {
+	return r.stack
+}
+
+type RefundPaymentStrategyResponse struct {
+	status int
+	desc   string
+	stack  map[string]interface{}
+}
+
+func NewRefundPaymentStrategyResponse(status int, desc string, stack map[string]interface{}) IRefundPaymentStrategyResponse {
+
+	return &RefundPaymentStrategyResponse{status, desc, stack}
+}
+func (r *RefundPaymentStrategyResponse) GetStatus() int {
+	return r.status
+}
+func (r *RefundPaymentStrategyResponse) GetDescription() string {
+	return r.desc
+}
+func (r *RefundPaymentStrategyResponse) GetData() map[string]interface{} {
+	return r.stack
+}
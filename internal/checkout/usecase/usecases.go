@@ -1,18 +1,25 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/wowucco/G3/internal/checkout"
+	"github.com/wowucco/G3/internal/checkout/promo"
+	"github.com/wowucco/G3/internal/checkout/stock"
 	"github.com/wowucco/G3/internal/checkout/strategy"
 	"github.com/wowucco/G3/internal/delivery"
 	"github.com/wowucco/G3/internal/entity"
 	"github.com/wowucco/G3/internal/product"
 	"github.com/wowucco/G3/pkg/notification"
+	"io/ioutil"
 	"log"
-	"sync"
+	"net/http"
+	"strings"
+	"time"
 )
 
 func NewOrderUseCase(
@@ -20,239 +27,1542 @@ func NewOrderUseCase(
 	p product.ReadRepository,
 	d delivery.DeliveryReadRepository,
 	pr checkout.IPaymentRepository,
+	rr checkout.IRefundRepository,
+	er checkout.IPaymentEventRepository,
+	ir checkout.IInvoiceRepository,
+	uow checkout.IUnitOfWork,
+	cr checkout.IOrderChangeRepository,
+	sr checkout.IDeliverySlotRepository,
+	s *stock.Service,
+	ps *promo.Service,
+	ws delivery.IWaybillService,
+	ts delivery.ITrackingService,
 	n *notification.Service,
 	pc *strategy.PaymentContext,
 ) *OrderUserCase {
 
-	return &OrderUserCase{orderRepository: o, productRepository: p, deliveryRepository: d, notify: n, paymentContext: pc, paymentRepository: pr}
+	return &OrderUserCase{orderRepository: o, productRepository: p, deliveryRepository: d, notify: n, paymentContext: pc, paymentRepository: pr, refundRepository: rr, paymentEventRepository: er, invoiceRepository: ir, unitOfWork: uow, orderChangeRepository: cr, deliverySlotRepository: sr, stock: s, promo: ps, waybill: ws, tracking: ts}
 }
 
 type OrderUserCase struct {
-	orderRepository    checkout.IOrderRepository
-	productRepository  product.ReadRepository
-	deliveryRepository delivery.DeliveryReadRepository
-	paymentRepository  checkout.IPaymentRepository
+	orderRepository        checkout.IOrderRepository
+	productRepository      product.ReadRepository
+	deliveryRepository     delivery.DeliveryReadRepository
+	paymentRepository      checkout.IPaymentRepository
+	refundRepository       checkout.IRefundRepository
+	paymentEventRepository checkout.IPaymentEventRepository
+	invoiceRepository      checkout.IInvoiceRepository
+	unitOfWork             checkout.IUnitOfWork
+	orderChangeRepository  checkout.IOrderChangeRepository
+	deliverySlotRepository checkout.IDeliverySlotRepository
+
+	stock          *stock.Service
+	promo          *promo.Service
+	waybill        delivery.IWaybillService
+	tracking       delivery.ITrackingService
+	notify         *notification.Service
+	paymentContext *strategy.PaymentContext
+}
+
+func (o *OrderUserCase) Create(ctx context.Context, form checkout.CreateOrderForm) (*entity.Order, error) {
+
+	oProducts, products, err := o.orderProducts(ctx, form)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := o.stock.Check(form.GetOrder().GetOrderItems(), oProducts); err != nil {
+		log.Printf("[Create order]%v", err)
+		return nil, err
+	}
+
+	cost, err := o.orderCost(form, oProducts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var discount *entity.OrderDiscount
+
+	if form.GetOrder().GetPromoCode() != "" {
+		discount, err = o.promo.Apply(ctx, form.GetOrder().GetPromoCode(), cost, oProducts, promoLines(oProducts, products))
+
+		if err != nil {
+			return nil, err
+		}
+
+		cost -= discount.GetAmount()
+	}
+
+	dMethod, err := o.deliveryRepository.GetDeliveryMethodBySlug(form.GetDelivery().GetMethod())
+
+	if err != nil {
+		return nil, err
+	}
+
+	pMethod, err := o.deliveryRepository.GetPaymentMethodBySlug(form.GetPayment().GetMethod())
+
+	if err != nil {
+		return nil, err
+	}
+
+	groupIds := make([]int, len(products))
+
+	for k, v := range products {
+		groupIds[k] = v.Group.ID
+	}
+
+	cart := entity.NewPaymentCart(cost, groupIds, 0)
+
+	if pMethod.GetSlug() == entity.PaymentMethodPartsPay {
+		cart.Parts = form.GetPayment().GetPayPartsPay()
+	}
+
+	if reason := pMethod.Rules.Check(cart); reason != "" {
+		return nil, &checkout.PaymentMethodError{Method: pMethod.GetSlug(), Reason: reason}
+	}
+
+	// parts count is checked against the provider range for the order amount, zero parts can't be paid
+	if pMethod.GetSlug() == entity.PaymentMethodPartsPay && (cart.Parts <= 0 || o.isPartsCountAvailable(ctx, cost, cart.Parts) == false) {
+		return nil, &checkout.PaymentMethodError{Method: pMethod.GetSlug(), Reason: entity.PaymentRuleReasonParts}
+	}
+
+	currency, err := o.orderCurrency(ctx, pMethod, form.GetPayment().GetCurrency())
+
+	if err != nil {
+		return nil, err
+	}
+
+	warehouse := entity.NewOrderDeliveryWarehouse(form.GetDelivery().GetCity().GetCode(), form.GetDelivery().GetCity().GetName(), form.GetDelivery().GetAddress().GetCode(), form.GetDelivery().GetAddress().GetName(), form.GetDelivery().IsCustomAddress())
+
+	var (
+		courier      *entity.OrderDeliveryCourier
+		slot         *entity.OrderDeliverySlot
+		slotCapacity int
+	)
+
+	if c := form.GetDelivery().GetCourier(); c != nil && dMethod.GetSlug() == entity.DeliveryMethodCourier {
+		courier = entity.NewOrderDeliveryCourier(c.GetStreetCode(), c.GetStreet(), c.GetHouse(), c.GetApartment())
+		warehouse = entity.NewOrderDeliveryWarehouse(form.GetDelivery().GetCity().GetCode(), form.GetDelivery().GetCity().GetName(), c.GetStreetCode(), courier.GetFullAddress(), true)
+
+		if sf := form.GetDelivery().GetSlot(); sf != nil {
+			slot, slotCapacity, err = o.courierSlot(ctx, form.GetDelivery().GetCity().GetCode(), sf)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	builder := &checkout.CreateOrderBuilder{
+		DeliveryMethod: dMethod,
+		PaymentMethod:  pMethod,
+		Products:       oProducts,
+		Warehouse:      warehouse,
+		Courier:        courier,
+		Slot:           slot,
+		Customer:       entity.NewOrderCustomer(form.GetClient().GetFio(), form.GetClient().GetPhone()),
+		Cost:           currency.FromBase(cost),
+		Currency:       currency,
+		Discount:       discount,
+		Comment:        form.GetComment(),
+		DoNotCall:      form.GetDoNotCall(),
+		PayInCompany:   form.GetPayment().GetPayInCompany(),
+		PayInEdrpou:    form.GetPayment().GetPayInEdrpou(),
+		PayInEmail:     form.GetPayment().GetPayInEmail(),
+		PayPartsPay:    form.GetPayment().GetPayPartsPay(),
+	}
+
+	var order *entity.Order
+
+	err = o.unitOfWork.Transaction(ctx, func(ctx context.Context) error {
+
+		created, err := o.orderRepository.Create(ctx, builder)
+
+		if err != nil {
+			return err
+		}
+
+		if err := o.stock.Reserve(ctx, created); err != nil {
+			return err
+		}
+
+		if discount != nil {
+			if err := o.promo.Redeem(ctx, discount); err != nil {
+				return err
+			}
+		}
+
+		if slot != nil {
+			if err := o.deliverySlotRepository.Book(ctx, created.GetId(), form.GetDelivery().GetCity().GetCode(), *slot, slotCapacity); err != nil {
+				return err
+			}
+		}
+
+		order = created
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	o.notify.OrderCreated(order)
+
+	return order, nil
+}
+
+// courierSlot finds the chosen slot among the city ones, capacity is checked again while booking
+func (o *OrderUserCase) courierSlot(ctx context.Context, cityId string, form checkout.DeliverySlotForm) (*entity.OrderDeliverySlot, int, error) {
+
+	date, err := time.ParseInLocation(entity.DeliverySlotDateLayout, form.GetDate(), time.Local)
+
+	if err != nil {
+		return nil, 0, &checkout.DeliverySlotError{Date: form.GetDate(), Slot: form.GetId(), Reason: checkout.DeliverySlotReasonUnknown}
+	}
+
+	y, m, d := time.Now().Date()
+
+	if date.Before(time.Date(y, m, d, 0, 0, 0, 0, time.Local)) {
+		return nil, 0, &checkout.DeliverySlotError{Date: form.GetDate(), Slot: form.GetId(), Reason: checkout.DeliverySlotReasonPast}
+	}
+
+	slots, err := o.deliveryRepository.GetCourierTimeSlots(ctx, cityId, form.GetDate())
+
+	if err != nil {
+		return nil, 0, errors.New(fmt.Sprintf("[courier slot][city %s][%v]", cityId, err))
+	}
+
+	for _, s := range slots {
+		if s.GetId() != form.GetId() {
+			continue
+		}
+
+		// today's slot can't be booked once it's over
+		if s.IsOverAt(date, time.Now()) {
+			return nil, 0, &checkout.DeliverySlotError{Date: form.GetDate(), Slot: form.GetId(), Reason: checkout.DeliverySlotReasonPast}
+		}
+
+		if s.GetAvailable() < 1 {
+			return nil, 0, &checkout.DeliverySlotError{Date: form.GetDate(), Slot: form.GetId(), Reason: checkout.DeliverySlotReasonFull}
+		}
+
+		return entity.NewOrderDeliverySlot(form.GetDate(), s.GetId(), s.GetFrom(), s.GetTo()), s.GetCapacity(), nil
+	}
+
+	return nil, 0, &checkout.DeliverySlotError{Date: form.GetDate(), Slot: form.GetId(), Reason: checkout.DeliverySlotReasonUnknown}
+}
+
+func (o *OrderUserCase) OrderInfo(ctx context.Context, form checkout.OrderIdForm) (*entity.Order, error) {
+	return o.orderRepository.Get(ctx, form.GetOrderId())
+}
+
+func (o *OrderUserCase) Orders(ctx context.Context, form checkout.IOrdersFilterForm, offset, limit int) ([]*entity.Order, error) {
+	return o.orderRepository.Find(ctx, orderFilter(form), offset, limit)
+}
+
+func (o *OrderUserCase) OrdersCount(ctx context.Context, form checkout.IOrdersFilterForm) (int, error) {
+	return o.orderRepository.Count(ctx, orderFilter(form))
+}
+
+// phoneTailLength is the national number length, so +380..., 380... and 0... forms of one phone match
+const phoneTailLength = 9
+
+func orderFilter(form checkout.IOrdersFilterForm) checkout.OrderFilter {
+
+	var digits []rune
+
+	for _, r := range form.GetPhone() {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, r)
+		}
+	}
+
+	if len(digits) > phoneTailLength {
+		digits = digits[len(digits)-phoneTailLength:]
+	}
+
+	return checkout.OrderFilter{
+		Phone:          string(digits),
+		PaymentStatus:  form.GetPaymentStatus(),
+		DeliveryStatus: form.GetDeliveryStatus(),
+		PaymentMethod:  form.GetPaymentMethod(),
+		From:           form.GetDateFrom(),
+		To:             form.GetDateTo(),
+	}
+}
+
+// EditOrder applies operator changes to the order and records every changed part in the audit trail.
+// Items of the form replace order items, kept items keep their price, new ones are sold by the current price,
+// promo discount is calculated again for the new items. Orders sent to a payment provider can't be edited.
+func (o *OrderUserCase) EditOrder(ctx context.Context, form checkout.IEditOrderForm) (*entity.Order, error) {
+
+	var (
+		order    *entity.Order
+		products map[int]*entity.Product
+	)
+
+	if form.GetItems() != nil {
+		ids := make([]int, len(form.GetItems()))
+
+		for k, v := range form.GetItems() {
+			ids[k] = v.GetProductId()
+		}
+
+		found, err := o.productRepository.GetByIdsWithSequence(ctx, ids)
+
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("[error][edit order][products][%v]", err))
+		}
+
+		products = make(map[int]*entity.Product, len(found))
+
+		for _, v := range found {
+			products[v.ID] = v
+		}
+	}
+
+	err := o.unitOfWork.Transaction(ctx, func(ctx context.Context) error {
+
+		var (
+			err     error
+			changes []*entity.OrderChange
+		)
+
+		order, err = o.orderRepository.GetForUpdate(ctx, form.GetOrderId())
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("[order not found][%d][%v]", form.GetOrderId(), err))
+		}
+
+		if order.IsEditable() == false {
+			return &checkout.OrderNotEditableError{OrderId: order.GetId(), PaymentStatus: order.GetPayment().GetStatus()}
+		}
+
+		// provider already holds the amount or the parts agreement of the current items
+		last, err := o.paymentRepository.GetLastByOrder(ctx, order.GetId())
+
+		if err != nil && err != sql.ErrNoRows {
+			return errors.New(fmt.Sprintf("[last payment][%d][%v]", order.GetId(), err))
+		}
+
+		if last != nil && last.IsOpen() && o.paymentContext.IsProviderPayment(last.GetProvider()) {
+			return &checkout.OrderNotEditableError{OrderId: order.GetId(), PaymentStatus: last.GetStatus(), Provider: last.GetProvider()}
+		}
+
+		change := func(field, before, after string) {
+			if before != after {
+				changes = append(changes, entity.CreateNewOrderChange(order.GetId(), field, before, after, form.GetOperator(), form.GetComment()))
+			}
+		}
+
+		if c := form.GetClient(); c != nil {
+			before := customerSummary(order.GetCustomer())
+			order.ChangeCustomer(entity.NewOrderCustomer(c.GetFio(), c.GetPhone()))
+			change(entity.OrderChangeCustomer, before, customerSummary(order.GetCustomer()))
+		}
+
+		if d := form.GetDelivery(); d != nil {
+			before := warehouseSummary(order.GetDelivery().GetWarehouse())
+			warehouse := entity.NewOrderDeliveryWarehouse(d.GetCity().GetCode(), d.GetCity().GetName(), d.GetAddress().GetCode(), d.GetAddress().GetName(), d.IsCustomAddress())
+
+			if before != warehouseSummary(*warehouse) {
+				// courier slot of the previous address is given back to the city
+				if order.GetDelivery().GetSlot() != nil {
+					if err := o.deliverySlotRepository.Release(ctx, order.GetId()); err != nil {
+						return err
+					}
+				}
+
+				order.ChangeWarehouse(warehouse)
+				change(entity.OrderChangeWarehouse, before, warehouseSummary(order.GetDelivery().GetWarehouse()))
+			}
+		}
+
+		itemsChanged := false
+
+		if form.GetItems() != nil {
+			before := itemsSummary(order)
+
+			items, err := editedItems(order, form.GetItems(), products)
+
+			if err != nil {
+				return err
+			}
+
+			if discount := order.GetDiscount(); discount != nil {
+				var cost int
+
+				for _, v := range items {
+					total := v.GetTotal()
+					cost += total.GetInCent()
+				}
+
+				if _, err := o.promo.Reapply(ctx, discount, cost, items, editedPromoLines(items, products)); err != nil {
+					return err
+				}
+			}
+
+			order.ChangeItems(items)
+
+			after := itemsSummary(order)
+			itemsChanged = before != after
+			change(entity.OrderChangeItems, before, after)
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+
+		if err := o.orderRepository.Save(ctx, order); err != nil {
+			return errors.New(fmt.Sprintf("[save order][%d][%v]", order.GetId(), err))
+		}
+
+		if itemsChanged == true {
+			if err := o.orderRepository.SaveItems(ctx, order); err != nil {
+				return err
+			}
+
+			if err := o.stock.Update(ctx, order); err != nil {
+				return err
+			}
+		}
+
+		for _, v := range changes {
+			if err := o.orderChangeRepository.Create(ctx, v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (o *OrderUserCase) OrderChanges(ctx context.Context, form checkout.OrderIdForm) ([]*entity.OrderChange, error) {
+	return o.orderChangeRepository.Find(ctx, form.GetOrderId())
+}
+
+// UpdateDeliveryStatus moves the order delivery to the next status and notifies the customer when parcel is on the way
+func (o *OrderUserCase) UpdateDeliveryStatus(ctx context.Context, form checkout.IUpdateDeliveryStatusForm) (*entity.Order, error) {
+
+	var order *entity.Order
+
+	err := o.unitOfWork.Transaction(ctx, func(ctx context.Context) error {
+
+		var err error
+
+		order, err = o.orderRepository.GetForUpdate(ctx, form.GetOrderId())
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("[order not found][%d][%v]", form.GetOrderId(), err))
+		}
+
+		if form.GetTrackingNumber() != "" {
+			order.SetTrackingNumber(form.GetTrackingNumber())
+		}
+
+		if err := order.UpdateDeliveryStatus(form.GetStatus(), form.GetComment()); err != nil {
+			return err
+		}
+
+		return o.settleOrder(ctx, order)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	o.notify.DeliveryStatusUpdated(order)
+
+	return order, nil
+}
+
+// waybillDescriptionLength is the longest parcel description carrier accepts
+const waybillDescriptionLength = 100
+
+// waybillRequestTimeout is how long a started waybill request blocks the next ones, the carrier responds much faster,
+// so an older mark is left by a request that failed before saving the result
+const waybillRequestTimeout = 5 * time.Minute
+
+// CreateWaybill registers the shipment with the carrier and saves the tracking number on the order.
+// The carrier is called without holding the order lock, the request mark saved before the call keeps
+// concurrent requests from creating a second waybill.
+func (o *OrderUserCase) CreateWaybill(ctx context.Context, form checkout.ICreateWaybillForm) (*entity.Waybill, error) {
+
+	var (
+		order  *entity.Order
+		parcel *entity.Parcel
+	)
+
+	err := o.unitOfWork.Transaction(ctx, func(ctx context.Context) error {
+
+		var err error
+
+		order, err = o.orderRepository.GetForUpdate(ctx, form.GetOrderId())
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("[order not found][%d][%v]", form.GetOrderId(), err))
+		}
+
+		if order.GetDelivery().GetTrackingNumber() != "" {
+			return errors.New(fmt.Sprintf("[order %d already has waybill %s]", order.GetId(), order.GetDelivery().GetTrackingNumber()))
+		}
+
+		if at := order.GetDelivery().GetWaybillRequestedAt(); at > 0 && time.Since(time.Unix(at, 0)) < waybillRequestTimeout {
+			return errors.New(fmt.Sprintf("[order %d waybill is being created since %d]", order.GetId(), at))
+		}
+
+		parcel = waybillParcel(order, form)
+
+		order.SetWaybillRequest(time.Now().Unix())
+
+		return o.orderRepository.Save(ctx, order)
+	})
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[error][create waybill]%v", err))
+	}
+
+	waybill, err := o.waybill.Create(ctx, *order, parcel)
+
+	if err != nil {
+		// nothing was created, so the next request doesn't have to wait for the mark to expire
+		if err := o.saveWaybill(ctx, order.GetId(), nil); err != nil {
+			log.Printf("[error][create waybill][order %d][clear request]%v", order.GetId(), err)
+		}
+
+		return nil, errors.New(fmt.Sprintf("[error][create waybill]%v", err))
+	}
+
+	if err := o.saveWaybill(ctx, order.GetId(), waybill); err != nil {
+		return nil, errors.New(fmt.Sprintf("[error][create waybill][waybill %s ref %s isn't saved]%v", waybill.GetNumber(), waybill.GetRef(), err))
+	}
+
+	return waybill, nil
+}
+
+// saveWaybill stores the created waybill on the order or clears the request mark when waybill is nil
+func (o *OrderUserCase) saveWaybill(ctx context.Context, orderId int, waybill *entity.Waybill) error {
+
+	return o.unitOfWork.Transaction(ctx, func(ctx context.Context) error {
+
+		order, err := o.orderRepository.GetForUpdate(ctx, orderId)
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("[order not found][%d][%v]", orderId, err))
+		}
+
+		if waybill == nil {
+			order.SetWaybillRequest(0)
+		} else {
+			order.SetWaybill(waybill.GetRef(), waybill.GetNumber())
+		}
+
+		return o.orderRepository.Save(ctx, order)
+	})
+}
+
+// waybillParcel fills the parcel defaults, declared value is the order cost and description lists item names
+func waybillParcel(order *entity.Order, form checkout.ICreateWaybillForm) *entity.Parcel {
+
+	declared := form.GetDeclaredValue()
+
+	if declared == 0 {
+		cost := order.GetPrice()
+		declared = cost.GetInCent()
+	}
+
+	description := form.GetDescription()
+
+	if description == "" {
+		names := make([]string, len(order.GetItems()))
+
+		for k, v := range order.GetItems() {
+			names[k] = v.GetProduct().Name
+		}
+
+		description = strings.Join(names, ", ")
+	}
+
+	if d := []rune(description); len(d) > waybillDescriptionLength {
+		description = string(d[:waybillDescriptionLength])
+	}
+
+	seats := form.GetSeats()
+
+	if seats == 0 {
+		seats = 1
+	}
+
+	return entity.NewParcel(form.GetWeight(), seats, declared, description)
+}
+
+func (o *OrderUserCase) InitPayment(ctx context.Context, form checkout.InitPaymentForm) (checkout.IInitPaymentResponse, error) {
+
+	var (
+		order *entity.Order
+		p     *entity.Payment
+	)
+
+	id, err := o.paymentRepository.NextId()
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[payment init][next sequense][%v]", err))
+	}
+
+	err = o.unitOfWork.Transaction(ctx, func(ctx context.Context) error {
+
+		var err error
+
+		// order row is locked, so concurrent requests can't init two payments for one order
+		order, err = o.orderRepository.GetForUpdate(ctx, form.GetOrderId())
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("[Init payment][%v]", err))
+		}
+
+		if order.CanMakePayment() != true {
+			return errors.New(fmt.Sprintf("[Init payment][Can't init payment for order with payment status: %v]", order.GetPayment().GetStatus()))
+		}
+
+		p = entity.CreateNewPaymentByOrder(id, order)
+
+		// stock and promo code usage could be released by the previous failed payment
+		if err := o.stock.Reserve(ctx, order); err != nil {
+			return err
+		}
+
+		restored, err := o.promo.Restore(ctx, order)
+
+		if err != nil {
+			return err
+		}
+
+		if restored == true {
+			if err := o.orderRepository.Save(ctx, order); err != nil {
+				return errors.New(fmt.Sprintf("[order save][%d][%v]", order.GetId(), err))
+			}
+		}
+
+		if err := o.paymentRepository.Create(ctx, p); err != nil {
+			return errors.New(fmt.Sprintf("[create payment][%v]", err))
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[payment init]%v", err))
+	}
+
+	pMethod := order.GetPayment().GetMethod()
+
+	r, err := o.paymentContext.GetInitPaymentStrategy(&pMethod).Init(ctx, order, p)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[payment init][provider init]%v", err))
+	}
+
+	p.SetProvider(r.GetProviderName())
+
+	if p.HasEqualStatus(entity.PaymentStatusNew) {
+		err = o.unitOfWork.Transaction(ctx, func(ctx context.Context) error {
+			return o.paymentRepository.Save(ctx, p)
+		})
+	} else {
+		// strategy moved the payment forward, e.g. pay-in invoice waits for the bank transfer
+		if err := order.UpdatePaymentStatus(p.GetStatus(), fmt.Sprintf("Payment initialized by %s", r.GetProviderName())); err != nil {
+			return nil, errors.New(fmt.Sprintf("[payment init][order status][%v]", err))
+		}
+
+		err = o.savePaymentWithOrder(ctx, p, order)
+	}
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[payment init][update error][%v]", err))
+	}
+
+	o.notify.PaymentCreated(order, p)
+
+	return NewIniPaymentResponse(p, order, r.GetAction(), r.GetResource()), nil
+}
+
+// Invoice returns the document issued for pay-in payment
+func (o *OrderUserCase) Invoice(ctx context.Context, form checkout.IInvoiceForm) (*entity.Invoice, error) {
+
+	i, err := o.invoiceRepository.Get(ctx, form.GetTransactionId())
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[error][invoice][not found][%s][%v]", form.GetTransactionId(), err))
+	}
+
+	return i, nil
+}
+
+// Installments calculates parts pay monthly payments for the product price or cart total
+func (o *OrderUserCase) Installments(ctx context.Context, form checkout.IInstallmentsForm) ([]*entity.Installment, error) {
+
+	amount := form.GetAmount()
+
+	if form.GetProductId() != 0 {
+		products, err := o.productRepository.GetByIdsWithSequence(ctx, []int{form.GetProductId()})
+
+		if err != nil || len(products) == 0 {
+			return nil, errors.New(fmt.Sprintf("[error][installments][product not found][%d][%v]", form.GetProductId(), err))
+		}
+
+		amount = products[0].Price.GetBasePriceByQuantity(1)
+	}
+
+	s, err := o.paymentContext.GetInstallmentStrategy(entity.PaymentMethodPartsPay)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[error][installments][unresolved strategy][%v]", err))
+	}
+
+	types := []string{entity.InstallmentTypeParts, entity.InstallmentTypeInstant}
+
+	if form.GetType() != "" {
+		types = []string{form.GetType()}
+	}
+
+	var installments []*entity.Installment
+
+	for _, t := range types {
+		r, err := s.Installments(ctx, amount, t)
+
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("[error][installments]%v", err))
+		}
+
+		installments = append(installments, r...)
+	}
+
+	return installments, nil
+}
+
+// orderCurrency returns the currency with the current rate the order is paid in, it has to be accepted by
+// the provider of the payment method
+func (o *OrderUserCase) orderCurrency(ctx context.Context, method *entity.PaymentMethod, iso string) (*entity.Currency, error) {
+
+	if o.paymentContext.IsCurrencySupported(method, iso) == false {
+		return nil, &checkout.PaymentMethodError{Method: method.GetSlug(), Reason: entity.PaymentRuleReasonCurrency}
+	}
+
+	if entity.IsBaseISO(iso) {
+		return entity.DefaultCurrency(), nil
+	}
+
+	return o.orderRepository.GetCurrency(ctx, iso)
+}
+
+// isPartsCountAvailable checks parts count against provider settings, e.g. configured min and max parts
+func (o *OrderUserCase) isPartsCountAvailable(ctx context.Context, amount, parts int) bool {
+
+	s, err := o.paymentContext.GetInstallmentStrategy(entity.PaymentMethodPartsPay)
+
+	if err != nil {
+		log.Printf("[error][parts count][unresolved strategy][%v]", err)
+		return false
+	}
+
+	installments, err := s.Installments(ctx, amount, entity.InstallmentTypeParts)
+
+	if err != nil {
+		log.Printf("[error][parts count]%v", err)
+		return false
+	}
+
+	for _, v := range installments {
+		if v.GetParts() == parts {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AcceptHoldenPayment captures the hold of the payment waiting confirmation. Like refund, the provider is called
+// without the payment lock and its answer is applied under the lock again.
+func (o *OrderUserCase) AcceptHoldenPayment(ctx context.Context, form checkout.IAcceptHoldenPaymentForm) error {
+
+	var s strategy.IAcceptHoldenStrategy
+
+	p, order, err := o.lockPayment(ctx, form.GetTransactionId(), func(ctx context.Context, p *entity.Payment, order *entity.Order) error {
+
+		if p.HasEqualStatus(entity.PaymentStatusWaitingConfirmation) == false {
+			return errors.New(fmt.Sprintf("[payment is not holden, status: %v][%s]", p.GetStatus(), p.GetTransactionId()))
+		}
+
+		var err error
+
+		s, err = o.paymentContext.GetAcceptHoldenPaymentStrategy(p.GetProvider())
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("[unresolved strategy][%s][%v]", p.GetTransactionId(), err))
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[error][accept holden]%v", err))
+	}
+
+	r, err := s.Accept(ctx, order, p)
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[error][accept holden][provider accept][%s]%v", p.GetTransactionId(), err))
+	}
+
+	p, order, applied, err := o.applyHoldenStatus(ctx, form.GetTransactionId(), r.GetStatus(), r.GetDescription())
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[error][accept holden]%v", err))
+	}
+
+	if applied == true {
+		o.notify.PaymentStatusUpdated(order, p)
+	}
+
+	return nil
+}
+
+// CancelHoldenPayment voids the hold of the payment waiting confirmation, e.g. when the order is rejected by operator
+func (o *OrderUserCase) CancelHoldenPayment(ctx context.Context, form checkout.ICancelHoldenPaymentForm) error {
+
+	var s strategy.ICancelHoldenStrategy
+
+	p, order, err := o.lockPayment(ctx, form.GetTransactionId(), func(ctx context.Context, p *entity.Payment, order *entity.Order) error {
+
+		if p.HasEqualStatus(entity.PaymentStatusWaitingConfirmation) == false {
+			return errors.New(fmt.Sprintf("[payment is not holden, status: %v][%s]", p.GetStatus(), p.GetTransactionId()))
+		}
+
+		var err error
+
+		s, err = o.paymentContext.GetCancelHoldenPaymentStrategy(p.GetProvider())
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("[unresolved strategy][%s][%v]", p.GetTransactionId(), err))
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[error][cancel holden]%v", err))
+	}
+
+	r, err := s.Cancel(ctx, order, p)
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[error][cancel holden][provider cancel][%s]%v", p.GetTransactionId(), err))
+	}
+
+	desc := r.GetDescription()
+
+	if form.GetComment() != "" {
+		desc = fmt.Sprintf("%s: %s", desc, form.GetComment())
+	}
+
+	p, order, applied, err := o.applyHoldenStatus(ctx, form.GetTransactionId(), r.GetStatus(), desc)
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[error][cancel holden]%v", err))
+	}
+
+	if applied == true {
+		o.notify.PaymentStatusUpdated(order, p)
+	}
+
+	return nil
+}
+
+// applyHoldenStatus moves the payment reloaded under lock to the status provider returned for the hold.
+// Returns false when the payment already has the status, e.g. the callback was processed while provider responded.
+func (o *OrderUserCase) applyHoldenStatus(ctx context.Context, transactionId string, status int, description string) (*entity.Payment, *entity.Order, bool, error) {
+
+	applied := false
+
+	p, order, err := o.lockPayment(ctx, transactionId, func(ctx context.Context, p *entity.Payment, order *entity.Order) error {
+
+		if p.HasEqualStatus(status) == true && order.HasEqualStatus(status) == true {
+			return nil
+		}
+
+		if err := p.UpdateStatus(status); err != nil {
+			return errors.New(fmt.Sprintf("[payment status][%s][%v]", p.GetTransactionId(), err))
+		}
+
+		if err := order.UpdatePaymentStatus(status, description); err != nil {
+			return errors.New(fmt.Sprintf("[order status][%d][%v]", order.GetId(), err))
+		}
+
+		applied = true
+
+		return o.savePaymentWithOrder(ctx, p, order)
+	})
+
+	return p, order, applied, err
+}
+
+// ConfirmManualPayment marks to_card, cash or cash on delivery payment as received by operator
+func (o *OrderUserCase) ConfirmManualPayment(ctx context.Context, form checkout.IConfirmManualPaymentForm) error {
+
+	last, err := o.paymentRepository.GetLastByOrder(ctx, form.GetOrderId())
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[error][confirm manual payment][payment not found][%d][%v]", form.GetOrderId(), err))
+	}
+
+	p, order, err := o.lockPayment(ctx, last.GetTransactionId(), func(ctx context.Context, p *entity.Payment, order *entity.Order) error {
+
+		if order.GetPayment().HasManualPayment() == false {
+			return errors.New(fmt.Sprintf("[payment method %s is not confirmed manually]", order.GetPayment().GetMethod().GetSlug()))
+		}
+
+		if form.GetAmount() < p.GetPrice().GetInCent() {
+			return errors.New(fmt.Sprintf("[received amount %d is less than payment amount %d]", form.GetAmount(), p.GetPrice().GetInCent()))
+		}
+
+		desc := fmt.Sprintf("Received %s manually", entity.NewPrice(form.GetAmount(), 0, 0, nil).CentToCurrency())
+
+		if form.GetComment() != "" {
+			desc = fmt.Sprintf("%s: %s", desc, form.GetComment())
+		}
+
+		if err := p.UpdateStatus(entity.PaymentStatusDone); err != nil {
+			return errors.New(fmt.Sprintf("[payment status][%s][%v]", p.GetTransactionId(), err))
+		}
+
+		if err := order.UpdatePaymentStatus(entity.PaymentStatusDone, desc); err != nil {
+			return errors.New(fmt.Sprintf("[order status][%d][%v]", order.GetId(), err))
+		}
+
+		return o.savePaymentWithOrder(ctx, p, order)
+	})
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[error][confirm manual payment]%v", err))
+	}
+
+	o.notify.PaymentStatusUpdated(order, p)
+
+	return nil
+}
+
+// Refund returns full or partial amount of the done payment. Payment and order move to refund status
+// once the whole amount is refunded. The refund is created before the provider call and the payment isn't locked
+// while provider responds, pending refunds hold their amount so concurrent refunds can't exceed the payment.
+func (o *OrderUserCase) Refund(ctx context.Context, form checkout.IRefundPaymentForm) (*entity.Refund, error) {
+
+	var (
+		refund *entity.Refund
+		s      strategy.IRefundStrategy
+	)
+
+	p, order, err := o.lockPayment(ctx, form.GetTransactionId(), func(ctx context.Context, p *entity.Payment, order *entity.Order) error {
+
+		if p.HasEqualStatus(entity.PaymentStatusDone) == false {
+			return errors.New(fmt.Sprintf("[can't refund payment with status: %v][%s]", p.GetStatus(), p.GetTransactionId()))
+		}
+
+		var err error
 
-	notify         *notification.Service
-	paymentContext *strategy.PaymentContext
+		s, err = o.paymentContext.GetRefundPaymentStrategy(p.GetProvider())
 
-	sync.Mutex
-}
+		if err != nil {
+			return errors.New(fmt.Sprintf("[unresolved strategy][%s][%v]", p.GetTransactionId(), err))
+		}
 
-func (o *OrderUserCase) Create(ctx context.Context, form checkout.CreateOrderForm) (*entity.Order, error) {
+		refunded, err := o.refundRepository.GetRefundedAmount(ctx, p.GetId())
+
+		if err != nil {
+			return err
+		}
+
+		pending, err := o.refundRepository.GetPendingAmount(ctx, p.GetId())
+
+		if err != nil {
+			return err
+		}
+
+		remaining := p.GetPrice().GetInCent() - refunded - pending
+		amount := form.GetAmount()
 
-	oProducts, err := o.orderProducts(ctx, form)
+		if amount == 0 {
+			amount = remaining
+		}
+
+		if amount <= 0 || amount > remaining {
+			return errors.New(fmt.Sprintf("[invalid amount %d, remaining %d][%s]", amount, remaining, p.GetTransactionId()))
+		}
+
+		id, err := o.refundRepository.NextId()
+
+		if err != nil {
+			return err
+		}
+
+		refund = entity.CreateNewRefundByPayment(id, p, amount, form.GetComment())
+
+		return o.refundRepository.Create(ctx, refund)
+	})
 
 	if err != nil {
-		return nil, err
+		return nil, errors.New(fmt.Sprintf("[error][refund]%v", err))
 	}
 
-	if len(oProducts) != len(form.GetOrder().GetOrderItems()) {
-		e := fmt.Sprintf("[Create order][count not equal] come: %v; query: %v", len(form.GetOrder().GetOrderItems()), len(oProducts))
-		log.Printf(e)
-		return nil, errors.New(e)
-	}
+	r, providerErr := s.Refund(ctx, order, p, refund)
 
-	dMethod, err := o.deliveryRepository.GetDeliveryMethodBySlug(form.GetDelivery().GetMethod())
+	description := ""
 
-	if err != nil {
-		return nil, err
+	// failed refund is saved too, so it stays visible in the payment history
+	if providerErr != nil {
+		refund.UpdateStatus(entity.RefundStatusFailed, "")
+	} else {
+		refund.UpdateStatus(r.GetStatus(), "")
+		description = r.GetDescription()
 	}
 
-	pMethod, err := o.deliveryRepository.GetPaymentMethodBySlug(form.GetPayment().GetMethod())
+	p, order, err = o.lockPayment(ctx, form.GetTransactionId(), func(ctx context.Context, p *entity.Payment, order *entity.Order) error {
+
+		if err := o.refundRepository.Save(ctx, refund); err != nil {
+			return errors.New(fmt.Sprintf("[refund save][%s][%v]", refund.GetTransactionId(), err))
+		}
+
+		if refund.IsDone() == false {
+			return nil
+		}
+
+		refunded, err := o.refundRepository.GetRefundedAmount(ctx, p.GetId())
+
+		if err != nil {
+			return err
+		}
+
+		if refunded < p.GetPrice().GetInCent() || p.HasEqualStatus(entity.PaymentStatusDone) == false {
+			return nil
+		}
+
+		if err := p.UpdateStatus(entity.PaymentStatusRefund); err != nil {
+			return errors.New(fmt.Sprintf("[payment status][%s][%v]", p.GetTransactionId(), err))
+		}
+
+		if err := order.UpdatePaymentStatus(entity.PaymentStatusRefund, description); err != nil {
+			return errors.New(fmt.Sprintf("[order status][%d][%v]", order.GetId(), err))
+		}
+
+		return o.savePaymentWithOrder(ctx, p, order)
+	})
 
 	if err != nil {
-		return nil, err
+		return nil, errors.New(fmt.Sprintf("[error][refund][%s]%v", refund.GetTransactionId(), err))
 	}
 
-	builder := &checkout.CreateOrderBuilder{
-		DeliveryMethod: dMethod,
-		PaymentMethod:  pMethod,
-		Products:       oProducts,
-		Warehouse:      entity.NewOrderDeliveryWarehouse(form.GetDelivery().GetCity().GetCode(), form.GetDelivery().GetCity().GetName(), form.GetDelivery().GetAddress().GetCode(), form.GetDelivery().GetAddress().GetName(), form.GetDelivery().IsCustomAddress()),
-		Customer:       entity.NewOrderCustomer(form.GetClient().GetFio(), form.GetClient().GetPhone()),
-		Cost:           form.GetOrder().GetCost(),
-		Comment:        form.GetComment(),
-		DoNotCall:      form.GetDoNotCall(),
-		PayInCompany:   form.GetPayment().GetPayInCompany(),
-		PayInEdrpou:    form.GetPayment().GetPayInEdrpou(),
-		PayInEmail:     form.GetPayment().GetPayInEmail(),
-		PayPartsPay:    form.GetPayment().GetPayPartsPay(),
+	if providerErr != nil {
+		return nil, errors.New(fmt.Sprintf("[error][refund][provider refund][%s]%v", p.GetTransactionId(), providerErr))
 	}
 
-	order, err := o.orderRepository.Create(ctx, builder)
+	o.notify.PaymentRefunded(order, p, refund)
 
-	if err != nil {
-		return nil, err
+	if refund.IsDone() == false {
+		return refund, errors.New(fmt.Sprintf("[error][refund][provider declined][%s][%s]", p.GetTransactionId(), description))
 	}
 
-	o.notify.OrderCreated(order)
-
-	return order, nil
+	return refund, nil
 }
 
-func (o *OrderUserCase) OrderInfo(ctx context.Context, form checkout.OrderIdForm) (*entity.Order, error) {
-	return o.orderRepository.Get(ctx, form.GetOrderId())
-}
+// paymentEventHeaders are request headers stored with provider callback, e.g. monobank webhook signature
+var paymentEventHeaders = []string{"X-Sign"}
 
-func (o *OrderUserCase) InitPayment(ctx context.Context, form checkout.InitPaymentForm) (checkout.IInitPaymentResponse, error) {
+// paymentEventProcessTimeout is how long the new event is left to the delivery processing it,
+// repeated callback takes it over after that
+const paymentEventProcessTimeout = 2 * time.Minute
 
-	order, err := o.orderRepository.Get(ctx, form.GetOrderId())
+func (o *OrderUserCase) ProviderCallback(ctx *gin.Context, form checkout.IProviderCallbackPaymentForm) (checkout.IProviderCallbackPaymentResponse, error) {
 
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("[Init payment][%v]", err))
-	}
+	s, err := o.paymentContext.GetProviderCallbackPaymentStrategy(form.GetProvider())
 
-	if order.CanMakePayment() != true {
-		return nil, errors.New(fmt.Sprintf("[Init payment][Can't init payment for order with payment status: %v]", order.GetPayment().GetStatus()))
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[error][provider callback][unresolved strategy][%v][%v]", form.GetProvider(), err))
 	}
 
-	id, err := o.paymentRepository.NextId()
+	body, err := ioutil.ReadAll(ctx.Request.Body)
 
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("[payment init][next sequense][%v]", err))
+		return nil, errors.New(fmt.Sprintf("[error][provider callback][read body][%v]", err))
 	}
 
-	p := entity.CreateNewPaymentByOrder(id, order)
+	// strategies read the request body on their own
+	ctx.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
 
-	err = o.paymentRepository.Create(ctx, p)
+	id, err := o.paymentEventRepository.NextId()
 
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("[payment init][create payment][%v]", err))
+		return nil, errors.New(fmt.Sprintf("[error][provider callback]%v", err))
 	}
 
-	pMethod := order.GetPayment().GetMethod()
+	headers := make(map[string]string, len(paymentEventHeaders))
 
-	r, err := o.paymentContext.GetInitPaymentStrategy(&pMethod).Init(ctx, order, p)
+	// only signature headers are stored, they are needed to check the signature on replay
+	for _, k := range paymentEventHeaders {
+		if v := ctx.GetHeader(k); v != "" {
+			headers[k] = v
+		}
+	}
+
+	event := entity.CreateNewPaymentEvent(id, form.GetProvider(), ctx.ContentType(), headers, body)
+
+	created, err := o.paymentEventRepository.Create(ctx, event)
 
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("[payment init][provider init]%v", err))
+		return nil, errors.New(fmt.Sprintf("[error][provider callback]%v", err))
 	}
 
-	p.SetProvider(r.GetProviderName())
+	if created == false {
+		event, err = o.paymentEventRepository.GetByHash(ctx, event.GetHash())
 
-	err = o.paymentRepository.Save(ctx, p)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("[error][provider callback][get event][%v]", err))
+		}
 
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("[payment init][update error][%v]", err))
+		// handled events aren't repeated, new one is left to the concurrent delivery unless it got stuck
+		if event.IsHandled() || event.IsProcessing(time.Now(), paymentEventProcessTimeout) {
+			log.Printf("[provider callback][duplicate event][%d][%s]", event.GetId(), event.GetOutcome())
+			return nil, nil
+		}
 	}
 
-	o.notify.PaymentCreated(order, p)
+	if err := o.handlePaymentEvent(ctx, s, event); err != nil {
+		return nil, errors.New(fmt.Sprintf("[error][provider callback]%v", err))
+	}
 
-	return NewIniPaymentResponse(p, order, r.GetAction(), r.GetResource()), nil
+	return nil, nil
 }
 
-func (o *OrderUserCase) AcceptHoldenPayment(ctx context.Context, form checkout.IAcceptHoldenPaymentForm) error {
+func (o *OrderUserCase) PaymentEvents(ctx context.Context, form checkout.IPaymentEventsForm) ([]*entity.PaymentEvent, error) {
+
+	limit := form.GetLimit()
+
+	if limit <= 0 {
+		limit = 50
+	}
+
+	return o.paymentEventRepository.Find(ctx, form.GetTransactionId(), limit)
+}
 
-	o.Lock()
-	defer o.Unlock()
+// ReplayPaymentEvent processes stored provider callback once again, e.g. after fixing the reason it failed
+func (o *OrderUserCase) ReplayPaymentEvent(ctx *gin.Context, form checkout.IReplayPaymentEventForm) (*entity.PaymentEvent, error) {
 
-	p, err := o.paymentRepository.Get(ctx, form.GetTransactionId())
+	event, err := o.paymentEventRepository.Get(ctx, form.GetEventId())
 
 	if err != nil {
-		return errors.New(fmt.Sprintf("[error][accept holden][payment not found][%s][%v]", form.GetTransactionId(), err))
+		return nil, errors.New(fmt.Sprintf("[error][replay payment event][event not found][%d][%v]", form.GetEventId(), err))
 	}
 
-	order, err := o.orderRepository.Get(ctx, p.GetOrderId())
+	s, err := o.paymentContext.GetProviderCallbackPaymentStrategy(event.GetProvider())
 
 	if err != nil {
-		return errors.New(fmt.Sprintf("[error][accept holden][order not found][%d][%v]", p.GetOrderId(), err))
+		return nil, errors.New(fmt.Sprintf("[error][replay payment event][unresolved strategy][%v][%v]", event.GetProvider(), err))
 	}
 
-	s, err := o.paymentContext.GetAcceptHoldenPaymentStrategy(p.GetProvider())
+	req, err := http.NewRequest(http.MethodPost, ctx.Request.URL.String(), strings.NewReader(event.GetBody()))
 
 	if err != nil {
-		return errors.New(fmt.Sprintf("[error][accept holden][unresolved strategy][%s][%v]", p.GetTransactionId(), err))
+		return nil, errors.New(fmt.Sprintf("[error][replay payment event][build request][%v]", err))
 	}
 
-	r, err := s.Accept(ctx, order, p)
+	for k, v := range event.GetHeaders() {
+		req.Header.Set(k, v)
+	}
 
-	if err != nil {
-		return errors.New(fmt.Sprintf("[error][accept holden][unresolved strategy][%s]%v", p.GetTransactionId(), err))
+	req.Header.Set("Content-Type", event.GetContentType())
+
+	// strategies read the callback from request, so they get the stored one instead of the replay request
+	rc := ctx.Copy()
+	rc.Request = req
+	rc.Keys = nil
+
+	if err := o.handlePaymentEvent(rc, s, event); err != nil {
+		return event, errors.New(fmt.Sprintf("[error][replay payment event]%v", err))
 	}
 
-	p.UpdateStatus(r.GetStatus())
-	err = o.paymentRepository.Save(ctx, p)
+	return event, nil
+}
+
+// handlePaymentEvent applies callback stored in event and saves the outcome
+func (o *OrderUserCase) handlePaymentEvent(ctx *gin.Context, s strategy.IProviderCallbackStrategy, event *entity.PaymentEvent) error {
+
+	err := o.processPaymentEvent(ctx, s, event)
 
 	if err != nil {
-		return errors.New(fmt.Sprintf("[error][accept holden][payment save][%s][%v]", p.GetTransactionId(), err))
+		event.UpdateOutcome(entity.PaymentEventOutcomeFailed, err.Error())
+	}
+
+	if err := o.paymentEventRepository.Save(ctx, event); err != nil {
+		log.Printf("[error][save payment event][%d][%v]", event.GetId(), err)
+	}
+
+	return err
+}
+
+func (o *OrderUserCase) processPaymentEvent(ctx *gin.Context, s strategy.IProviderCallbackStrategy, event *entity.PaymentEvent) error {
+
+	event.SetValidSignature(s.IsValidSignature(ctx))
+
+	if event.IsValidSignature() == false {
+		return errors.New(fmt.Sprintf("[invalid signature][%v]", event.GetProvider()))
 	}
 
-	order.UpdatePaymentStatus(r.GetStatus(), r.GetDescription())
-	err = o.orderRepository.Save(ctx, order)
+	transactionId := s.GetTransactionId(ctx)
+
+	event.SetTransactionId(transactionId)
+
+	var (
+		resp    strategy.IProcessingCallbackPaymentStrategyResponse
+		applied bool
+	)
+
+	_, _, err := o.lockPayment(ctx, transactionId, func(txCtx context.Context, payment *entity.Payment, order *entity.Order) error {
+
+		var err error
+
+		// strategies read the callback from gin context, repositories get the transaction one
+		resp, err = s.ProcessingCallback(ctx)
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("[processing error][%d][%v]", payment.GetOrderId(), err))
+		}
+
+		applied, err = o.applyProviderStatus(txCtx, payment, order, resp)
+
+		return err
+	})
+
 	if err != nil {
-		return errors.New(fmt.Sprintf("[error][accept holden][order save][%d][%v]", order.GetId(), err))
+		return err
 	}
 
-	o.notify.PaymentStatusUpdated(order, p)
+	if applied == true {
+		event.UpdateOutcome(entity.PaymentEventOutcomeProcessed, fmt.Sprintf("payment status %d", resp.GetStatus()))
+	} else {
+		event.UpdateOutcome(entity.PaymentEventOutcomeSkipped, resp.GetDescription())
+	}
 
 	return nil
 }
 
-func (o *OrderUserCase) ProviderCallback(ctx *gin.Context, form checkout.IProviderCallbackPaymentForm) (checkout.IProviderCallbackPaymentResponse, error) {
+// ReconcilePayments asks providers for the state of payments stuck in new or pending status,
+// e.g. when the provider callback was lost. Payments of providers without status api are never selected,
+// so they don't hold the window. Returns count of checked payments.
+func (o *OrderUserCase) ReconcilePayments(ctx context.Context, staleAfter, maxAge time.Duration, limit int) (int, error) {
 
-	o.Lock()
-	defer o.Unlock()
+	providers := o.paymentContext.GetStatusProviders()
 
-	s, err := o.paymentContext.GetProviderCallbackPaymentStrategy(form.GetProvider())
+	if len(providers) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+
+	payments, err := o.paymentRepository.GetStale(
+		ctx,
+		providers,
+		[]int{entity.PaymentStatusNew, entity.PaymentStatusPending},
+		now.Add(-maxAge),
+		now.Add(-staleAfter),
+		limit,
+	)
 
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("[error][provider callback][unresolved strategy][%v][%v]", form.GetProvider(), err))
+		return 0, errors.New(fmt.Sprintf("[error][reconcile payments]%v", err))
 	}
 
-	if s.IsValidSignature(ctx) == false {
-		return nil, errors.New(fmt.Sprintf("[error][provider callback][invalid signature][%v][%v]", form.GetProvider(), err))
+	checked := 0
+
+	for _, p := range payments {
+		s, err := o.paymentContext.GetPaymentStatusStrategy(p.GetProvider())
+
+		if err != nil {
+			continue
+		}
+
+		if err := o.reconcilePayment(ctx, s, p); err != nil {
+			log.Printf("[error][reconcile payments][%s]%v", p.GetTransactionId(), err)
+			continue
+		}
+
+		checked++
 	}
 
-	transactionId := s.GetTransactionId(ctx)
+	return checked, nil
+}
+
+// ReleaseExpiredReservations gives back stock of orders nobody paid or processed for longer than maxAge.
+// Returns count of released orders.
+func (o *OrderUserCase) ReleaseExpiredReservations(ctx context.Context, maxAge time.Duration) (int, error) {
+
+	var ids []int
+
+	err := o.unitOfWork.Transaction(ctx, func(ctx context.Context) error {
+
+		var err error
+
+		ids, err = o.stock.ReleaseExpired(ctx, maxAge)
 
-	payment, err := o.paymentRepository.Get(ctx, transactionId)
+		return err
+	})
 
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("[error][provider callback][payment not found][%s][%v]", transactionId, err))
+		return 0, errors.New(fmt.Sprintf("[error][release expired reservations]%v", err))
 	}
 
-	order, err := o.orderRepository.Get(ctx, payment.GetOrderId())
+	return len(ids), nil
+}
+
+// TrackDeliveries moves orders sent by carrier along with parcel statuses and records carrier descriptions in
+// delivery history. Parcels waiting at the warehouse are polled too, they are canceled when refused or not picked up.
+// Orders polled longest ago go first, so limit doesn't starve any of them.
+// Returns count of changed orders.
+func (o *OrderUserCase) TrackDeliveries(ctx context.Context, maxAge time.Duration, limit int) (int, error) {
+
+	orders, err := o.orderRepository.FindLeastTracked(ctx, checkout.OrderFilter{
+		DeliveryStatuses: []int{entity.DeliveryStatusNew, entity.DeliveryStatusCheck, entity.DeliveryStatusWaitingDelivery, entity.DeliveryStatusDelivery, entity.DeliveryStatusReadyToReceive},
+		Tracked:          true,
+		DeliveryMethod:   entity.DeliveryMethodNovaposhta,
+		From:             time.Now().Add(-maxAge).Unix(),
+	}, limit)
 
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("[error][provider callback][order not found][%d][%v]", payment.GetOrderId(), err))
+		return 0, errors.New(fmt.Sprintf("[error][track deliveries]%v", err))
+	}
+
+	if len(orders) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int, len(orders))
+	numbers := make([]string, len(orders))
+
+	for k, v := range orders {
+		ids[k] = v.GetId()
+		numbers[k] = v.GetDelivery().GetTrackingNumber()
 	}
 
-	resp, err := s.ProcessingCallback(ctx)
+	statuses, err := o.tracking.Track(ctx, numbers)
 
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("[error][provider callback][processing error][%d][%v]", payment.GetOrderId(), err))
+		return 0, errors.New(fmt.Sprintf("[error][track deliveries]%v", err))
 	}
 
-	if resp.Skip() == true {
-		return nil, nil
+	if err := o.orderRepository.SetTracked(ctx, ids, time.Now()); err != nil {
+		return 0, errors.New(fmt.Sprintf("[error][track deliveries]%v", err))
 	}
 
-	if payment.HasEqualStatus(resp.GetStatus()) == true && order.HasEqualStatus(resp.GetStatus()) {
-		log.Printf("[provider callback][payment has current status][%s][%d]", transactionId, resp.GetStatus())
-		return nil, nil
+	changed := 0
+
+	for _, v := range orders {
+		s, ok := statuses[v.GetDelivery().GetTrackingNumber()]
+
+		if ok == false || s.GetStatus() == 0 {
+			continue
+		}
+
+		updated, err := o.trackDelivery(ctx, v.GetId(), s)
+
+		if err != nil {
+			log.Printf("[error][track deliveries][order %d]%v", v.GetId(), err)
+			continue
+		}
+
+		if updated == true {
+			changed++
+		}
 	}
 
-	payment.UpdateStatus(resp.GetStatus())
-	err = o.paymentRepository.Save(ctx, payment)
+	return changed, nil
+}
+
+func (o *OrderUserCase) trackDelivery(ctx context.Context, orderId int, s *entity.WaybillStatus) (bool, error) {
+
+	var (
+		order    *entity.Order
+		updated  bool
+		previous int
+	)
+
+	// order is reloaded under lock, operator could change the status meanwhile
+	err := o.unitOfWork.Transaction(ctx, func(ctx context.Context) error {
+
+		var err error
+
+		order, err = o.orderRepository.GetForUpdate(ctx, orderId)
+
+		if err != nil {
+			return err
+		}
+
+		if order.GetDelivery().GetTrackingNumber() != s.GetNumber() {
+			return nil
+		}
+
+		previous = order.GetDelivery().GetStatus()
+		updated, err = order.TrackDeliveryStatus(s.GetStatus(), s.GetDescription())
+
+		if err != nil || updated == false {
+			return err
+		}
+
+		return o.settleOrder(ctx, order)
+	})
 
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("[error][provider callback][payment save][%s][%v]", payment.GetTransactionId(), err))
+		return false, err
+	}
+
+	if updated == true && previous != order.GetDelivery().GetStatus() {
+		o.notify.DeliveryStatusUpdated(order)
 	}
 
-	order.UpdatePaymentStatus(resp.GetStatus(), resp.GetDescription())
-	err = o.orderRepository.Save(ctx, order)
+	return updated, nil
+}
+
+func (o *OrderUserCase) reconcilePayment(ctx context.Context, s strategy.IPaymentStatusStrategy, payment *entity.Payment) error {
+
+	// provider is asked without the lock, only reading the status, and the answer is applied to the payment
+	// reloaded under lock, callback could be processed meanwhile
+	resp, err := s.Status(ctx, payment)
 
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("[error][provider callback][order save][%d][%v]", order.GetId(), err))
+		return errors.New(fmt.Sprintf("[provider status]%v", err))
+	}
+
+	_, _, err = o.lockPayment(ctx, payment.GetTransactionId(), func(ctx context.Context, payment *entity.Payment, order *entity.Order) error {
+
+		if resp.Skip() == true || payment.HasEqualStatus(resp.GetStatus()) == true {
+			// touch updated time to check the payment again after stale interval
+			return o.paymentRepository.Save(ctx, payment)
+		}
+
+		_, err := o.applyProviderStatus(ctx, payment, order, resp)
+
+		return err
+	})
+
+	return err
+}
+
+// lockPayment runs fn in one transaction holding row locks of the payment and its order, so requests
+// changing the same payment wait for each other on every API replica while other payments go concurrently.
+func (o *OrderUserCase) lockPayment(ctx context.Context, transactionId string, fn func(ctx context.Context, payment *entity.Payment, order *entity.Order) error) (*entity.Payment, *entity.Order, error) {
+
+	var (
+		payment *entity.Payment
+		order   *entity.Order
+	)
+
+	err := o.unitOfWork.Transaction(ctx, func(ctx context.Context) error {
+
+		var err error
+
+		payment, err = o.paymentRepository.GetForUpdate(ctx, transactionId)
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("[payment not found][%s][%v]", transactionId, err))
+		}
+
+		order, err = o.orderRepository.GetForUpdate(ctx, payment.GetOrderId())
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("[order not found][%d][%v]", payment.GetOrderId(), err))
+		}
+
+		return fn(ctx, payment, order)
+	})
+
+	return payment, order, err
+}
+
+// applyProviderStatus moves payment and order to the status reported by provider.
+// Returns false when the status was skipped and nothing was changed.
+func (o *OrderUserCase) applyProviderStatus(ctx context.Context, payment *entity.Payment, order *entity.Order, resp strategy.IProcessingCallbackPaymentStrategyResponse) (bool, error) {
+
+	if resp.Skip() == true {
+		return false, nil
+	}
+
+	if payment.HasEqualStatus(resp.GetStatus()) == true && order.HasEqualStatus(resp.GetStatus()) {
+		log.Printf("[provider status][payment has current status][%s][%d]", payment.GetTransactionId(), resp.GetStatus())
+		return false, nil
+	}
+
+	// late or out of order provider statuses must not move the payment backwards, so they are skipped
+	if err := payment.UpdateStatus(resp.GetStatus()); err != nil {
+		log.Printf("[provider status][skip][payment status][%s][%v]", payment.GetTransactionId(), err)
+		return false, nil
+	}
+
+	if err := order.UpdatePaymentStatus(resp.GetStatus(), resp.GetDescription()); err != nil {
+		log.Printf("[provider status][skip][order status][%d][%v]", order.GetId(), err)
+		return false, nil
+	}
+
+	if err := o.savePaymentWithOrder(ctx, payment, order); err != nil {
+		return false, err
 	}
 
 	o.notify.PaymentStatusUpdated(order, payment)
 
-	return nil, nil
+	return true, nil
+}
+
+// savePaymentWithOrder persists the payment row and the order payment status in one transaction.
+// Stock reserved by the order is consumed when the payment is done, stock and promo code usage are released
+// when it's closed unpaid.
+func (o *OrderUserCase) savePaymentWithOrder(ctx context.Context, payment *entity.Payment, order *entity.Order) error {
+
+	return o.unitOfWork.Transaction(ctx, func(ctx context.Context) error {
+
+		if err := o.paymentRepository.Save(ctx, payment); err != nil {
+			return errors.New(fmt.Sprintf("[payment save][%s][%v]", payment.GetTransactionId(), err))
+		}
+
+		return o.settleOrder(ctx, order)
+	})
+}
+
+// settleOrder saves the order after payment or delivery status change, stock reservation and promo code usage
+// follow the order state. Should run inside a transaction.
+func (o *OrderUserCase) settleOrder(ctx context.Context, order *entity.Order) error {
+
+	// the order keeps whether the promo usage is released, so it goes before the order save
+	if err := o.promo.Settle(ctx, order); err != nil {
+		return errors.New(fmt.Sprintf("[promo settle][%d]%v", order.GetId(), err))
+	}
+
+	if err := o.orderRepository.Save(ctx, order); err != nil {
+		return errors.New(fmt.Sprintf("[order save][%d][%v]", order.GetId(), err))
+	}
+
+	if err := o.stock.Settle(ctx, order); err != nil {
+		return errors.New(fmt.Sprintf("[stock settle][%d]%v", order.GetId(), err))
+	}
+
+	return nil
 }
 
-func (o *OrderUserCase) orderProducts(ctx context.Context, form checkout.CreateOrderForm) ([]*entity.OrderProduct, error) {
+// orderProducts returns order lines priced in base currency and catalog products in the same sequence
+func (o *OrderUserCase) orderProducts(ctx context.Context, form checkout.CreateOrderForm) ([]*entity.OrderProduct, []*entity.Product, error) {
 
 	pIds := make([]int, len(form.GetOrder().GetOrderItems()))
 	mTemp := make(map[int]checkout.OrderItemForm, len(form.GetOrder().GetOrderItems()))
@@ -265,22 +1575,75 @@ func (o *OrderUserCase) orderProducts(ctx context.Context, form checkout.CreateO
 	products, err := o.productRepository.GetByIdsWithSequence(ctx, pIds)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	simple := make([]entity.SimpleProduct, len(products))
 
 	for k, v := range products {
-		simple[k] = *entity.NewSimpleProduct(v.ID, v.Name, v.Code, v.Exist, v.Status, v.Price)
+		simple[k] = *entity.NewSimpleProduct(v.ID, v.Name, v.Code, v.Exist, v.Quantity, v.Status, v.Price)
 	}
 
 	oProducts := make([]*entity.OrderProduct, len(simple))
 
 	for k, v := range simple {
-		oProducts[k] = entity.NewOrderProduct(mTemp[v.ID].GetCount(), mTemp[v.ID].GetPrice(), v)
+		count := mTemp[v.ID].GetCount()
+		oProducts[k] = entity.NewOrderProduct(count, v.Price.GetBasePriceByQuantity(count), v)
+	}
+
+	return oProducts, products, nil
+}
+
+func promoLines(items []*entity.OrderProduct, products []*entity.Product) []entity.PromoLine {
+
+	lines := make([]entity.PromoLine, len(items))
+
+	for k, v := range items {
+		total := v.GetTotal()
+
+		lines[k] = entity.PromoLine{
+			ProductId: v.GetProduct().ID,
+			GroupId:   products[k].Group.ID,
+			BrandId:   products[k].Brand.ID,
+			Total:     total.GetInCent(),
+		}
+	}
+
+	return lines
+}
+
+// orderCost sums line totals calculated from product prices and rejects the form if client prices differ
+func (o *OrderUserCase) orderCost(form checkout.CreateOrderForm, products []*entity.OrderProduct) (int, error) {
+
+	given := make(map[int]int, len(form.GetOrder().GetOrderItems()))
+
+	for _, v := range form.GetOrder().GetOrderItems() {
+		given[v.GetProductId()] = v.GetPrice()
+	}
+
+	mismatch := &checkout.PriceMismatchError{GivenCost: form.GetOrder().GetCost()}
+
+	for _, p := range products {
+		price := p.GetPrice()
+		total := p.GetTotal()
+
+		mismatch.ExpectedCost += total.GetInCent()
+
+		if given[p.GetProduct().ID] != price.GetInCent() {
+			mismatch.Items = append(mismatch.Items, checkout.ItemPriceMismatch{
+				ProductId: p.GetProduct().ID,
+				Given:     given[p.GetProduct().ID],
+				Expected:  price.GetInCent(),
+			})
+		}
 	}
 
-	return oProducts, nil
+	if mismatch.HasMismatch() == true {
+		log.Printf("[Create order]%v", mismatch)
+		return 0, mismatch
+	}
+
+	return mismatch.ExpectedCost, nil
 }
 
 func (o OrderUserCase) Callback(provider string, ) {
@@ -322,4 +1685,96 @@ func (r *InitPaymentResponse) GetOrderId() int {
 }
 func (r *InitPaymentResponse) GetDoNotCall() bool {
 	return r.order.GetDoNotCall()
-}
\ No newline at end of file
+}
+
+// editedItems builds the new items list, kept items are copied from the order, new ones are priced by products
+func editedItems(order *entity.Order, forms []checkout.EditOrderItemForm, products map[int]*entity.Product) ([]*entity.OrderProduct, error) {
+
+	current := make(map[int]entity.OrderProduct, len(order.GetItems()))
+
+	for _, v := range order.GetItems() {
+		current[v.GetProduct().ID] = v
+	}
+
+	items := make([]*entity.OrderProduct, 0, len(forms))
+	seen := make(map[int]bool, len(forms))
+	stockErr := &checkout.StockError{}
+
+	for _, v := range forms {
+		if seen[v.GetProductId()] == true {
+			return nil, errors.New(fmt.Sprintf("[product %d is listed twice]", v.GetProductId()))
+		}
+
+		seen[v.GetProductId()] = true
+
+		if item, ok := current[v.GetProductId()]; ok == true {
+			item.SetQuantity(v.GetCount())
+			items = append(items, &item)
+			continue
+		}
+
+		p, ok := products[v.GetProductId()]
+
+		if ok == false {
+			stockErr.Add(checkout.ItemStockError{ProductId: v.GetProductId(), Reason: checkout.StockReasonNotFound, Requested: v.GetCount()})
+			continue
+		}
+
+		simple := entity.NewSimpleProduct(p.ID, p.Name, p.Code, p.Exist, p.Quantity, p.Status, p.Price)
+
+		if simple.IsActive() == false {
+			stockErr.Add(checkout.ItemStockError{ProductId: p.ID, Name: p.Name, Reason: checkout.StockReasonDisabled, Requested: v.GetCount()})
+			continue
+		}
+
+		items = append(items, entity.NewOrderProduct(v.GetCount(), p.Price.GetBasePriceByQuantity(v.GetCount()), *simple))
+	}
+
+	if stockErr.HasItems() == true {
+		return nil, stockErr
+	}
+
+	return items, nil
+}
+
+// editedPromoLines is promoLines of edited items, products of kept items could be removed from the catalog since
+func editedPromoLines(items []*entity.OrderProduct, products map[int]*entity.Product) []entity.PromoLine {
+
+	lines := make([]entity.PromoLine, len(items))
+
+	for k, v := range items {
+		total := v.GetTotal()
+
+		lines[k] = entity.PromoLine{ProductId: v.GetProduct().ID, Total: total.GetInCent()}
+
+		if p, ok := products[v.GetProduct().ID]; ok == true {
+			lines[k].GroupId = p.Group.ID
+			lines[k].BrandId = p.Brand.ID
+		}
+	}
+
+	return lines
+}
+
+func customerSummary(c entity.OrderCustomer) string {
+	return fmt.Sprintf("%s, %s", c.GetName(), c.GetPhone())
+}
+
+func warehouseSummary(w entity.OrderDeliveryWarehouse) string {
+	return fmt.Sprintf("%s, %s", w.GetCity().GetName(), w.GetAddress().GetName())
+}
+
+func itemsSummary(order *entity.Order) string {
+
+	lines := make([]string, 0, len(order.GetItems())+1)
+
+	for _, v := range order.GetItems() {
+		total := v.GetDiscountedTotal()
+		lines = append(lines, fmt.Sprintf("%s (%d) x%d = %s", v.GetProduct().Name, v.GetProduct().Code, v.GetQuantity(), total.CentToCurrency()))
+	}
+
+	cost := order.GetPrice()
+	lines = append(lines, fmt.Sprintf("total: %s", cost.CentToCurrency()))
+
+	return strings.Join(lines, "; ")
+}
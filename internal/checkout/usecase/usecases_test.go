@@ -0,0 +1,774 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wowucco/G3/internal/checkout"
+	"github.com/wowucco/G3/internal/checkout/stock"
+	"github.com/wowucco/G3/internal/checkout/strategy"
+	"github.com/wowucco/G3/internal/entity"
+	"github.com/wowucco/G3/pkg/notification"
+	"github.com/wowucco/G3/pkg/sms"
+	"github.com/wowucco/G3/pkg/telegram"
+)
+
+type unitOfWorkStub struct{}
+
+func (unitOfWorkStub) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type orderRepositoryStub struct {
+	checkout.IOrderRepository
+	order   *entity.Order
+	saved   int
+	filter  checkout.OrderFilter
+	tracked []int
+}
+
+func (r *orderRepositoryStub) GetForUpdate(ctx context.Context, orderId int) (*entity.Order, error) {
+	return r.order, nil
+}
+
+func (r *orderRepositoryStub) GetCurrency(ctx context.Context, iso string) (*entity.Currency, error) {
+	return entity.NewCurrency(2, iso, 27.35, iso), nil
+}
+
+func (r *orderRepositoryStub) Save(ctx context.Context, order *entity.Order) error {
+	r.saved++
+	return nil
+}
+
+func (r *orderRepositoryStub) FindLeastTracked(ctx context.Context, filter checkout.OrderFilter, limit int) ([]*entity.Order, error) {
+	r.filter = filter
+	return []*entity.Order{r.order}, nil
+}
+
+func (r *orderRepositoryStub) SetTracked(ctx context.Context, orderIds []int, at time.Time) error {
+	r.tracked = orderIds
+	return nil
+}
+
+type paymentRepositoryStub struct {
+	checkout.IPaymentRepository
+	payment *entity.Payment
+	saved   int
+}
+
+func (r *paymentRepositoryStub) GetForUpdate(ctx context.Context, transactionId string) (*entity.Payment, error) {
+	return r.payment, nil
+}
+
+func (r *paymentRepositoryStub) GetLastByOrder(ctx context.Context, orderId int) (*entity.Payment, error) {
+	return r.payment, nil
+}
+
+func (r *paymentRepositoryStub) Save(ctx context.Context, p *entity.Payment) error {
+	r.saved++
+	return nil
+}
+
+type stockRepositoryStub struct {
+	checkout.IStockRepository
+	consumed []int
+	released []int
+}
+
+func (r *stockRepositoryStub) Consume(ctx context.Context, orderId int) error {
+	r.consumed = append(r.consumed, orderId)
+	return nil
+}
+
+func (r *stockRepositoryStub) Release(ctx context.Context, orderId int) error {
+	r.released = append(r.released, orderId)
+	return nil
+}
+
+type confirmManualPaymentForm struct {
+	orderId int
+	amount  int
+}
+
+func (f confirmManualPaymentForm) GetOrderId() int {
+	return f.orderId
+}
+
+func (f confirmManualPaymentForm) GetAmount() int {
+	return f.amount
+}
+
+func (f confirmManualPaymentForm) GetComment() string {
+	return "bank statement"
+}
+
+func newManualPaymentOrder(method string) *entity.Order {
+
+	return entity.NewOrder(
+		1, 0, "", false, 10050, nil,
+		entity.NewOrderCustomer("customer", "380501234567"),
+		entity.NewOrderDelivery(entity.DeliveryStatusNew, entity.NewDeliveryMethod(1, "yourself", entity.DeliveryMethodYourself), nil, nil),
+		entity.NewOrderPayment(entity.PaymentStatusPending, entity.NewPaymentMethod(1, method, method), nil, "", "", "", 0),
+		[]*entity.OrderProduct{entity.NewOrderProduct(1, 10050, entity.SimpleProduct{ID: 1, Name: "product"})},
+	)
+}
+
+func TestOrderUserCase_ConfirmManualPayment(t *testing.T) {
+
+	tests := []struct {
+		tag      string
+		method   string
+		amount   int
+		hasError bool
+	}{
+		{"t1", entity.PaymentMethodPayin, 10050, false},
+		{"t2", entity.PaymentMethodToCard, 10050, false},
+		{"t3", entity.PaymentMethodPayin, 10000, true},
+		{"t4", entity.PaymentMethodP2P, 10050, true},
+	}
+
+	for _, test := range tests {
+		order := newManualPaymentOrder(test.method)
+		payment := entity.CreateNewPaymentByOrder(1, order)
+		_ = payment.UpdateStatus(entity.PaymentStatusPending)
+
+		orders := &orderRepositoryStub{order: order}
+		payments := &paymentRepositoryStub{payment: payment}
+		stocks := &stockRepositoryStub{}
+		n := notification.NewNotificationService(make(chan sms.Message, 10), make(chan telegram.Message, 10), nil, "", "%d", "%d")
+
+		uc := NewOrderUseCase(orders, nil, nil, payments, nil, nil, nil, unitOfWorkStub{}, nil, nil, stock.NewStockService(stocks), nil, nil, nil, n, nil)
+
+		err := uc.ConfirmManualPayment(context.Background(), confirmManualPaymentForm{orderId: order.GetId(), amount: test.amount})
+
+		if test.hasError {
+			assert.Error(t, err, test.tag)
+			assert.Equal(t, entity.PaymentStatusPending, payment.GetStatus(), test.tag)
+			assert.Equal(t, 0, payments.saved, test.tag)
+			assert.Empty(t, stocks.consumed, test.tag)
+			continue
+		}
+
+		assert.NoError(t, err, test.tag)
+		assert.Equal(t, entity.PaymentStatusDone, payment.GetStatus(), test.tag)
+		assert.Equal(t, entity.PaymentStatusDone, order.GetPayment().GetStatus(), test.tag)
+		assert.Equal(t, 1, payments.saved, test.tag)
+		assert.Equal(t, 1, orders.saved, test.tag)
+		assert.Equal(t, []int{order.GetId()}, stocks.consumed, test.tag)
+	}
+}
+
+type refundRepositoryStub struct {
+	refunded int
+	pending  int
+	created  *entity.Refund
+}
+
+func (r *refundRepositoryStub) NextId() (int, error) {
+	return 1, nil
+}
+
+func (r *refundRepositoryStub) Create(ctx context.Context, refund *entity.Refund) error {
+	r.created = refund
+	return nil
+}
+
+func (r *refundRepositoryStub) Save(ctx context.Context, refund *entity.Refund) error {
+	if refund.IsDone() {
+		r.refunded += refund.GetPrice().GetInCent()
+	}
+	return nil
+}
+
+func (r *refundRepositoryStub) GetRefundedAmount(ctx context.Context, paymentId int) (int, error) {
+	return r.refunded, nil
+}
+
+func (r *refundRepositoryStub) GetPendingAmount(ctx context.Context, paymentId int) (int, error) {
+	return r.pending, nil
+}
+
+type refundStrategyStub struct {
+	calls int
+}
+
+func (s *refundStrategyStub) Refund(ctx context.Context, order *entity.Order, payment *entity.Payment, refund *entity.Refund) (strategy.IRefundPaymentStrategyResponse, error) {
+	s.calls++
+	return strategy.NewRefundPaymentStrategyResponse(entity.RefundStatusDone, "refunded", nil), nil
+}
+
+type refundForm struct {
+	amount int
+}
+
+func (f refundForm) GetTransactionId() string {
+	return "transaction"
+}
+
+func (f refundForm) GetAmount() int {
+	return f.amount
+}
+
+func (f refundForm) GetComment() string {
+	return ""
+}
+
+func TestOrderUserCase_Refund(t *testing.T) {
+
+	tests := []struct {
+		tag      string
+		amount   int
+		refunded int
+		pending  int
+		hasError bool
+		expected int
+		status   int
+	}{
+		{"t1", 0, 0, 0, false, 10050, entity.PaymentStatusRefund},
+		{"t2", 5000, 0, 0, false, 5000, entity.PaymentStatusDone},
+		{"t3", 0, 5000, 0, false, 5050, entity.PaymentStatusRefund},
+		{"t4", 6000, 5000, 0, true, 0, entity.PaymentStatusDone},
+		{"t5", 3000, 5000, 4000, true, 0, entity.PaymentStatusDone},
+		{"t6", 0, 10050, 0, true, 0, entity.PaymentStatusDone},
+	}
+
+	for _, test := range tests {
+		r := strategy.NewRegistry()
+		s := &refundStrategyStub{}
+
+		_ = r.Register(&strategy.Provider{
+			Name:         "liqpay",
+			Methods:      []string{entity.PaymentMethodP2P},
+			Capabilities: []strategy.Capability{strategy.CapabilityRefund},
+			Strategy:     s,
+		})
+
+		order := newManualPaymentOrder(entity.PaymentMethodP2P)
+		_ = order.UpdatePaymentStatus(entity.PaymentStatusDone, "")
+
+		payment := entity.NewPayment(1, "transaction", order.GetId(), "liqpay", entity.NewPrice(10050, 0, 0, entity.NewCurrencySnapshot("USD", 27.35)), entity.PaymentStatusDone, 0, 0)
+
+		refunds := &refundRepositoryStub{refunded: test.refunded, pending: test.pending}
+		n := notification.NewNotificationService(make(chan sms.Message, 10), make(chan telegram.Message, 10), nil, "", "%d", "%d")
+		pc := strategy.NewPaymentContext(r, strategy.NewDefaultStrategy(nil))
+
+		uc := NewOrderUseCase(&orderRepositoryStub{order: order}, nil, nil, &paymentRepositoryStub{payment: payment}, refunds, nil, nil, unitOfWorkStub{}, nil, nil, stock.NewStockService(&stockRepositoryStub{}), nil, nil, nil, n, pc)
+
+		refund, err := uc.Refund(context.Background(), refundForm{amount: test.amount})
+
+		assert.Equal(t, test.status, payment.GetStatus(), test.tag)
+
+		if test.hasError {
+			assert.Error(t, err, test.tag)
+			assert.Nil(t, refunds.created, test.tag)
+			assert.Equal(t, 0, s.calls, test.tag)
+			continue
+		}
+
+		assert.NoError(t, err, test.tag)
+		assert.Equal(t, test.expected, refund.GetPrice().GetInCent(), test.tag)
+		assert.Equal(t, "USD", refund.GetPrice().GetCurrency().GetISO(), test.tag)
+		assert.Equal(t, test.status, order.GetPayment().GetStatus(), test.tag)
+	}
+}
+
+func TestOrderUserCase_orderCurrency(t *testing.T) {
+
+	r := strategy.NewRegistry()
+
+	_ = r.Register(&strategy.Provider{
+		Name:         "p2p",
+		Methods:      []string{entity.PaymentMethodP2P},
+		Capabilities: []strategy.Capability{strategy.CapabilityInit},
+		Currencies:   []string{"USD"},
+		Strategy:     strategy.NewP2PStrategy(nil, nil),
+	})
+
+	pc := strategy.NewPaymentContext(r, strategy.NewDefaultStrategy(nil))
+	uc := NewOrderUseCase(&orderRepositoryStub{}, nil, nil, nil, nil, nil, nil, unitOfWorkStub{}, nil, nil, nil, nil, nil, nil, nil, pc)
+
+	p2p := entity.NewPaymentMethod(1, "p2p", entity.PaymentMethodP2P)
+	partsPay := entity.NewPaymentMethod(2, "parts pay", entity.PaymentMethodPartsPay)
+
+	c, err := uc.orderCurrency(context.Background(), p2p, "USD")
+	assert.NoError(t, err, "t1")
+	assert.Equal(t, "USD", c.GetISO(), "t2")
+	assert.Equal(t, 3656, c.FromBase(100000), "t3")
+
+	c, err = uc.orderCurrency(context.Background(), partsPay, "")
+	assert.NoError(t, err, "t4")
+	assert.True(t, c.IsBase(), "t5")
+
+	var methodErr *checkout.PaymentMethodError
+
+	_, err = uc.orderCurrency(context.Background(), partsPay, "USD")
+	assert.True(t, errors.As(err, &methodErr), "t6")
+	assert.Equal(t, entity.PaymentRuleReasonCurrency, methodErr.Reason, "t7")
+
+	_, err = uc.orderCurrency(context.Background(), p2p, "EUR")
+	assert.Error(t, err, "t8")
+}
+
+type orderChangeRepositoryStub struct {
+	checkout.IOrderChangeRepository
+	created int
+}
+
+func (r *orderChangeRepositoryStub) Create(ctx context.Context, c *entity.OrderChange) error {
+	r.created++
+	return nil
+}
+
+type editOrderForm struct {
+	orderId int
+	client  checkout.ClientForm
+}
+
+func (f editOrderForm) GetOrderId() int {
+	return f.orderId
+}
+
+func (f editOrderForm) GetOperator() string {
+	return "operator"
+}
+
+func (f editOrderForm) GetComment() string {
+	return ""
+}
+
+func (f editOrderForm) GetClient() checkout.ClientForm {
+	return f.client
+}
+
+func (f editOrderForm) GetDelivery() checkout.EditDeliveryForm {
+	return nil
+}
+
+func (f editOrderForm) GetItems() []checkout.EditOrderItemForm {
+	return nil
+}
+
+type clientForm struct {
+	fio   string
+	phone string
+}
+
+func (f clientForm) GetFio() string {
+	return f.fio
+}
+
+func (f clientForm) GetPhone() string {
+	return f.phone
+}
+
+func TestOrderUserCase_EditOrder(t *testing.T) {
+
+	r := strategy.NewRegistry()
+
+	_ = r.Register(&strategy.Provider{
+		Name:         "liqpay",
+		Methods:      []string{entity.PaymentMethodP2P},
+		Capabilities: []strategy.Capability{strategy.CapabilityInit},
+		Strategy:     strategy.NewP2PStrategy(nil, nil),
+	})
+
+	pc := strategy.NewPaymentContext(r, strategy.NewDefaultStrategy(nil))
+
+	tests := []struct {
+		tag      string
+		provider string
+		status   int
+		editable bool
+	}{
+		{"t1", "liqpay", entity.PaymentStatusNew, false},
+		{"t2", "liqpay", entity.PaymentStatusPending, false},
+		{"t3", "liqpay", entity.PaymentStatusFailed, true},
+		{"t4", "none", entity.PaymentStatusNew, true},
+		{"t5", "", entity.PaymentStatusNew, true},
+	}
+
+	for _, test := range tests {
+		order := newManualPaymentOrder(entity.PaymentMethodP2P)
+		price := order.GetPrice()
+		payment := entity.NewPayment(1, "transaction", order.GetId(), test.provider, &price, test.status, 0, 0)
+
+		orders := &orderRepositoryStub{order: order}
+		changes := &orderChangeRepositoryStub{}
+
+		uc := NewOrderUseCase(orders, nil, nil, &paymentRepositoryStub{payment: payment}, nil, nil, nil, unitOfWorkStub{}, changes, nil, nil, nil, nil, nil, nil, pc)
+
+		_, err := uc.EditOrder(context.Background(), editOrderForm{orderId: order.GetId(), client: clientForm{"new customer", "380501234567"}})
+
+		if test.editable == false {
+			var editErr *checkout.OrderNotEditableError
+
+			assert.True(t, errors.As(err, &editErr), test.tag)
+			assert.Equal(t, test.provider, editErr.Provider, test.tag)
+			assert.Equal(t, 0, orders.saved, test.tag)
+			continue
+		}
+
+		assert.NoError(t, err, test.tag)
+		assert.Equal(t, "new customer", order.GetCustomer().GetName(), test.tag)
+		assert.Equal(t, 1, orders.saved, test.tag)
+		assert.Equal(t, 1, changes.created, test.tag)
+	}
+}
+
+type waybillServiceStub struct {
+	err     error
+	created int
+}
+
+func (s *waybillServiceStub) Create(ctx context.Context, order entity.Order, parcel *entity.Parcel) (*entity.Waybill, error) {
+	s.created++
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return entity.NewWaybill("ref", "20450000000000", 7000, "2026-10-20"), nil
+}
+
+type createWaybillForm struct {
+	orderId int
+}
+
+func (f createWaybillForm) GetOrderId() int {
+	return f.orderId
+}
+
+func (f createWaybillForm) GetWeight() float64 {
+	return 1
+}
+
+func (f createWaybillForm) GetSeats() int {
+	return 0
+}
+
+func (f createWaybillForm) GetDeclaredValue() int {
+	return 0
+}
+
+func (f createWaybillForm) GetDescription() string {
+	return ""
+}
+
+func TestOrderUserCase_CreateWaybill(t *testing.T) {
+
+	tests := []struct {
+		tag         string
+		requestedAt int64
+		err         error
+		created     int
+		saved       int
+		number      string
+		hasError    bool
+	}{
+		{"t1", 0, nil, 1, 2, "20450000000000", false},
+		{"t2", time.Now().Unix(), nil, 0, 0, "", true},
+		{"t3", time.Now().Add(-waybillRequestTimeout).Unix(), nil, 1, 2, "20450000000000", false},
+		{"t4", 0, errors.New("carrier is down"), 1, 2, "", true},
+	}
+
+	for _, test := range tests {
+		order := newManualPaymentOrder(entity.PaymentMethodP2P)
+		order.SetWaybillRequest(test.requestedAt)
+
+		orders := &orderRepositoryStub{order: order}
+		waybills := &waybillServiceStub{err: test.err}
+
+		uc := NewOrderUseCase(orders, nil, nil, nil, nil, nil, nil, unitOfWorkStub{}, nil, nil, nil, nil, waybills, nil, nil, nil)
+
+		_, err := uc.CreateWaybill(context.Background(), createWaybillForm{orderId: order.GetId()})
+
+		assert.Equal(t, test.hasError, err != nil, test.tag)
+		assert.Equal(t, test.created, waybills.created, test.tag)
+		assert.Equal(t, test.saved, orders.saved, test.tag)
+		assert.Equal(t, test.number, order.GetDelivery().GetTrackingNumber(), test.tag)
+
+		if test.created > 0 {
+			assert.Equal(t, int64(0), order.GetDelivery().GetWaybillRequestedAt(), test.tag)
+		}
+	}
+}
+
+type trackingServiceStub struct {
+	statuses map[string]*entity.WaybillStatus
+}
+
+func (s trackingServiceStub) Track(ctx context.Context, numbers []string) (map[string]*entity.WaybillStatus, error) {
+	return s.statuses, nil
+}
+
+func TestOrderUserCase_TrackDeliveries(t *testing.T) {
+
+	order := newManualPaymentOrder(entity.PaymentMethodP2P)
+	order.SetTrackingNumber("20450000000000")
+
+	orders := &orderRepositoryStub{order: order}
+	tracking := trackingServiceStub{statuses: map[string]*entity.WaybillStatus{
+		"20450000000000": entity.NewWaybillStatus("20450000000000", entity.DeliveryStatusDelivery, "on the way"),
+	}}
+	n := notification.NewNotificationService(make(chan sms.Message, 10), make(chan telegram.Message, 10), nil, "", "%d", "%d")
+
+	uc := NewOrderUseCase(orders, nil, nil, nil, nil, nil, nil, unitOfWorkStub{}, nil, nil, nil, nil, nil, tracking, n, nil)
+
+	changed, err := uc.TrackDeliveries(context.Background(), time.Hour, 10)
+
+	assert.NoError(t, err, "t1")
+	assert.Equal(t, 1, changed, "t2")
+	assert.Equal(t, entity.DeliveryMethodNovaposhta, orders.filter.DeliveryMethod, "t3")
+	assert.Equal(t, []int{order.GetId()}, orders.tracked, "t4")
+	assert.Equal(t, entity.DeliveryStatusDelivery, order.GetDelivery().GetStatus(), "t5")
+	assert.Contains(t, orders.filter.DeliveryStatuses, entity.DeliveryStatusReadyToReceive, "t6")
+
+	// the parcel nobody picked up is returned, the order delivery is canceled and its stock is released
+	_ = order.UpdateDeliveryStatus(entity.DeliveryStatusReadyToReceive, "arrived")
+
+	stocks := &stockRepositoryStub{}
+	tracking.statuses["20450000000000"] = entity.NewWaybillStatus("20450000000000", entity.DeliveryStatusCanceled, "storage time is over")
+
+	uc = NewOrderUseCase(orders, nil, nil, nil, nil, nil, nil, unitOfWorkStub{}, nil, nil, stock.NewStockService(stocks), nil, nil, tracking, n, nil)
+
+	changed, err = uc.TrackDeliveries(context.Background(), time.Hour, 10)
+
+	assert.NoError(t, err, "t7")
+	assert.Equal(t, 1, changed, "t8")
+	assert.Equal(t, entity.DeliveryStatusCanceled, order.GetDelivery().GetStatus(), "t9")
+	assert.Equal(t, []int{order.GetId()}, stocks.released, "t10")
+}
+
+type paymentEventRepositoryStub struct {
+	checkout.IPaymentEventRepository
+	// existing is the event with the same hash received before
+	existing *entity.PaymentEvent
+	saved    *entity.PaymentEvent
+}
+
+func (r *paymentEventRepositoryStub) NextId() (int, error) {
+	return 1, nil
+}
+
+func (r *paymentEventRepositoryStub) Get(ctx context.Context, id int) (*entity.PaymentEvent, error) {
+	return r.existing, nil
+}
+
+func (r *paymentEventRepositoryStub) GetByHash(ctx context.Context, hash string) (*entity.PaymentEvent, error) {
+	return r.existing, nil
+}
+
+func (r *paymentEventRepositoryStub) Create(ctx context.Context, e *entity.PaymentEvent) (bool, error) {
+	return r.existing == nil, nil
+}
+
+func (r *paymentEventRepositoryStub) Save(ctx context.Context, e *entity.PaymentEvent) error {
+	r.saved = e
+	return nil
+}
+
+type callbackStrategyStub struct {
+	calls int
+}
+
+func (s *callbackStrategyStub) IsValidSignature(ctx *gin.Context) bool {
+	return true
+}
+
+func (s *callbackStrategyStub) GetTransactionId(ctx *gin.Context) string {
+	return "transaction"
+}
+
+func (s *callbackStrategyStub) ProcessingCallback(ctx *gin.Context) (strategy.IProcessingCallbackPaymentStrategyResponse, error) {
+	s.calls++
+	return strategy.NewProcessingCallbackPaymentStrategyResponse(entity.PaymentStatusDone, "paid", nil, false), nil
+}
+
+type providerCallbackForm struct{}
+
+func (f providerCallbackForm) GetProvider() string {
+	return "liqpay"
+}
+
+type replayPaymentEventForm struct{}
+
+func (f replayPaymentEventForm) GetEventId() int {
+	return 1
+}
+
+func newPaymentEvent(outcome string, updated time.Time) *entity.PaymentEvent {
+	return entity.NewPaymentEvent(1, "liqpay", "transaction", "application/json", nil, "{}", "hash", true, outcome, "", updated.Unix(), updated.Unix())
+}
+
+func newCallbackUseCase(events *paymentEventRepositoryStub, s *callbackStrategyStub) (*OrderUserCase, *entity.Payment) {
+
+	r := strategy.NewRegistry()
+
+	_ = r.Register(&strategy.Provider{
+		Name:         "liqpay",
+		Methods:      []string{entity.PaymentMethodP2P},
+		Capabilities: []strategy.Capability{strategy.CapabilityCallback},
+		Strategy:     s,
+	})
+
+	order := newManualPaymentOrder(entity.PaymentMethodP2P)
+	payment := entity.NewPayment(1, "transaction", order.GetId(), "liqpay", entity.NewPrice(10050, 0, 0, nil), entity.PaymentStatusPending, 0, 0)
+
+	n := notification.NewNotificationService(make(chan sms.Message, 10), make(chan telegram.Message, 10), nil, "", "%d", "%d")
+	pc := strategy.NewPaymentContext(r, strategy.NewDefaultStrategy(nil))
+
+	uc := NewOrderUseCase(&orderRepositoryStub{order: order}, nil, nil, &paymentRepositoryStub{payment: payment}, nil, events, nil, unitOfWorkStub{}, nil, nil, stock.NewStockService(&stockRepositoryStub{}), nil, nil, nil, n, pc)
+
+	return uc, payment
+}
+
+func newCallbackContext() *gin.Context {
+
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader("{}"))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	return ctx
+}
+
+func TestOrderUserCase_ProviderCallback(t *testing.T) {
+
+	tests := []struct {
+		tag       string
+		existing  *entity.PaymentEvent
+		processed bool
+	}{
+		{"t1", nil, true},
+		{"t2", newPaymentEvent(entity.PaymentEventOutcomeProcessed, time.Now()), false},
+		{"t3", newPaymentEvent(entity.PaymentEventOutcomeSkipped, time.Now()), false},
+		{"t4", newPaymentEvent(entity.PaymentEventOutcomeFailed, time.Now()), true},
+		{"t5", newPaymentEvent(entity.PaymentEventOutcomeNew, time.Now()), false},
+		{"t6", newPaymentEvent(entity.PaymentEventOutcomeNew, time.Now().Add(-2*paymentEventProcessTimeout)), true},
+	}
+
+	for _, test := range tests {
+		events := &paymentEventRepositoryStub{existing: test.existing}
+		s := &callbackStrategyStub{}
+		uc, payment := newCallbackUseCase(events, s)
+
+		_, err := uc.ProviderCallback(newCallbackContext(), providerCallbackForm{})
+
+		assert.NoError(t, err, test.tag)
+
+		if test.processed == false {
+			assert.Equal(t, 0, s.calls, test.tag)
+			assert.Nil(t, events.saved, test.tag)
+			assert.Equal(t, entity.PaymentStatusPending, payment.GetStatus(), test.tag)
+			continue
+		}
+
+		assert.Equal(t, 1, s.calls, test.tag)
+		assert.Equal(t, entity.PaymentEventOutcomeProcessed, events.saved.GetOutcome(), test.tag)
+		assert.Equal(t, entity.PaymentStatusDone, payment.GetStatus(), test.tag)
+	}
+}
+
+func TestOrderUserCase_ReplayPaymentEvent(t *testing.T) {
+
+	events := &paymentEventRepositoryStub{existing: newPaymentEvent(entity.PaymentEventOutcomeFailed, time.Now())}
+	s := &callbackStrategyStub{}
+	uc, payment := newCallbackUseCase(events, s)
+
+	event, err := uc.ReplayPaymentEvent(newCallbackContext(), replayPaymentEventForm{})
+
+	assert.NoError(t, err, "t1")
+	assert.Equal(t, entity.PaymentEventOutcomeProcessed, event.GetOutcome(), "t2")
+	assert.Equal(t, entity.PaymentStatusDone, payment.GetStatus(), "t3")
+
+	// replay of the processed event finds the payment in the same status and changes nothing
+	event, err = uc.ReplayPaymentEvent(newCallbackContext(), replayPaymentEventForm{})
+
+	assert.NoError(t, err, "t4")
+	assert.Equal(t, entity.PaymentEventOutcomeSkipped, event.GetOutcome(), "t5")
+	assert.Equal(t, 2, s.calls, "t6")
+}
+
+type holdStrategyStub struct {
+	calls int
+	// meanwhile runs during the provider call, e.g. the callback applied while provider responds
+	meanwhile func()
+}
+
+func (s *holdStrategyStub) Accept(ctx context.Context, order *entity.Order, payment *entity.Payment) (strategy.IAcceptHoldenPaymentStrategyResponse, error) {
+	s.calls++
+
+	if s.meanwhile != nil {
+		s.meanwhile()
+	}
+
+	return strategy.NewAcceptHoldenPaymentStrategyResponse(entity.PaymentStatusDone, "accepted", nil), nil
+}
+
+type acceptHoldenPaymentForm struct{}
+
+func (f acceptHoldenPaymentForm) GetTransactionId() string {
+	return "transaction"
+}
+
+func TestOrderUserCase_AcceptHoldenPayment(t *testing.T) {
+
+	tests := []struct {
+		tag       string
+		status    int
+		meanwhile bool
+		hasError  bool
+		calls     int
+		saved     int
+	}{
+		{"t1", entity.PaymentStatusWaitingConfirmation, false, false, 1, 1},
+		{"t2", entity.PaymentStatusPending, false, true, 0, 0},
+		{"t3", entity.PaymentStatusWaitingConfirmation, true, false, 1, 0},
+	}
+
+	for _, test := range tests {
+		r := strategy.NewRegistry()
+		s := &holdStrategyStub{}
+
+		_ = r.Register(&strategy.Provider{
+			Name:         "liqpay",
+			Methods:      []string{entity.PaymentMethodP2P},
+			Capabilities: []strategy.Capability{strategy.CapabilityAccept},
+			Strategy:     s,
+		})
+
+		order := newManualPaymentOrder(entity.PaymentMethodP2P)
+		_ = order.UpdatePaymentStatus(test.status, "")
+
+		payment := entity.NewPayment(1, "transaction", order.GetId(), "liqpay", entity.NewPrice(10050, 0, 0, nil), test.status, 0, 0)
+
+		if test.meanwhile {
+			s.meanwhile = func() {
+				_ = payment.UpdateStatus(entity.PaymentStatusDone)
+				_ = order.UpdatePaymentStatus(entity.PaymentStatusDone, "callback")
+			}
+		}
+
+		payments := &paymentRepositoryStub{payment: payment}
+		n := notification.NewNotificationService(make(chan sms.Message, 10), make(chan telegram.Message, 10), nil, "", "%d", "%d")
+		pc := strategy.NewPaymentContext(r, strategy.NewDefaultStrategy(nil))
+
+		uc := NewOrderUseCase(&orderRepositoryStub{order: order}, nil, nil, payments, nil, nil, nil, unitOfWorkStub{}, nil, nil, stock.NewStockService(&stockRepositoryStub{}), nil, nil, nil, n, pc)
+
+		err := uc.AcceptHoldenPayment(context.Background(), acceptHoldenPaymentForm{})
+
+		assert.Equal(t, test.hasError, err != nil, test.tag)
+		assert.Equal(t, test.calls, s.calls, test.tag)
+		assert.Equal(t, test.saved, payments.saved, test.tag)
+
+		if test.hasError == false {
+			assert.Equal(t, entity.PaymentStatusDone, payment.GetStatus(), test.tag)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/wowucco/G3/internal/entity"
+	"time"
 )
 
 type IInitPaymentResponse interface {
@@ -23,7 +24,23 @@ type IProviderCallbackPaymentResponse interface {
 type IOrderUseCase interface {
 	Create(ctx context.Context, form CreateOrderForm) (*entity.Order, error)
 	OrderInfo(ctx context.Context, form OrderIdForm) (*entity.Order, error)
+	Orders(ctx context.Context, form IOrdersFilterForm, offset, limit int) ([]*entity.Order, error)
+	OrdersCount(ctx context.Context, form IOrdersFilterForm) (int, error)
+	EditOrder(ctx context.Context, form IEditOrderForm) (*entity.Order, error)
+	OrderChanges(ctx context.Context, form OrderIdForm) ([]*entity.OrderChange, error)
+	UpdateDeliveryStatus(ctx context.Context, form IUpdateDeliveryStatusForm) (*entity.Order, error)
+	CreateWaybill(ctx context.Context, form ICreateWaybillForm) (*entity.Waybill, error)
 	InitPayment(ctx context.Context, form InitPaymentForm) (IInitPaymentResponse, error)
+	Invoice(ctx context.Context, form IInvoiceForm) (*entity.Invoice, error)
+	Installments(ctx context.Context, form IInstallmentsForm) ([]*entity.Installment, error)
 	AcceptHoldenPayment(ctx context.Context, form IAcceptHoldenPaymentForm) error
+	CancelHoldenPayment(ctx context.Context, form ICancelHoldenPaymentForm) error
+	ConfirmManualPayment(ctx context.Context, form IConfirmManualPaymentForm) error
+	Refund(ctx context.Context, form IRefundPaymentForm) (*entity.Refund, error)
 	ProviderCallback(ctx *gin.Context, form IProviderCallbackPaymentForm) (IProviderCallbackPaymentResponse, error)
+	PaymentEvents(ctx context.Context, form IPaymentEventsForm) ([]*entity.PaymentEvent, error)
+	ReplayPaymentEvent(ctx *gin.Context, form IReplayPaymentEventForm) (*entity.PaymentEvent, error)
+	ReconcilePayments(ctx context.Context, staleAfter, maxAge time.Duration, limit int) (int, error)
+	TrackDeliveries(ctx context.Context, maxAge time.Duration, limit int) (int, error)
+	ReleaseExpiredReservations(ctx context.Context, maxAge time.Duration) (int, error)
 }
@@ -14,7 +14,9 @@ import (
 
 type DeliveryReadRepository interface {
 	// mix
-	GetDeliveryInfoByCityId(ctx context.Context, id string) ([]*entity.DeliveryInfo, error)
+	// GetDeliveryInfoByCityId lists only payment methods eligible for the cart unless it's nil,
+	// carrier delivery estimate needs the cart too. Filter limits carrier warehouses only.
+	GetDeliveryInfoByCityId(ctx context.Context, id string, cart *entity.PaymentCart, filter entity.WarehouseFilter) ([]*entity.DeliveryInfo, error)
 
 	// Psql
 	// GetDeliveryMethodsByCity(ctx context.Context, city entity.City) ([]*entity.DeliveryMethod, error)
@@ -25,6 +27,15 @@ type DeliveryReadRepository interface {
 	GetCityById(ctx context.Context, id string) (*entity.City, error)
 	SearchCity(ctx context.Context, text string) ([]*entity.City, error)
 
+	// GetCourierTimeSlots returns slots of courier delivery in the city with orders booked for the date,
+	// date is in entity.DeliverySlotDateLayout
+	GetCourierTimeSlots(ctx context.Context, cityId, date string) ([]*entity.DeliveryTimeSlot, error)
+
 	GetDeliveryMethodBySlug(slug string) (*entity.DeliveryMethod, error)
 	GetPaymentMethodBySlug(slug string) (*entity.PaymentMethod, error)
 }
+
+// DeliverySyncRepository imports carrier cities and warehouses the delivery read repository is served from
+type DeliverySyncRepository interface {
+	SyncNovaposhta(ctx context.Context) error
+}
@@ -162,7 +162,39 @@ func (d ESDeliveryReadRepository) baseQueryToEs(ctx context.Context, q map[strin
 	return result, nil
 }
 
-func (d ESDeliveryReadRepository) getWarehousesForNovaposhtaByCity(ctx context.Context, city entity.City) ([]NPWarehouse, error) {
+// getWarehousesForNovaposhtaByCity filters warehouses by type and weight, open hours are checked by caller
+func (d ESDeliveryReadRepository) getWarehousesForNovaposhtaByCity(ctx context.Context, city entity.City, filter entity.WarehouseFilter) ([]NPWarehouse, error) {
+
+	must := []interface{}{
+		map[string]interface{}{
+			"match": map[string]interface{}{
+				"cityId": map[string]string{
+					"query":            city.ID,
+					"zero_terms_query": "all",
+					"operator":         "and",
+				},
+			},
+		},
+	}
+
+	if filter.Type != "" {
+		must = append(must, map[string]interface{}{
+			"term": map[string]string{
+				"type": filter.Type,
+			},
+		})
+	}
+
+	if filter.Weight > 0 {
+		must = append(must, map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should": []interface{}{
+					map[string]interface{}{"range": map[string]interface{}{"totalMaxWeightAllowed": map[string]int{"gte": filter.Weight}}},
+					map[string]interface{}{"term": map[string]int{"totalMaxWeightAllowed": 0}},
+				},
+			},
+		})
+	}
 
 	q := map[string]interface{}{
 		"_source": []string{
@@ -172,6 +204,8 @@ func (d ESDeliveryReadRepository) getWarehousesForNovaposhtaByCity(ctx context.C
 			"phone",
 			"number",
 			"totalMaxWeightAllowed",
+			"type",
+			"schedule",
 		},
 		//"sort": []interface{}{
 		//	map[string]interface{}{
@@ -183,12 +217,8 @@ func (d ESDeliveryReadRepository) getWarehousesForNovaposhtaByCity(ctx context.C
 		//	},
 		//},
 		"query": map[string]interface{}{
-			"match": map[string]interface{}{
-				"cityId": map[string]string{
-					"query":            city.ID,
-					"zero_terms_query": "all",
-					"operator":         "and",
-				},
+			"bool": map[string]interface{}{
+				"must": must,
 			},
 		},
 	}
@@ -196,57 +226,31 @@ func (d ESDeliveryReadRepository) getWarehousesForNovaposhtaByCity(ctx context.C
 	var buf bytes.Buffer
 
 	if err := json.NewEncoder(&buf).Encode(q); err != nil {
-		log.Fatalf("Error encoding query: %s", err)
-		return nil, err
+		return nil, errors.New(fmt.Sprintf("[novaposhta warehouses][city %s][encode query][%v]", city.ID, err))
 	}
 
 	res, err := d.es.Search(
-		d.es.Search.WithContext(context.Background()),
+		d.es.Search.WithContext(ctx),
 		d.es.Search.WithIndex(ESDeliveryIndex),
 		d.es.Search.WithDocumentType(ESDeliveryWarehouseDocType),
 		d.es.Search.WithSize(1000),
 		d.es.Search.WithBody(&buf),
-		d.es.Search.WithPretty(),
 	)
 
-	defer res.Body.Close()
-
 	if err != nil {
-		log.Fatalf("Error getting response: %s", err)
-		return nil, err
+		return nil, errors.New(fmt.Sprintf("[novaposhta warehouses][city %s][search][%v]", city.ID, err))
 	}
 
-	if res.IsError() {
-		var e map[string]interface{}
-		if err := json.NewDecoder(res.Body).Decode(&e); err != nil {
-			log.Fatalf("Error parsing the response body: %s", err)
-			return nil, err
-		} else {
-			// Print the response status and error information.
-			log.Fatalf("[%s] %s: %s",
-				res.Status(),
-				e["error"].(map[string]interface{})["type"],
-				e["error"].(map[string]interface{})["reason"],
-			)
+	defer res.Body.Close()
 
-			return nil, errors.New(fmt.Sprintf("[%s] %s: %s", res.Status(),
-				e["error"].(map[string]interface{})["type"],
-				e["error"].(map[string]interface{})["reason"]))
-		}
+	if res.IsError() {
+		return nil, errors.New(fmt.Sprintf("[novaposhta warehouses][city %s][search][%s]", city.ID, res.String()))
 	}
 
 	var result map[string]interface{}
 
 	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		log.Fatalf("Error parsing the response body: %s", err)
-		return nil, err
-	}
-
-
-
-	if err != nil {
-		log.Fatalf("Error encoding query: %s", err)
-		return nil, err
+		return nil, errors.New(fmt.Sprintf("[novaposhta warehouses][city %s][decode response][%v]", city.ID, err))
 	}
 
 	i := 0
@@ -257,6 +261,14 @@ func (d ESDeliveryReadRepository) getWarehousesForNovaposhtaByCity(ctx context.C
 		t, _ := strconv.Atoi(fmt.Sprintf("%v", hit.(map[string]interface{})["_source"].(map[string]interface{})["totalMaxWeightAllowed"]))
 		n, _ := strconv.Atoi(fmt.Sprintf("%v", hit.(map[string]interface{})["_source"].(map[string]interface{})["number"]))
 
+		schedule := make(map[string]string)
+
+		if sch, ok := hit.(map[string]interface{})["_source"].(map[string]interface{})["schedule"].(map[string]interface{}); ok {
+			for day, hours := range sch {
+				schedule[day] = fmt.Sprintf("%v", hours)
+			}
+		}
+
 		warehouses[i] = NPWarehouse{
 			ID:                           fmt.Sprintf("%v", hit.(map[string]interface{})["_source"].(map[string]interface{})["id"]),
 			CityID:                       fmt.Sprintf("%v", hit.(map[string]interface{})["_source"].(map[string]interface{})["cityId"]),
@@ -274,52 +286,75 @@ func (d ESDeliveryReadRepository) getWarehousesForNovaposhtaByCity(ctx context.C
 			SettlementTypeDescription:    fmt.Sprintf("%v", hit.(map[string]interface{})["_source"].(map[string]interface{})["settlementTypeDescription"]),
 			Number:                       n,
 			TotalMaxWeightAllowed:        t,
+			Type:                         fmt.Sprintf("%v", hit.(map[string]interface{})["_source"].(map[string]interface{})["type"]),
+			Schedule:                     schedule,
 		}
 
-		log.Printf("Error getting response: %v\n", warehouses[i])
-
 		i++
 	}
 
 	return warehouses, nil
 }
 
-func (d ESDeliveryReadRepository) indexCitiesWarehouses(city entity.City, warehouses []NPWarehouse) error {
+// bulkIndex replaces documents of the type by ids
+func (d ESDeliveryReadRepository) bulkIndex(ctx context.Context, docType string, docs map[string]interface{}) error {
 
-	for _, warehouse := range warehouses {
+	if len(docs) == 0 {
+		return nil
+	}
 
-		var buf bytes.Buffer
+	var buf bytes.Buffer
 
-		if err := json.NewEncoder(&buf).Encode(warehouse); err != nil {
-			log.Fatalf("Error encoding query: %s", err)
+	enc := json.NewEncoder(&buf)
+
+	for id, doc := range docs {
+		meta := map[string]interface{}{"index": map[string]string{"_id": id}}
+
+		if err := enc.Encode(meta); err != nil {
+			return err
+		}
+		if err := enc.Encode(doc); err != nil {
 			return err
 		}
+	}
 
-		res, err := d.es.Index(ESDeliveryIndex, &buf, d.es.Index.WithDocumentID(warehouse.ID), d.es.Index.WithDocumentType(ESDeliveryWarehouseDocType))
+	res, err := d.es.Bulk(&buf, d.es.Bulk.WithContext(ctx), d.es.Bulk.WithIndex(ESDeliveryIndex), d.es.Bulk.WithDocumentType(docType))
 
-		if err != nil {
-			log.Fatalf("warhouse indexing get response error: %s", err)
-		}
+	if err != nil {
+		return err
+	}
 
-		res.Body.Close()
+	defer res.Body.Close()
 
-		if res.IsError() {
-			log.Printf("[%s] Error indexing document ID=%s", res.Status(), warehouse.ID)
-		}
+	if res.IsError() {
+		return errors.New(fmt.Sprintf("[%s] bulk index of %s failed", res.Status(), docType))
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return err
+	}
+
+	if result.Errors == true {
+		return errors.New(fmt.Sprintf("bulk index of %s has failed documents", docType))
 	}
 
 	return nil
 }
 
-func (d ESDeliveryReadRepository) deleteWarehousesByCity(city entity.City) error {
+// deleteNotSyncedSince removes documents of the type the import since syncedAt didn't update
+func (d ESDeliveryReadRepository) deleteNotSyncedSince(ctx context.Context, docType string, syncedAt int64) error {
 
 	q := map[string]interface{}{
 		"query": map[string]interface{}{
-			"match": map[string]interface{}{
-				"cityId": map[string]string{
-					"query":            city.ID,
-					"zero_terms_query": "all",
-					"operator":         "and",
+			"bool": map[string]interface{}{
+				"must_not": map[string]interface{}{
+					"range": map[string]interface{}{
+						"syncedAt": map[string]int64{"gte": syncedAt},
+					},
 				},
 			},
 		},
@@ -328,34 +363,19 @@ func (d ESDeliveryReadRepository) deleteWarehousesByCity(city entity.City) error
 	var buf bytes.Buffer
 
 	if err := json.NewEncoder(&buf).Encode(q); err != nil {
-		log.Fatalf("Error encoding query: %s", err)
 		return err
 	}
 
-	res, err := d.es.DeleteByQuery([]string{ESDeliveryIndex}, &buf, d.es.DeleteByQuery.WithDocumentType(ESDeliveryWarehouseDocType))
-	defer res.Body.Close()
-
-	return err
-}
-
-func (d ESDeliveryReadRepository) reindexCitiesWarehouses(city entity.City, warehouses []NPWarehouse) error {
-
-	err := d.deleteWarehousesByCity(city)
+	res, err := d.es.DeleteByQuery([]string{ESDeliveryIndex}, &buf, d.es.DeleteByQuery.WithContext(ctx), d.es.DeleteByQuery.WithDocumentType(docType))
 
 	if err != nil {
-		log.Fatalf("Error delete query: %s", err)
 		return err
 	}
 
-	if len(warehouses) < 1 {
-		return nil
-	}
-
-	err = d.indexCitiesWarehouses(city, warehouses)
+	defer res.Body.Close()
 
-	if err != nil {
-		log.Fatalf("Error index query: %s", err)
-		return err
+	if res.IsError() {
+		return errors.New(fmt.Sprintf("[%s] delete of not synced %s failed", res.Status(), docType))
 	}
 
 	return nil
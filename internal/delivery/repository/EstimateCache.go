@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/wowucco/G3/internal/entity"
+	"sync"
+	"time"
+)
+
+// estimateCacheTTL is how long carrier estimates are reused, tariffs and delivery dates change daily at most
+const estimateCacheTTL = 30 * time.Minute
+
+// estimateTimeout limits the carrier call, delivery info is returned without estimate when it's exceeded
+const estimateTimeout = 3 * time.Second
+
+func newEstimateCache(ttl time.Duration) *estimateCache {
+
+	return &estimateCache{ttl: ttl, items: make(map[string]estimateCacheItem)}
+}
+
+// estimateCache keeps carrier estimates per city and delivery method. Cost depends on the parcel weight
+// and declared value, so items count and amount of the cart are part of the key as well.
+type estimateCache struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	items map[string]estimateCacheItem
+}
+
+type estimateCacheItem struct {
+	estimate *entity.DeliveryEstimate
+	expires  time.Time
+}
+
+func (c *estimateCache) get(city entity.City, method entity.DeliveryMethod, cart entity.PaymentCart, now time.Time) (*entity.DeliveryEstimate, bool) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.items[estimateCacheKey(city, method, cart)]
+
+	if ok == false || now.After(item.expires) {
+		return nil, false
+	}
+
+	return item.estimate, true
+}
+
+// set stores the estimate and drops expired ones, so the cache doesn't grow with carts of the past
+func (c *estimateCache) set(city entity.City, method entity.DeliveryMethod, cart entity.PaymentCart, estimate *entity.DeliveryEstimate, now time.Time) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, v := range c.items {
+		if now.After(v.expires) {
+			delete(c.items, k)
+		}
+	}
+
+	c.items[estimateCacheKey(city, method, cart)] = estimateCacheItem{estimate: estimate, expires: now.Add(c.ttl)}
+}
+
+func estimateCacheKey(city entity.City, method entity.DeliveryMethod, cart entity.PaymentCart) string {
+	return fmt.Sprintf("%s|%s|%d|%d", city.ID, method.Slug, cart.Items, cart.Amount)
+}
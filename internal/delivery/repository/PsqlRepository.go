@@ -2,15 +2,19 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	dbx "github.com/go-ozzo/ozzo-dbx"
 	"github.com/wowucco/G3/internal/entity"
+	"time"
 )
 
 const tableNameDeliveryMethods = "shop_delivery_method"
 const tableNamePaymentMethods = "shop_payment_method"
 const tableNameDeliveryAssignCity = "shop_delivery_assignment_city"
 const tableNameDeliveryAssignPayment = "shop_delivery_assignment_payment"
+const tableNameOrders = "shop_order"
+const tableNameOrderDeliverySlots = "shop_order_delivery_slot"
 
 func (d PsqlDeliveryReadRepository) getDeliveryMethodsByCity(ctx context.Context, city entity.City) ([]entity.DeliveryMethod, error) {
 
@@ -81,11 +85,7 @@ func (d PsqlDeliveryReadRepository) getPaymentMethodsByDeliveryMethodId(ctx cont
 	pMethods := make([]entity.PaymentMethod, len(rows))
 
 	for k, v := range rows {
-		pMethods[k] = entity.PaymentMethod{
-			ID:   v.ID,
-			Name: v.Name,
-			Slug: v.Slug,
-		}
+		pMethods[k] = toPaymentMethodEntity(v)
 	}
 
 	return pMethods, nil
@@ -105,11 +105,30 @@ func (d PsqlDeliveryReadRepository) getPaymentMethodBySlug(slug string) (*entity
 		return nil, err
 	}
 
-	return &entity.PaymentMethod{
+	m := toPaymentMethodEntity(row)
+
+	return &m, nil
+}
+
+func toPaymentMethodEntity(row PaymentMethod) entity.PaymentMethod {
+
+	var excluded []int
+
+	if row.ExcludedGroups.Valid == true {
+		_ = json.Unmarshal([]byte(row.ExcludedGroups.String), &excluded)
+	}
+
+	return entity.PaymentMethod{
 		ID:   row.ID,
 		Name: row.Name,
 		Slug: row.Slug,
-	}, nil
+		Rules: entity.PaymentMethodRules{
+			MinAmount:      int(row.MinAmount.Int64),
+			MinParts:       int(row.MinParts.Int64),
+			MaxParts:       int(row.MaxParts.Int64),
+			ExcludedGroups: excluded,
+		},
+	}
 }
 
 func (d PsqlDeliveryReadRepository) getWarehousesForYourselfByCity(ctx context.Context, city entity.City) ([]entity.Warehouse, error) {
@@ -148,6 +167,95 @@ func (d PsqlDeliveryReadRepository) getWarehousesForYourselfByCity(ctx context.C
 	return w, nil
 }
 
+// getDeliveryEstimateByTariff returns nil when the tariff isn't configured for the city
+func (d PsqlDeliveryReadRepository) getDeliveryEstimateByTariff(ctx context.Context, deliveryMethod entity.DeliveryMethod, city entity.City) (*entity.DeliveryEstimate, error) {
+
+	var row DeliveryTariff
+
+	err := d.db.Select("d.cost", "d.delivery_days", "d.tariffs").
+		From(tableWithAlias(tableNameDeliveryMethods, "d")).
+		Where(dbx.NewExp("d.id={:id}", dbx.Params{"id": deliveryMethod.ID})).
+		One(&row)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tariff := CityTariff{Cost: int(row.Cost.Int64), Days: int(row.Days.Int64)}
+	configured := row.Cost.Valid
+
+	if row.Tariffs.Valid == true {
+		var tariffs map[string]CityTariff
+		_ = json.Unmarshal([]byte(row.Tariffs.String), &tariffs)
+
+		if t, ok := tariffs[city.ID]; ok {
+			tariff = t
+			configured = true
+		}
+	}
+
+	if configured == false {
+		return nil, nil
+	}
+
+	return entity.NewDeliveryEstimate(entity.NewPrice(tariff.Cost, 0, 0, nil), time.Now().AddDate(0, 0, tariff.Days)), nil
+}
+
+// getCourierTimeSlotsByCity returns nothing when slots aren't configured for the city
+func (d PsqlDeliveryReadRepository) getCourierTimeSlotsByCity(ctx context.Context, cityId, date string) ([]*entity.DeliveryTimeSlot, error) {
+
+	var row DeliveryAssignmentCity
+
+	err := d.db.Select("dac.*").
+		From(tableWithAlias(tableNameDeliveryAssignCity, "dac")).
+		InnerJoin(tableWithAlias(tableNameDeliveryMethods, "d"), dbx.NewExp("dac.delivery_method_id = d.id")).
+		Where(dbx.NewExp("d.slug={:slug}", dbx.Params{"slug": entity.DeliveryMethodCourier})).
+		AndWhere(dbx.NewExp("dac.city_token={:token}", dbx.Params{"token": cityId})).
+		One(&row)
+
+	if err == sql.ErrNoRows || (err == nil && row.TimeSlots.Valid == false) {
+		return make([]*entity.DeliveryTimeSlot, 0), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var slots []TimeSlot
+
+	if err := json.Unmarshal([]byte(row.TimeSlots.String), &slots); err != nil {
+		return nil, err
+	}
+
+	var booked []BookedSlot
+
+	err = d.db.Select("s.slot", "COUNT(*) AS booked").
+		From(tableWithAlias(tableNameOrderDeliverySlots, "s")).
+		InnerJoin(tableWithAlias(tableNameOrders, "o"), dbx.NewExp("o.id = s.order_id")).
+		Where(dbx.HashExp{"s.city_token": cityId, "s.date": date}).
+		AndWhere(dbx.NewExp("o.delivery_status <> {:canceled}", dbx.Params{"canceled": entity.DeliveryStatusCanceled})).
+		GroupBy("s.slot").
+		All(&booked)
+
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(booked))
+
+	for _, b := range booked {
+		counts[b.Slot] = b.Booked
+	}
+
+	result := make([]*entity.DeliveryTimeSlot, len(slots))
+
+	for k, v := range slots {
+		result[k] = entity.NewDeliveryTimeSlot(v.Id, v.From, v.To, v.Capacity, counts[v.Id])
+	}
+
+	return result, nil
+}
+
 func tableWithAlias(tableName, alias string) string {
 	return tableName + " " + alias
 }
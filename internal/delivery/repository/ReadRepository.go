@@ -4,9 +4,10 @@ import (
 	"context"
 	"github.com/wowucco/G3/internal/entity"
 	"log"
+	"time"
 )
 
-func (d DeliveryReadRepository) GetDeliveryInfoByCityId(ctx context.Context, id string) ([]*entity.DeliveryInfo, error) {
+func (d DeliveryReadRepository) GetDeliveryInfoByCityId(ctx context.Context, id string, cart *entity.PaymentCart, filter entity.WarehouseFilter) ([]*entity.DeliveryInfo, error) {
 
 	city, err := d.GetCityById(ctx, id)
 
@@ -25,18 +26,47 @@ func (d DeliveryReadRepository) GetDeliveryInfoByCityId(ctx context.Context, id
 	for key, deliveryMethod := range deliveryMethods {
 
 		paymentMethods, _ := d.db.getPaymentMethodsByDeliveryMethodId(ctx, deliveryMethod.ID)
-		warehouses, _ := d.getWarehousesOfCityByDeliveryMethod(ctx, *city, deliveryMethod)
+
+		if cart != nil {
+			paymentMethods = eligiblePaymentMethods(paymentMethods, *cart)
+		}
+
+		warehouses, err := d.getWarehousesOfCityByDeliveryMethod(ctx, *city, deliveryMethod, filter)
+
+		if err != nil {
+			log.Printf("[error][delivery warehouses][city %s][method %s][%v]", city.ID, deliveryMethod.Slug, err)
+		}
+
+		estimate, err := d.getDeliveryEstimate(ctx, *city, deliveryMethod, cart)
+
+		if err != nil {
+			log.Printf("[error][delivery estimate][city %s][method %s][%v]", city.ID, deliveryMethod.Slug, err)
+		}
 
 		deliveryInfo[key] = &entity.DeliveryInfo{
 			DeliveryMethod: deliveryMethod,
 			PaymentMethods: paymentMethods,
 			Warehouses:     warehouses,
+			Estimate:       estimate,
 		}
 	}
 
 	return deliveryInfo, nil
 }
 
+func eligiblePaymentMethods(methods []entity.PaymentMethod, cart entity.PaymentCart) []entity.PaymentMethod {
+
+	eligible := make([]entity.PaymentMethod, 0, len(methods))
+
+	for _, m := range methods {
+		if m.IsEligible(cart) {
+			eligible = append(eligible, m)
+		}
+	}
+
+	return eligible
+}
+
 func (d DeliveryReadRepository) GetCityById(ctx context.Context, id string) (*entity.City, error) {
 
 	return d.es.getCityById(ctx, id)
@@ -47,6 +77,11 @@ func (d DeliveryReadRepository) SearchCity(ctx context.Context, text string) ([]
 	return d.es.searchCity(ctx, text)
 }
 
+func (d DeliveryReadRepository) GetCourierTimeSlots(ctx context.Context, cityId, date string) ([]*entity.DeliveryTimeSlot, error) {
+
+	return d.db.getCourierTimeSlotsByCity(ctx, cityId, date)
+}
+
 func (d DeliveryReadRepository) GetDeliveryMethodBySlug(slug string) (*entity.DeliveryMethod, error) {
 
 	return d.db.getDeliveryMethodBySlug(slug)
@@ -57,13 +92,13 @@ func (d DeliveryReadRepository) GetPaymentMethodBySlug(slug string) (*entity.Pay
 	return d.db.getPaymentMethodBySlug(slug)
 }
 
-func (d DeliveryReadRepository) getWarehousesOfCityByDeliveryMethod(ctx context.Context, city entity.City, deliveryMethod entity.DeliveryMethod) ([]entity.Warehouse, error) {
+func (d DeliveryReadRepository) getWarehousesOfCityByDeliveryMethod(ctx context.Context, city entity.City, deliveryMethod entity.DeliveryMethod, filter entity.WarehouseFilter) ([]entity.Warehouse, error) {
 
 	switch deliveryMethod.Slug {
 	case entity.DeliveryMethodYourself:
 		return d.db.getWarehousesForYourselfByCity(ctx, city)
 	case entity.DeliveryMethodNovaposhta:
-		return d.getWarehousesForNovaposhtaByCity(ctx, city)
+		return d.getWarehousesForNovaposhtaByCity(ctx, city, filter)
 	case entity.DeliveryMethodCourier:
 		fallthrough
 	default:
@@ -71,31 +106,69 @@ func (d DeliveryReadRepository) getWarehousesOfCityByDeliveryMethod(ctx context.
 	}
 }
 
-func (d DeliveryReadRepository) getWarehousesForNovaposhtaByCity(ctx context.Context, city entity.City) ([]entity.Warehouse, error) {
+// getDeliveryEstimate calculates carrier delivery by the cart, so it's unknown without cart.
+// Courier and yourself deliveries are estimated by shop tariffs.
+func (d DeliveryReadRepository) getDeliveryEstimate(ctx context.Context, city entity.City, deliveryMethod entity.DeliveryMethod, cart *entity.PaymentCart) (*entity.DeliveryEstimate, error) {
 
-	esr, err := d.es.getWarehousesForNovaposhtaByCity(ctx, city)
+	switch deliveryMethod.Slug {
+	case entity.DeliveryMethodNovaposhta:
+		if cart == nil || d.estimate == nil {
+			return nil, nil
+		}
+		return d.getCarrierEstimate(ctx, city, deliveryMethod, *cart)
+	case entity.DeliveryMethodYourself, entity.DeliveryMethodCourier:
+		return d.db.getDeliveryEstimateByTariff(ctx, deliveryMethod, city)
+	default:
+		return nil, nil
+	}
+}
 
-	if err != nil {
-		return make([]entity.Warehouse, 0), err
+// getCarrierEstimate reuses estimates of the same cart to the city, the carrier isn't waited longer
+// than estimateTimeout, so its outage doesn't block delivery info
+func (d DeliveryReadRepository) getCarrierEstimate(ctx context.Context, city entity.City, deliveryMethod entity.DeliveryMethod, cart entity.PaymentCart) (*entity.DeliveryEstimate, error) {
+
+	if e, ok := d.estimates.get(city, deliveryMethod, cart, time.Now()); ok == true {
+		return e, nil
 	}
 
-	if len(esr) > 0 {
-		return toWarehouseEntities(esr), nil
+	ctx, cancel := context.WithTimeout(ctx, estimateTimeout)
+	defer cancel()
+
+	e, err := d.estimate.Estimate(ctx, city, cart)
+
+	if err != nil {
+		return nil, err
 	}
 
-	npwh, err := d.np.getWarehousesForNovaposhtaByCity(ctx, city)
+	d.estimates.set(city, deliveryMethod, cart, e, time.Now())
+
+	return e, nil
+}
+
+// getWarehousesForNovaposhtaByCity reads the index the sync imports warehouses into
+func (d DeliveryReadRepository) getWarehousesForNovaposhtaByCity(ctx context.Context, city entity.City, filter entity.WarehouseFilter) ([]entity.Warehouse, error) {
+
+	esr, err := d.es.getWarehousesForNovaposhtaByCity(ctx, city, filter)
 
 	if err != nil {
 		return make([]entity.Warehouse, 0), err
 	}
 
-	go func() {
-		if e := d.es.reindexCitiesWarehouses(city, npwh); e != nil {
-			log.Fatalf("reindex warehouses error: %s", err)
+	w := toWarehouseEntities(esr)
+
+	if filter.OpenAt.IsZero() {
+		return w, nil
+	}
+
+	open := make([]entity.Warehouse, 0, len(w))
+
+	for _, v := range w {
+		if v.IsOpenAt(filter.OpenAt) {
+			open = append(open, v)
 		}
-	}()
+	}
 
-	return toWarehouseEntities(npwh), nil
+	return open, nil
 }
 
 func toWarehouseEntities(npwh []NPWarehouse) []entity.Warehouse {
@@ -110,6 +183,8 @@ func toWarehouseEntities(npwh []NPWarehouse) []entity.Warehouse {
 			Phone:     v.Phone,
 			Number:    v.Number,
 			MaxWeight: v.TotalMaxWeightAllowed,
+			Type:      v.Type,
+			Schedule:  v.Schedule,
 		}
 	}
 
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wowucco/G3/internal/entity"
+)
+
+type estimateServiceStub struct {
+	calls int
+	// hang makes the call wait for the context end like the carrier which doesn't respond
+	hang bool
+}
+
+func (s *estimateServiceStub) Estimate(ctx context.Context, city entity.City, cart entity.PaymentCart) (*entity.DeliveryEstimate, error) {
+	s.calls++
+
+	if s.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	return entity.NewDeliveryEstimate(entity.NewPrice(7000, 0, 0, nil), time.Now()), nil
+}
+
+func TestDeliveryReadRepository_getCarrierEstimate(t *testing.T) {
+	s := &estimateServiceStub{}
+	d := DeliveryReadRepository{estimate: s, estimates: newEstimateCache(time.Minute)}
+
+	city := entity.City{ID: "city"}
+	method := entity.DeliveryMethod{Slug: entity.DeliveryMethodNovaposhta}
+	cart := entity.PaymentCart{Amount: 10000, Items: 2}
+
+	e, err := d.getCarrierEstimate(context.Background(), city, method, cart)
+	assert.NoError(t, err, "t1")
+	assert.Equal(t, 7000, e.GetCost().GetInCent(), "t2")
+
+	_, _ = d.getCarrierEstimate(context.Background(), city, method, cart)
+	assert.Equal(t, 1, s.calls, "t3")
+
+	_, _ = d.getCarrierEstimate(context.Background(), city, method, entity.PaymentCart{Amount: 10000, Items: 3})
+	assert.Equal(t, 2, s.calls, "t4")
+
+	s.hang = true
+
+	start := time.Now()
+	e, err = d.getCarrierEstimate(context.Background(), entity.City{ID: "other"}, method, cart)
+	assert.Error(t, err, "t5")
+	assert.Nil(t, e, "t6")
+	assert.True(t, time.Since(start) < 2*estimateTimeout, "t7")
+}
+
+func TestEstimateCache(t *testing.T) {
+	c := newEstimateCache(time.Minute)
+	now := time.Now()
+
+	city := entity.City{ID: "city"}
+	method := entity.DeliveryMethod{Slug: entity.DeliveryMethodNovaposhta}
+	cart := entity.PaymentCart{Amount: 10000, Items: 2}
+
+	c.set(city, method, cart, entity.NewDeliveryEstimate(entity.NewPrice(7000, 0, 0, nil), now), now)
+
+	_, ok := c.get(city, method, cart, now.Add(59*time.Second))
+	assert.True(t, ok, "t1")
+
+	_, ok = c.get(city, method, cart, now.Add(61*time.Second))
+	assert.False(t, ok, "t2")
+
+	c.set(entity.City{ID: "other"}, method, cart, entity.NewDeliveryEstimate(entity.NewPrice(7000, 0, 0, nil), now), now.Add(2*time.Minute))
+	assert.Equal(t, 1, len(c.items), "t3")
+}
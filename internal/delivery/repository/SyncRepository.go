@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v5"
+	"github.com/wowucco/G3/internal/entity"
+	"github.com/wowucco/G3/pkg/novaposhta"
+	"strconv"
+	"time"
+)
+
+func NewDeliverySyncRepository(es *elasticsearch.Client, np *novaposhta.Client) *DeliverySyncRepository {
+	return &DeliverySyncRepository{
+		es: &ESDeliveryReadRepository{es: es},
+		np: np,
+	}
+}
+
+type DeliverySyncRepository struct {
+	es *ESDeliveryReadRepository
+	np *novaposhta.Client
+}
+
+// SyncNovaposhta imports all cities and warehouses into the delivery index page by page.
+// Documents the import didn't update are deleted only when the whole import succeeded.
+func (s DeliverySyncRepository) SyncNovaposhta(ctx context.Context) error {
+
+	syncedAt := time.Now().Unix()
+
+	cities, err := s.syncCities(ctx, syncedAt)
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[novaposhta sync][cities][%v]", err))
+	}
+
+	warehouses, err := s.syncWarehouses(ctx, syncedAt)
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("[novaposhta sync][warehouses][%v]", err))
+	}
+
+	if cities == 0 || warehouses == 0 {
+		return errors.New(fmt.Sprintf("[novaposhta sync][imported %d cities and %d warehouses]", cities, warehouses))
+	}
+
+	if err := s.es.deleteNotSyncedSince(ctx, ESDeliveryCityDocType, syncedAt); err != nil {
+		return errors.New(fmt.Sprintf("[novaposhta sync][delete cities][%v]", err))
+	}
+
+	if err := s.es.deleteNotSyncedSince(ctx, ESDeliveryWarehouseDocType, syncedAt); err != nil {
+		return errors.New(fmt.Sprintf("[novaposhta sync][delete warehouses][%v]", err))
+	}
+
+	return nil
+}
+
+func (s DeliverySyncRepository) syncCities(ctx context.Context, syncedAt int64) (int, error) {
+
+	count := 0
+
+	for page := 1; ; page++ {
+
+		cities, err := s.np.GetCities(ctx, page, novaposhta.AddressLimit)
+
+		if err != nil {
+			return count, err
+		}
+
+		docs := make(map[string]interface{}, len(cities))
+
+		for _, c := range cities {
+			docs[c.Ref] = NPCity{
+				ID:       c.Ref,
+				Name:     c.Description,
+				NameRu:   c.DescriptionRu,
+				Area:     c.Area,
+				SyncedAt: syncedAt,
+			}
+		}
+
+		if err := s.es.bulkIndex(ctx, ESDeliveryCityDocType, docs); err != nil {
+			return count, err
+		}
+
+		count += len(cities)
+
+		if len(cities) < novaposhta.AddressLimit {
+			return count, nil
+		}
+	}
+}
+
+func (s DeliverySyncRepository) syncWarehouses(ctx context.Context, syncedAt int64) (int, error) {
+
+	count := 0
+
+	for page := 1; ; page++ {
+
+		warehouses, err := s.np.GetWarehouses(ctx, page, novaposhta.AddressLimit)
+
+		if err != nil {
+			return count, err
+		}
+
+		docs := make(map[string]interface{}, len(warehouses))
+
+		for _, w := range warehouses {
+			docs[w.Ref] = toNPWarehouse(w, syncedAt)
+		}
+
+		if err := s.es.bulkIndex(ctx, ESDeliveryWarehouseDocType, docs); err != nil {
+			return count, err
+		}
+
+		count += len(warehouses)
+
+		if len(warehouses) < novaposhta.AddressLimit {
+			return count, nil
+		}
+	}
+}
+
+func toNPWarehouse(w novaposhta.Warehouse, syncedAt int64) NPWarehouse {
+
+	n, _ := strconv.Atoi(w.Number)
+	t, _ := strconv.ParseFloat(w.TotalMaxWeightAllowed, 64)
+
+	warehouseType := entity.WarehouseTypeBranch
+
+	if w.CategoryOfWarehouse == novaposhta.WarehouseCategoryPostomat {
+		warehouseType = entity.WarehouseTypePostomat
+	}
+
+	return NPWarehouse{
+		ID:                           w.Ref,
+		CityID:                       w.CityRef,
+		Name:                         w.Description,
+		NameRu:                       w.DescriptionRu,
+		ShortAddress:                 w.ShortAddress,
+		ShortAddressRu:               w.ShortAddressRu,
+		Phone:                        w.Phone,
+		CityDescription:              w.CityDescription,
+		CityDescriptionRu:            w.CityDescriptionRu,
+		SettlementRef:                w.SettlementRef,
+		SettlementDescription:        w.SettlementDescription,
+		SettlementAreaDescription:    w.SettlementAreaDescription,
+		SettlementRegionsDescription: w.SettlementRegionsDescription,
+		SettlementTypeDescription:    w.SettlementTypeDescription,
+		Number:                       n,
+		TotalMaxWeightAllowed:        int(t),
+		Type:                         warehouseType,
+		Schedule:                     w.Schedule,
+		SyncedAt:                     syncedAt,
+	}
+}
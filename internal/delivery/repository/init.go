@@ -3,32 +3,32 @@ package repository
 import (
 	"github.com/elastic/go-elasticsearch/v5"
 	dbx "github.com/go-ozzo/ozzo-dbx"
-	"github.com/wowucco/go-novaposhta"
+	"github.com/wowucco/G3/internal/delivery"
 )
 
 type DeliveryReadRepository struct {
 
 	db *PsqlDeliveryReadRepository
 	es *ESDeliveryReadRepository
-	np *NPDeliveryReadRepository
+
+	// estimate is nil when carrier delivery cost isn't calculated
+	estimate  delivery.IEstimateService
+	estimates *estimateCache
 }
 
 type ESDeliveryReadRepository struct {
 	es *elasticsearch.Client
 }
 
-type NPDeliveryReadRepository struct {
-	np *novaposhta.Client
-}
-
 type PsqlDeliveryReadRepository struct {
 	db *dbx.DB
 }
 
-func NewDeliveryReadRepository(db *dbx.DB, es *elasticsearch.Client, np *novaposhta.Client) *DeliveryReadRepository {
+func NewDeliveryReadRepository(db *dbx.DB, es *elasticsearch.Client, estimate delivery.IEstimateService) *DeliveryReadRepository {
 	return &DeliveryReadRepository{
 		db: &PsqlDeliveryReadRepository{db:db},
 		es: &ESDeliveryReadRepository{es:es},
-		np: &NPDeliveryReadRepository{np:np},
+		estimate: estimate,
+		estimates: newEstimateCache(estimateCacheTTL),
 	}
 }
@@ -11,11 +11,28 @@ type DeliveryMethod struct {
 	Width  sql.NullString `db:"width"`
 }
 
+// DeliveryTariff is the delivery cost in cents and days to deliver, tariffs json overrides them by city token,
+// e.g. {"8d5a980d-391c-11dd-90d9-001a92567626": {"cost": 5000, "days": 1}}
+type DeliveryTariff struct {
+	Cost    sql.NullInt64  `db:"cost"`
+	Days    sql.NullInt64  `db:"delivery_days"`
+	Tariffs sql.NullString `db:"tariffs"`
+}
+
+type CityTariff struct {
+	Cost int `json:"cost"`
+	Days int `json:"days"`
+}
+
 type PaymentMethod struct {
-	ID     int    `db:"id"`
-	Name   string `db:"name"`
-	Slug   string `db:"slug"`
-	Status bool   `db:"status"`
+	ID             int            `db:"id"`
+	Name           string         `db:"name"`
+	Slug           string         `db:"slug"`
+	Status         bool           `db:"status"`
+	MinAmount      sql.NullInt64  `db:"min_amount"`
+	MinParts       sql.NullInt64  `db:"min_parts"`
+	MaxParts       sql.NullInt64  `db:"max_parts"`
+	ExcludedGroups sql.NullString `db:"excluded_groups"`
 }
 
 type Warehouse struct {
@@ -41,8 +58,35 @@ type NPWarehouse struct {
 	SettlementTypeDescription    string `json:"settlementTypeDescription,omitempty"`
 	Number                       int    `json:"number"`
 	TotalMaxWeightAllowed        int    `json:"totalMaxWeightAllowed"`
+	Type                         string `json:"type"`
+	// Schedule is open hours by weekday
+	Schedule map[string]string `json:"schedule,omitempty"`
+	// SyncedAt is the unix time of the import the document was updated by
+	SyncedAt int64 `json:"syncedAt"`
+}
+
+type NPCity struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	NameRu   string `json:"nameRu"`
+	Area     string `json:"area"`
+	SyncedAt int64  `json:"syncedAt"`
 }
 
 type DeliveryAssignmentCity struct {
 	Warehouses sql.NullString `db:"warehouses"`
+	// TimeSlots is courier delivery slots json, e.g. [{"id": "morning", "from": "10:00", "to": "14:00", "capacity": 10}]
+	TimeSlots sql.NullString `db:"time_slots"`
+}
+
+type TimeSlot struct {
+	Id       string `json:"id"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Capacity int    `json:"capacity"`
+}
+
+type BookedSlot struct {
+	Slot   string `db:"slot"`
+	Booked int    `db:"booked"`
 }
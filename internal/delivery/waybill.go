@@ -0,0 +1,26 @@
+package delivery
+
+import (
+	"context"
+	"github.com/wowucco/G3/internal/entity"
+)
+
+// IWaybillService creates carrier waybills for orders sent to the carrier warehouse
+type IWaybillService interface {
+	Create(ctx context.Context, order entity.Order, parcel *entity.Parcel) (*entity.Waybill, error)
+}
+
+// ITrackingService returns carrier statuses of waybills by tracking numbers, unknown numbers are missed in result
+type ITrackingService interface {
+	Track(ctx context.Context, numbers []string) (map[string]*entity.WaybillStatus, error)
+}
+
+// IEstimateService calculates carrier delivery cost and date of the cart sent to the city
+type IEstimateService interface {
+	Estimate(ctx context.Context, city entity.City, cart entity.PaymentCart) (*entity.DeliveryEstimate, error)
+}
+
+// IStreetService searches streets of the city for courier delivery address
+type IStreetService interface {
+	Search(ctx context.Context, cityId, text string) ([]*entity.Street, error)
+}
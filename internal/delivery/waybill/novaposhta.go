@@ -0,0 +1,88 @@
+package waybill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/wowucco/G3/internal/entity"
+	"github.com/wowucco/G3/pkg/novaposhta"
+	"math"
+	"time"
+)
+
+// NovaposhtaSender is the shop counterparty, refs are taken from the Nova Poshta cabinet
+type NovaposhtaSender struct {
+	CityRef    string
+	Ref        string
+	AddressRef string
+	ContactRef string
+	Phone      string
+}
+
+func NewNovaposhtaWaybillService(c *novaposhta.Client, sender NovaposhtaSender) *NovaposhtaWaybillService {
+
+	return &NovaposhtaWaybillService{client: c, sender: sender}
+}
+
+type NovaposhtaWaybillService struct {
+	client *novaposhta.Client
+	sender NovaposhtaSender
+}
+
+// Create sends the order to the warehouse chosen by customer, delivery is paid by recipient.
+// Cash on delivery orders get backward delivery of the order cost.
+func (s *NovaposhtaWaybillService) Create(ctx context.Context, order entity.Order, parcel *entity.Parcel) (*entity.Waybill, error) {
+
+	if order.GetDelivery().GetMethod().GetSlug() != entity.DeliveryMethodNovaposhta {
+		return nil, errors.New(fmt.Sprintf("[novaposhta waybill][order %d][delivery method is %s]", order.GetId(), order.GetDelivery().GetMethod().GetSlug()))
+	}
+
+	warehouse := order.GetDelivery().GetWarehouse()
+
+	if warehouse.GetAddress().IsCustom() == true || warehouse.GetAddress().GetId() == "" {
+		return nil, errors.New(fmt.Sprintf("[novaposhta waybill][order %d][address isn't novaposhta warehouse]", order.GetId()))
+	}
+
+	declared := parcel.GetDeclaredValue()
+
+	doc := novaposhta.InternetDocument{
+		PayerType:     novaposhta.PayerTypeRecipient,
+		PaymentMethod: novaposhta.PaymentMethodCash,
+		DateTime:      time.Now().Format(novaposhta.DateLayout),
+		CargoType:     novaposhta.CargoTypeParcel,
+		ServiceType:   novaposhta.ServiceTypeWarehouseWarehouse,
+		Weight:        parcel.GetWeight(),
+		SeatsAmount:   parcel.GetSeats(),
+		Description:   parcel.GetDescription(),
+		Cost:          declared.CentToFloatValue(),
+
+		CitySender:    s.sender.CityRef,
+		Sender:        s.sender.Ref,
+		SenderAddress: s.sender.AddressRef,
+		ContactSender: s.sender.ContactRef,
+		SendersPhone:  s.sender.Phone,
+
+		CityRecipient:    warehouse.GetCity().GetId(),
+		RecipientAddress: warehouse.GetAddress().GetId(),
+		RecipientName:    order.GetCustomer().GetName(),
+		RecipientType:    novaposhta.RecipientTypePrivatePerson,
+		RecipientsPhone:  order.GetCustomer().GetPhone(),
+	}
+
+	if order.GetPayment().GetMethod().GetSlug() == entity.PaymentMethodCashOnDelivery {
+		cost := order.GetPrice()
+		doc.BackwardDeliveryData = []novaposhta.BackwardDelivery{{
+			PayerType:        novaposhta.PayerTypeRecipient,
+			CargoType:        novaposhta.CargoTypeMoney,
+			RedeliveryString: cost.CentToCurrency(),
+		}}
+	}
+
+	created, err := s.client.CreateInternetDocument(ctx, doc)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[novaposhta waybill][order %d][%v]", order.GetId(), err))
+	}
+
+	return entity.NewWaybill(created.Ref, created.IntDocNumber, int(math.Round(created.CostOnSite*100)), created.EstimatedDeliveryDate), nil
+}
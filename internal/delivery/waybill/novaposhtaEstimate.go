@@ -0,0 +1,62 @@
+package waybill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/wowucco/G3/internal/entity"
+	"github.com/wowucco/G3/pkg/novaposhta"
+	"math"
+	"time"
+)
+
+func NewNovaposhtaEstimateService(c *novaposhta.Client, senderCityRef string, itemWeight float64) *NovaposhtaEstimateService {
+
+	return &NovaposhtaEstimateService{client: c, senderCityRef: senderCityRef, itemWeight: itemWeight}
+}
+
+// NovaposhtaEstimateService calculates warehouse to warehouse delivery paid by recipient,
+// products have no weight so parcel weight is itemWeight per product in cart
+type NovaposhtaEstimateService struct {
+	client        *novaposhta.Client
+	senderCityRef string
+	itemWeight    float64
+}
+
+func (s *NovaposhtaEstimateService) Estimate(ctx context.Context, city entity.City, cart entity.PaymentCart) (*entity.DeliveryEstimate, error) {
+
+	items := cart.Items
+
+	if items < 1 {
+		items = 1
+	}
+
+	price, err := s.client.GetDocumentPrice(ctx, novaposhta.DocumentPriceRequest{
+		CitySender:    s.senderCityRef,
+		CityRecipient: city.ID,
+		ServiceType:   novaposhta.ServiceTypeWarehouseWarehouse,
+		CargoType:     novaposhta.CargoTypeParcel,
+		Weight:        s.itemWeight * float64(items),
+		SeatsAmount:   1,
+		Cost:          float64(cart.Amount) / 100,
+	})
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[novaposhta estimate][city %s][price][%v]", city.ID, err))
+	}
+
+	date, err := s.client.GetDocumentDeliveryDate(ctx, novaposhta.DocumentDeliveryDateRequest{
+		DateTime:      time.Now().Format(novaposhta.DateLayout),
+		ServiceType:   novaposhta.ServiceTypeWarehouseWarehouse,
+		CitySender:    s.senderCityRef,
+		CityRecipient: city.ID,
+	})
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[novaposhta estimate][city %s][delivery date][%v]", city.ID, err))
+	}
+
+	cost := entity.NewPrice(int(math.Round(price.Cost*100)), 0, 0, nil)
+
+	return entity.NewDeliveryEstimate(cost, date), nil
+}
@@ -0,0 +1,38 @@
+package waybill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/wowucco/G3/internal/entity"
+	"github.com/wowucco/G3/pkg/novaposhta"
+)
+
+// streetsLimit is enough for autocomplete suggestions
+const streetsLimit = 20
+
+func NewNovaposhtaStreetService(c *novaposhta.Client) *NovaposhtaStreetService {
+
+	return &NovaposhtaStreetService{client: c}
+}
+
+type NovaposhtaStreetService struct {
+	client *novaposhta.Client
+}
+
+func (s *NovaposhtaStreetService) Search(ctx context.Context, cityId, text string) ([]*entity.Street, error) {
+
+	found, err := s.client.GetStreets(ctx, cityId, text, streetsLimit)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("[novaposhta streets][city %s][%v]", cityId, err))
+	}
+
+	streets := make([]*entity.Street, len(found))
+
+	for k, v := range found {
+		streets[k] = &entity.Street{ID: v.Ref, Name: v.Description, Type: v.StreetsType}
+	}
+
+	return streets, nil
+}
@@ -0,0 +1,71 @@
+package waybill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/wowucco/G3/internal/entity"
+	"github.com/wowucco/G3/pkg/novaposhta"
+)
+
+// novaposhtaStatuses maps carrier status codes onto delivery statuses,
+// codes of received parcels and unknown numbers have no match and are skipped
+var novaposhtaStatuses = map[string]int{
+	"1":   entity.DeliveryStatusWaitingDelivery, // waybill is created, parcel isn't handed to carrier yet
+	"2":   entity.DeliveryStatusCanceled,        // waybill is deleted
+	"4":   entity.DeliveryStatusDelivery,        // in sender city
+	"5":   entity.DeliveryStatusDelivery,        // on the way to recipient city
+	"6":   entity.DeliveryStatusDelivery,        // in recipient city
+	"12":  entity.DeliveryStatusDelivery,        // being packed
+	"41":  entity.DeliveryStatusDelivery,        // in recipient city, delivery date is changed
+	"101": entity.DeliveryStatusDelivery,        // on the way to recipient
+	"104": entity.DeliveryStatusDelivery,        // address is changed
+	"111": entity.DeliveryStatusDelivery,        // courier delivery failed
+	"112": entity.DeliveryStatusDelivery,        // delivery date is changed by recipient
+	"7":   entity.DeliveryStatusReadyToReceive,  // arrived to warehouse
+	"8":   entity.DeliveryStatusReadyToReceive,  // arrived to postomat
+	"102": entity.DeliveryStatusCanceled,        // refused by recipient
+	"103": entity.DeliveryStatusCanceled,        // refused by recipient
+	"105": entity.DeliveryStatusCanceled,        // storage time is over
+	"108": entity.DeliveryStatusCanceled,        // refused by recipient
+}
+
+func NewNovaposhtaTrackingService(c *novaposhta.Client) *NovaposhtaTrackingService {
+
+	return &NovaposhtaTrackingService{client: c}
+}
+
+type NovaposhtaTrackingService struct {
+	client *novaposhta.Client
+}
+
+func (s *NovaposhtaTrackingService) Track(ctx context.Context, numbers []string) (map[string]*entity.WaybillStatus, error) {
+
+	result := make(map[string]*entity.WaybillStatus, len(numbers))
+
+	for start := 0; start < len(numbers); start += novaposhta.TrackingLimit {
+		end := start + novaposhta.TrackingLimit
+
+		if end > len(numbers) {
+			end = len(numbers)
+		}
+
+		documents := make([]novaposhta.TrackingDocument, 0, end-start)
+
+		for _, v := range numbers[start:end] {
+			documents = append(documents, novaposhta.TrackingDocument{DocumentNumber: v})
+		}
+
+		statuses, err := s.client.GetStatusDocuments(ctx, documents)
+
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("[novaposhta tracking][%v]", err))
+		}
+
+		for _, v := range statuses {
+			result[v.Number] = entity.NewWaybillStatus(v.Number, novaposhtaStatuses[v.StatusCode], v.Status)
+		}
+	}
+
+	return result, nil
+}
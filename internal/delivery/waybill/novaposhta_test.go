@@ -0,0 +1,155 @@
+package waybill
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wowucco/G3/internal/entity"
+	"github.com/wowucco/G3/pkg/novaposhta"
+)
+
+func newNovaposhtaStandIn(t *testing.T) (*httptest.Server, map[string]map[string]interface{}) {
+
+	requests := make(map[string]map[string]interface{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(b, &body))
+		requests[r.URL.Path] = body
+
+		if body["apiKey"] != "key" {
+			_, _ = w.Write([]byte(`{"success":false,"data":[],"errors":["API key expired"]}`))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/TrackingDocument/getStatusDocuments":
+			_, _ = w.Write([]byte(`{"success":true,"data":[{"Number":"20450000000001","StatusCode":"7","Status":"Arrived"},{"Number":"20450000000002","StatusCode":"9","Status":"Received"}],"errors":[]}`))
+		case "/InternetDocument/save":
+			_, _ = w.Write([]byte(`{"success":true,"data":[{"Ref":"doc-ref","IntDocNumber":"20450000000001","CostOnSite":65.5,"EstimatedDeliveryDate":"20.10.2026"}],"errors":[]}`))
+		case "/InternetDocument/getDocumentPrice":
+			_, _ = w.Write([]byte(`{"success":true,"data":[{"Cost":70,"AssessedCost":2500}],"errors":[]}`))
+		case "/InternetDocument/getDocumentDeliveryDate":
+			_, _ = w.Write([]byte(`{"success":true,"data":[{"DeliveryDate":{"date":"2026-10-21 00:00:00.000000","timezone_type":3,"timezone":"Europe/Kiev"}}],"errors":[]}`))
+		case "/Address/getStreet":
+			_, _ = w.Write([]byte(`{"success":true,"data":[{"Ref":"street-ref","Description":"Khreshchatyk","StreetsType":"vul."}],"errors":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return srv, requests
+}
+
+func newOrder(deliverySlug, paymentSlug string, customAddress bool) *entity.Order {
+
+	return entity.NewOrder(
+		7, 0, "", false, 125000, nil,
+		entity.NewOrderCustomer("Ivan Petrenko", "+380671234567"),
+		entity.NewOrderDelivery(
+			entity.DeliveryStatusNew,
+			entity.NewDeliveryMethod(2, "Nova Poshta", deliverySlug),
+			entity.NewOrderDeliveryWarehouse("city-ref", "Kyiv", "warehouse-ref", "Warehouse #1", customAddress),
+			nil,
+		),
+		entity.NewOrderPayment(entity.PaymentStatusNew, entity.NewPaymentMethod(3, "COD", paymentSlug), nil, "", "", "", 0),
+		nil,
+	)
+}
+
+func TestNovaposhtaWaybillService_Create(t *testing.T) {
+	srv, requests := newNovaposhtaStandIn(t)
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	s := NewNovaposhtaWaybillService(
+		novaposhta.NewClient(novaposhta.Config{ApiKey: "key", BaseUrl: u}),
+		NovaposhtaSender{CityRef: "sender-city", Ref: "sender", AddressRef: "sender-address", ContactRef: "contact", Phone: "380500000000"},
+	)
+
+	w, err := s.Create(context.Background(), *newOrder(entity.DeliveryMethodNovaposhta, entity.PaymentMethodCashOnDelivery, false), entity.NewParcel(1.5, 1, 125000, "Tent"))
+	assert.NoError(t, err, "t1")
+	assert.Equal(t, "20450000000001", w.GetNumber(), "t2")
+	assert.Equal(t, "doc-ref", w.GetRef(), "t3")
+	cost := w.GetCost()
+	assert.Equal(t, 6550, cost.GetInCent(), "t4")
+
+	props := requests["/InternetDocument/save"]["methodProperties"].(map[string]interface{})
+	assert.Equal(t, "warehouse-ref", props["RecipientAddress"], "t5")
+	assert.Equal(t, "city-ref", props["CityRecipient"], "t6")
+	assert.Equal(t, 1250.0, props["Cost"], "t7")
+	assert.Equal(t, "1250.00", props["BackwardDeliveryData"].([]interface{})[0].(map[string]interface{})["RedeliveryString"], "t8")
+
+	_, err = s.Create(context.Background(), *newOrder(entity.DeliveryMethodCourier, entity.PaymentMethodCash, false), entity.NewParcel(1, 1, 100, "Tent"))
+	assert.Error(t, err, "t9")
+
+	_, err = s.Create(context.Background(), *newOrder(entity.DeliveryMethodNovaposhta, entity.PaymentMethodCash, true), entity.NewParcel(1, 1, 100, "Tent"))
+	assert.Error(t, err, "t10")
+
+	bad := NewNovaposhtaWaybillService(novaposhta.NewClient(novaposhta.Config{ApiKey: "expired", BaseUrl: u}), NovaposhtaSender{})
+	_, err = bad.Create(context.Background(), *newOrder(entity.DeliveryMethodNovaposhta, entity.PaymentMethodCash, false), entity.NewParcel(1, 1, 100, "Tent"))
+	assert.Error(t, err, "t11")
+}
+
+func TestNovaposhtaTrackingService_Track(t *testing.T) {
+	srv, requests := newNovaposhtaStandIn(t)
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	s := NewNovaposhtaTrackingService(novaposhta.NewClient(novaposhta.Config{ApiKey: "key", BaseUrl: u}))
+
+	statuses, err := s.Track(context.Background(), []string{"20450000000001", "20450000000002"})
+	assert.NoError(t, err, "t1")
+	assert.Equal(t, entity.DeliveryStatusReadyToReceive, statuses["20450000000001"].GetStatus(), "t2")
+	assert.Equal(t, "Arrived", statuses["20450000000001"].GetDescription(), "t3")
+	assert.Equal(t, 0, statuses["20450000000002"].GetStatus(), "t4")
+
+	docs := requests["/TrackingDocument/getStatusDocuments"]["methodProperties"].(map[string]interface{})["Documents"].([]interface{})
+	assert.Equal(t, 2, len(docs), "t5")
+}
+
+func TestNovaposhtaEstimateService_Estimate(t *testing.T) {
+	srv, requests := newNovaposhtaStandIn(t)
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	s := NewNovaposhtaEstimateService(novaposhta.NewClient(novaposhta.Config{ApiKey: "key", BaseUrl: u}), "sender-city", 0.5)
+
+	cart := entity.NewPaymentCart(250000, []int{1}, 0)
+	cart.Items = 3
+
+	e, err := s.Estimate(context.Background(), entity.City{ID: "city-ref", Name: "Kyiv"}, cart)
+	assert.NoError(t, err, "t1")
+	cost := e.GetCost()
+	assert.Equal(t, 7000, cost.GetInCent(), "t2")
+	assert.Equal(t, "2026-10-21", e.GetDate().Format("2006-01-02"), "t3")
+
+	props := requests["/InternetDocument/getDocumentPrice"]["methodProperties"].(map[string]interface{})
+	assert.Equal(t, 1.5, props["Weight"], "t4")
+	assert.Equal(t, float64(2500), props["Cost"], "t5")
+	assert.Equal(t, "sender-city", props["CitySender"], "t6")
+}
+
+func TestNovaposhtaStreetService_Search(t *testing.T) {
+	srv, requests := newNovaposhtaStandIn(t)
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	s := NewNovaposhtaStreetService(novaposhta.NewClient(novaposhta.Config{ApiKey: "key", BaseUrl: u}))
+
+	streets, err := s.Search(context.Background(), "city-ref", "khre")
+	assert.NoError(t, err, "t1")
+	assert.Equal(t, []*entity.Street{{ID: "street-ref", Name: "Khreshchatyk", Type: "vul."}}, streets, "t2")
+
+	props := requests["/Address/getStreet"]["methodProperties"].(map[string]interface{})
+	assert.Equal(t, "city-ref", props["CityRef"], "t3")
+	assert.Equal(t, "khre", props["FindByString"], "t4")
+}
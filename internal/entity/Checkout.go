@@ -1,18 +1,22 @@
 package entity
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 /**
  *************	Order	**************
  */
-func NewOrder(id int, created int64, comment string, doNotCall bool, cost int, customer *OrderCustomer, delivery *OrderDelivery, payment *OrderPayment, items []*OrderProduct) *Order {
+// NewOrder builds the order, cost is in cents of the currency, nil currency means the base one
+func NewOrder(id int, created int64, comment string, doNotCall bool, cost int, currency *Currency, customer *OrderCustomer, delivery *OrderDelivery, payment *OrderPayment, items []*OrderProduct) *Order {
 
 	return &Order{
 		id:        id,
 		created:   created,
 		comment:   comment,
 		doNotCall: doNotCall,
-		totalCost: *NewPrice(cost, 0, 0, nil),
+		totalCost: *NewPrice(cost, 0, 0, currency),
 		customer:  customer,
 		delivery:  delivery,
 		payment:   payment,
@@ -31,6 +35,7 @@ type Order struct {
 	delivery *OrderDelivery
 	payment  *OrderPayment
 	items    []*OrderProduct
+	discount *OrderDiscount
 }
 
 func (o Order) GetId() int {
@@ -48,6 +53,19 @@ func (o Order) GetDoNotCall() bool {
 func (o Order) GetPrice() Price {
 	return o.totalCost
 }
+
+// GetCurrency returns the currency order is paid in, it's saved with its rate at creation
+func (o Order) GetCurrency() Currency {
+	return o.totalCost.GetCurrency()
+}
+
+// GetDiscount returns applied promo code or nil, total cost already includes the discount
+func (o Order) GetDiscount() *OrderDiscount {
+	return o.discount
+}
+func (o *Order) SetDiscount(d *OrderDiscount) {
+	o.discount = d
+}
 func (o Order) GetCustomer() OrderCustomer {
 	return *o.customer
 }
@@ -71,10 +89,140 @@ func (o Order) CanMakePayment() bool {
 	return o.payment.status == PaymentStatusNew || o.payment.status == PaymentStatusFailed
 }
 
-func (o *Order) UpdatePaymentStatus(status int, comment string) {
+// IsClosed reports whether the order won't be sold as it is: delivery is canceled or payment is canceled,
+// failed or refunded. Failed order is opened again by the next payment.
+func (o Order) IsClosed() bool {
+	if o.delivery != nil && o.delivery.status == DeliveryStatusCanceled {
+		return true
+	}
+
+	switch o.payment.status {
+	case PaymentStatusCanceled, PaymentStatusFailed, PaymentStatusRefund:
+		return true
+	}
+
+	return false
+}
+
+// UpdatePaymentStatus moves the order payment to status and records it in history.
+// Setting the current status again is a no-op.
+func (o *Order) UpdatePaymentStatus(status int, comment string) error {
+	if o.payment.status == status {
+		return nil
+	}
+
+	if CanChangePaymentStatus(o.payment.status, status) == false {
+		return &PaymentStatusTransitionError{From: o.payment.status, To: status}
+	}
+
 	o.payment.status = status
 	h := NewOrderPaymentStatusHistory(status, time.Now().Unix(), comment)
 	o.payment.statusHistory = append(o.payment.statusHistory, h)
+
+	return nil
+}
+
+// UpdateDeliveryStatus moves the order delivery to status and records it in history.
+// Setting the current status again is a no-op.
+func (o *Order) UpdateDeliveryStatus(status int, comment string) error {
+	if o.delivery.status == status {
+		return nil
+	}
+
+	if CanChangeDeliveryStatus(o.delivery.status, status) == false {
+		return &DeliveryStatusTransitionError{From: o.delivery.status, To: status}
+	}
+
+	o.delivery.status = status
+	h := NewOrderDeliveryStatusHistory(status, time.Now().Unix(), comment)
+	o.delivery.statusHistory = append(o.delivery.statusHistory, h)
+
+	return nil
+}
+
+// TrackDeliveryStatus records carrier progress of the parcel. A new description of the current status is added
+// to history as well, missed in-transit step is passed through when the parcel already arrived.
+// Returns false when nothing changed.
+func (o *Order) TrackDeliveryStatus(status int, comment string) (bool, error) {
+	if o.delivery.status == status {
+		h := o.delivery.statusHistory
+
+		if len(h) > 0 && h[len(h)-1].comment == comment {
+			return false, nil
+		}
+
+		o.delivery.statusHistory = append(h, NewOrderDeliveryStatusHistory(status, time.Now().Unix(), comment))
+
+		return true, nil
+	}
+
+	if CanChangeDeliveryStatus(o.delivery.status, status) == false && CanChangeDeliveryStatus(o.delivery.status, DeliveryStatusDelivery) {
+		if err := o.UpdateDeliveryStatus(DeliveryStatusDelivery, comment); err != nil {
+			return false, err
+		}
+	}
+
+	if err := o.UpdateDeliveryStatus(status, comment); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// IsEditable reports whether operators can change the order content, paid or being paid orders are frozen
+func (o Order) IsEditable() bool {
+	return o.payment.status != PaymentStatusDone && o.payment.status != PaymentStatusWaitingConfirmation
+}
+func (o *Order) ChangeCustomer(customer *OrderCustomer) {
+	o.customer = customer
+}
+
+// ChangeWarehouse moves the delivery to another address, courier address and slot belong to the previous one
+// and are dropped
+func (o *Order) ChangeWarehouse(warehouse *OrderDeliveryWarehouse) {
+	o.delivery.warehouse = warehouse
+	o.delivery.courier = nil
+	o.delivery.slot = nil
+}
+func (o *Order) SetTrackingNumber(number string) {
+	o.delivery.trackingNumber = number
+}
+
+// SetWaybillRequest marks the waybill is being created since the timestamp, zero clears the mark
+func (o *Order) SetWaybillRequest(requestedAt int64) {
+	o.delivery.waybillRequestedAt = requestedAt
+}
+
+// SetWaybill stores the created carrier waybill and clears the request mark
+func (o *Order) SetWaybill(ref, number string) {
+	o.delivery.waybillRef = ref
+	o.delivery.trackingNumber = number
+	o.delivery.waybillRequestedAt = 0
+}
+func (o *Order) SetCourier(courier *OrderDeliveryCourier, slot *OrderDeliverySlot) {
+	o.delivery.courier = courier
+	o.delivery.slot = slot
+}
+
+// ChangeItems replaces the items and recalculates total cost and promo discount from them
+func (o *Order) ChangeItems(items []*OrderProduct) {
+	var cost, discount int
+
+	for _, v := range items {
+		total := v.GetDiscountedTotal()
+		cost += total.GetInCent()
+		discount += v.discount
+	}
+
+	// item prices are in the base currency
+	o.items = items
+	o.totalCost = *NewPrice(o.totalCost.Currency.FromBase(cost), 0, 0, &o.totalCost.Currency)
+
+	if o.discount != nil {
+		released := o.discount.IsReleased()
+		o.discount = NewOrderDiscount(o.discount.GetCode(), discount)
+		o.discount.SetReleased(released)
+	}
 }
 func (o *Order) HasEqualStatus(status int) bool {
 	return o.payment.status == status
@@ -125,6 +273,14 @@ type OrderDelivery struct {
 	method        *DeliveryMethod
 	warehouse     *OrderDeliveryWarehouse
 	statusHistory []*OrderDeliveryStatusHistory
+	// trackingNumber is the carrier waybill number, empty till the parcel is sent
+	trackingNumber string
+	// waybillRef is the carrier id of the waybill, waybillRequestedAt is set while the waybill is being created
+	waybillRef         string
+	waybillRequestedAt int64
+	// courier and slot are set for courier delivery only
+	courier *OrderDeliveryCourier
+	slot    *OrderDeliverySlot
 }
 
 func (o OrderDelivery) GetStatus() int {
@@ -136,6 +292,21 @@ func (o OrderDelivery) GetMethod() DeliveryMethod {
 func (o OrderDelivery) GetWarehouse() OrderDeliveryWarehouse {
 	return *o.warehouse
 }
+func (o OrderDelivery) GetTrackingNumber() string {
+	return o.trackingNumber
+}
+func (o OrderDelivery) GetWaybillRef() string {
+	return o.waybillRef
+}
+func (o OrderDelivery) GetWaybillRequestedAt() int64 {
+	return o.waybillRequestedAt
+}
+func (o OrderDelivery) GetCourier() *OrderDeliveryCourier {
+	return o.courier
+}
+func (o OrderDelivery) GetSlot() *OrderDeliverySlot {
+	return o.slot
+}
 func (o OrderDelivery) GetStatusHistory() []OrderDeliveryStatusHistory {
 	s := make([]OrderDeliveryStatusHistory, len(o.statusHistory))
 
@@ -220,6 +391,70 @@ func (w OrderDeliveryWarehouse) GetAddress() OrderDeliveryWarehouseAddress {
 	return w.address
 }
 
+func NewOrderDeliveryCourier(streetId, street, house, apartment string) *OrderDeliveryCourier {
+
+	return &OrderDeliveryCourier{streetId: streetId, street: street, house: house, apartment: apartment}
+}
+
+// OrderDeliveryCourier is the customer address, streetId is the carrier street ref, empty for street typed by hand
+type OrderDeliveryCourier struct {
+	streetId  string
+	street    string
+	house     string
+	apartment string
+}
+
+func (c OrderDeliveryCourier) GetStreetId() string {
+	return c.streetId
+}
+func (c OrderDeliveryCourier) GetStreet() string {
+	return c.street
+}
+func (c OrderDeliveryCourier) GetHouse() string {
+	return c.house
+}
+func (c OrderDeliveryCourier) GetApartment() string {
+	return c.apartment
+}
+
+// GetFullAddress is the address line for operators and warehouse name of courier orders
+func (c OrderDeliveryCourier) GetFullAddress() string {
+
+	address := fmt.Sprintf("%s, %s", c.street, c.house)
+
+	if c.apartment != "" {
+		address += fmt.Sprintf(", kv. %s", c.apartment)
+	}
+
+	return address
+}
+
+func NewOrderDeliverySlot(date, id, from, to string) *OrderDeliverySlot {
+
+	return &OrderDeliverySlot{date: date, id: id, from: from, to: to}
+}
+
+// OrderDeliverySlot is the time slot chosen for courier delivery, date is in DeliverySlotDateLayout
+type OrderDeliverySlot struct {
+	date string
+	id   string
+	from string
+	to   string
+}
+
+func (s OrderDeliverySlot) GetDate() string {
+	return s.date
+}
+func (s OrderDeliverySlot) GetId() string {
+	return s.id
+}
+func (s OrderDeliverySlot) GetFrom() string {
+	return s.from
+}
+func (s OrderDeliverySlot) GetTo() string {
+	return s.to
+}
+
 /**
 ******************	Payment ************
  */
@@ -269,6 +504,16 @@ func (o OrderPayment) HasToCardPayment() bool {
 	return o.method.Slug == PaymentMethodToCard
 }
 
+// HasManualPayment reports whether receiving the money is confirmed by operator instead of provider
+func (o OrderPayment) HasManualPayment() bool {
+	switch o.method.Slug {
+	case PaymentMethodToCard, PaymentMethodCash, PaymentMethodCashOnDelivery, PaymentMethodPayin:
+		return true
+	default:
+		return false
+	}
+}
+
 func NewOrderPaymentStatusHistory(status int, created int64, comment string) *OrderPaymentStatusHistory {
 	return &OrderPaymentStatusHistory{status, created, comment}
 }
@@ -324,6 +569,14 @@ type OrderProduct struct {
 	quantity int
 	price    Price
 	product  SimpleProduct
+	// discount of the whole line in cents
+	discount int
+}
+
+// OrderProductPart is a quantity of the item sold by the same unit price
+type OrderProductPart struct {
+	Quantity int
+	Price    int
 }
 
 func (o OrderProduct) GetQuantity() int {
@@ -335,3 +588,44 @@ func (o OrderProduct) GetPrice() Price {
 func (o OrderProduct) GetProduct() SimpleProduct {
 	return o.product
 }
+
+// GetCurrency returns the product currency with the rate the item price was converted by
+func (o OrderProduct) GetCurrency() Currency {
+	return o.product.Price.GetCurrency()
+}
+func (o OrderProduct) GetDiscount() Price {
+	return *NewPrice(o.discount, 0, 0, &o.price.Currency)
+}
+func (o *OrderProduct) SetDiscount(amount int) {
+	o.discount = amount
+}
+
+// SetQuantity changes the quantity keeping the discount per unit
+func (o *OrderProduct) SetQuantity(quantity int) {
+	if o.quantity > 0 {
+		o.discount = o.discount * quantity / o.quantity
+	}
+
+	o.quantity = quantity
+}
+func (o OrderProduct) GetDiscountedTotal() Price {
+	return *NewPrice(o.price.GetInCent()*o.quantity-o.discount, 0, 0, &o.price.Currency)
+}
+
+// GetDiscountedParts splits the line by unit prices which sum up to the discounted total,
+// the last unit takes the remainder when the discount isn't divisible by quantity
+func (o OrderProduct) GetDiscountedParts() []OrderProductPart {
+
+	total := o.price.GetInCent()*o.quantity - o.discount
+	unit := total / o.quantity
+	rest := total - unit*o.quantity
+
+	if rest == 0 {
+		return []OrderProductPart{{o.quantity, unit}}
+	}
+
+	return []OrderProductPart{{o.quantity - 1, unit}, {1, unit + rest}}
+}
+func (o OrderProduct) GetTotal() Price {
+	return *NewPrice(o.price.GetInCent()*o.quantity, 0, 0, &o.price.Currency)
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrder_ChangeItems(t *testing.T) {
+	first := NewOrderProduct(2, 1000, SimpleProduct{ID: 1})
+	first.SetDiscount(300)
+
+	order := NewOrder(1, 0, "", false, 1700, nil, nil, nil, nil, []*OrderProduct{first})
+	order.SetDiscount(NewOrderDiscount("PROMO", 300))
+
+	kept := *first
+	kept.SetQuantity(4)
+
+	order.ChangeItems([]*OrderProduct{&kept, NewOrderProduct(1, 500, SimpleProduct{ID: 2})})
+
+	price := order.GetPrice()
+	assert.Equal(t, 4000-600+500, price.GetInCent())
+	assert.Equal(t, 600, order.GetDiscount().GetAmount())
+	assert.Equal(t, "PROMO", order.GetDiscount().GetCode())
+	assert.Equal(t, 2, len(order.GetItems()))
+}
+
+func TestOrder_ChangeWarehouse(t *testing.T) {
+	order := NewOrder(1, 0, "", false, 0, nil, nil, NewOrderDelivery(DeliveryStatusNew, nil, nil, nil), nil, nil)
+	order.SetCourier(NewOrderDeliveryCourier("ref", "Khreshchatyk", "22", "5"), NewOrderDeliverySlot("2026-10-19", "morning", "10:00", "14:00"))
+
+	order.ChangeWarehouse(NewOrderDeliveryWarehouse("city", "Lviv", "warehouse", "Branch 1", false))
+
+	assert.Equal(t, "Branch 1", order.GetDelivery().GetWarehouse().GetAddress().GetName(), "t1")
+	assert.Nil(t, order.GetDelivery().GetCourier(), "t2")
+	assert.Nil(t, order.GetDelivery().GetSlot(), "t3")
+}
+
+func TestOrder_TrackDeliveryStatus(t *testing.T) {
+	order := NewOrder(1, 0, "", false, 0, nil, nil, NewOrderDelivery(DeliveryStatusNew, nil, nil, nil), nil, nil)
+
+	changed, err := order.TrackDeliveryStatus(DeliveryStatusDelivery, "on the way")
+	assert.NoError(t, err)
+	assert.True(t, changed, "t1")
+
+	changed, _ = order.TrackDeliveryStatus(DeliveryStatusDelivery, "on the way")
+	assert.False(t, changed, "t2")
+
+	changed, _ = order.TrackDeliveryStatus(DeliveryStatusDelivery, "in recipient city")
+	assert.True(t, changed, "t3")
+	assert.Equal(t, 2, len(order.GetDelivery().GetStatusHistory()), "t4")
+
+	skipped := NewOrder(2, 0, "", false, 0, nil, nil, NewOrderDelivery(DeliveryStatusWaitingDelivery, nil, nil, nil), nil, nil)
+
+	changed, err = skipped.TrackDeliveryStatus(DeliveryStatusReadyToReceive, "arrived")
+	assert.NoError(t, err)
+	assert.True(t, changed, "t5")
+	assert.Equal(t, DeliveryStatusReadyToReceive, skipped.GetDelivery().GetStatus(), "t6")
+	assert.Equal(t, 2, len(skipped.GetDelivery().GetStatusHistory()), "t7")
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrency_ToBase(t *testing.T) {
+	usd := NewCurrency(2, "$", 27.35, "USD")
+
+	assert.Equal(t, 273500, usd.ToBase(10000), "t1")
+	assert.Equal(t, 2735, usd.ToBase(100), "t2")
+	assert.Equal(t, 27, usd.ToBase(1), "t3")
+	assert.Equal(t, 10000, DefaultCurrency().ToBase(10000), "t4")
+
+	eur := NewCurrency(3, "€", 29.12345, "EUR")
+	assert.Equal(t, 291234, eur.ToBase(10000), "t5")
+
+	assert.Equal(t, DefaultCurrency(), NewCurrencySnapshot("", 0), "t6")
+	assert.Equal(t, float32(27.35), NewCurrencySnapshot("USD", 27.35).GetRate(), "t7")
+}
+
+func TestCurrency_FromBase(t *testing.T) {
+	usd := NewCurrency(2, "$", 27.35, "USD")
+
+	assert.Equal(t, 10000, usd.FromBase(273500), "t1")
+	assert.Equal(t, 3656, usd.FromBase(100000), "t2")
+	assert.Equal(t, 10000, usd.FromBase(usd.ToBase(10000)), "t3")
+	assert.Equal(t, 10000, DefaultCurrency().FromBase(10000), "t4")
+	assert.True(t, IsBaseISO(""), "t5")
+	assert.False(t, IsBaseISO("USD"), "t6")
+}
@@ -1,9 +1,20 @@
 package entity
 
+import (
+	"strings"
+	"time"
+)
+
 const DeliveryMethodYourself = "yourself"
 const DeliveryMethodNovaposhta = "novaposhta"
 const DeliveryMethodCourier = "courier"
 
+const WarehouseTypeBranch = "branch"
+const WarehouseTypePostomat = "postomat"
+
+// DeliverySlotDateLayout is the date format of courier time slots
+const DeliverySlotDateLayout = "2006-01-02"
+
 const DeliveryStatusNew = 1
 const DeliveryStatusCheck = 2
 const DeliveryStatusWaitingDelivery = 3
@@ -11,6 +22,26 @@ const DeliveryStatusDelivery = 4
 const DeliveryStatusReadyToReceive = 5
 const DeliveryStatusCanceled = 6
 
+const DeliveryStatusNewLabel = "New"
+const DeliveryStatusCheckLabel = "Check"
+const DeliveryStatusWaitingDeliveryLabel = "Waiting delivery"
+const DeliveryStatusDeliveryLabel = "Delivery"
+const DeliveryStatusReadyToReceiveLabel = "Ready to receive"
+const DeliveryStatusCanceledLabel = "Canceled"
+
+func DeliveryStatusLabel(status int) string {
+	m := map[int]string{
+		DeliveryStatusNew:             DeliveryStatusNewLabel,
+		DeliveryStatusCheck:           DeliveryStatusCheckLabel,
+		DeliveryStatusWaitingDelivery: DeliveryStatusWaitingDeliveryLabel,
+		DeliveryStatusDelivery:        DeliveryStatusDeliveryLabel,
+		DeliveryStatusReadyToReceive:  DeliveryStatusReadyToReceiveLabel,
+		DeliveryStatusCanceled:        DeliveryStatusCanceledLabel,
+	}
+
+	return m[status]
+}
+
 type City struct {
 	ID   string
 	Name string
@@ -20,6 +51,25 @@ type DeliveryInfo struct {
 	DeliveryMethod DeliveryMethod
 	PaymentMethods []PaymentMethod
 	Warehouses     []Warehouse
+	// Estimate is nil when delivery cost and date are unknown
+	Estimate *DeliveryEstimate
+}
+
+func NewDeliveryEstimate(cost *Price, date time.Time) *DeliveryEstimate {
+	return &DeliveryEstimate{cost: cost, date: date}
+}
+
+// DeliveryEstimate is the delivery cost paid by customer and the date order is expected to be delivered
+type DeliveryEstimate struct {
+	cost *Price
+	date time.Time
+}
+
+func (e *DeliveryEstimate) GetCost() *Price {
+	return e.cost
+}
+func (e *DeliveryEstimate) GetDate() time.Time {
+	return e.date
 }
 
 func NewDeliveryMethod(id int, name, slug string, ) *DeliveryMethod {
@@ -48,5 +98,100 @@ type Warehouse struct {
 	Address   string
 	Phone     string
 	Number    int
+	// MaxWeight in kilograms, 0 means unlimited
 	MaxWeight int
+	Type      string
+	// Schedule is open hours by weekday, e.g. {"Monday": "08:00-20:00", "Sunday": "-"}, empty when unknown
+	Schedule map[string]string
+}
+
+// IsOpenAt is true when open hours of the weekday cover the time, unknown schedule is always open
+func (w Warehouse) IsOpenAt(t time.Time) bool {
+
+	if len(w.Schedule) == 0 {
+		return true
+	}
+
+	hours := strings.Split(w.Schedule[t.Weekday().String()], "-")
+
+	if len(hours) != 2 {
+		return false
+	}
+
+	open, err := time.Parse("15:04", strings.TrimSpace(hours[0]))
+
+	if err != nil {
+		return false
+	}
+
+	closed, err := time.Parse("15:04", strings.TrimSpace(hours[1]))
+
+	if err != nil {
+		return false
+	}
+
+	minutes := t.Hour()*60 + t.Minute()
+
+	return minutes >= open.Hour()*60+open.Minute() && minutes < closed.Hour()*60+closed.Minute()
+}
+
+// WarehouseFilter limits carrier warehouses, zero values don't limit anything
+type WarehouseFilter struct {
+	Type string
+	// Weight of the parcel in kilograms warehouse must accept
+	Weight int
+	OpenAt time.Time
+}
+
+type Street struct {
+	ID   string
+	Name string
+	// Type is the street type abbreviation, e.g. vul.
+	Type string
+}
+
+func NewDeliveryTimeSlot(id, from, to string, capacity, booked int) *DeliveryTimeSlot {
+	return &DeliveryTimeSlot{id: id, from: from, to: to, capacity: capacity, booked: booked}
+}
+
+// DeliveryTimeSlot is the courier delivery interval of the city, capacity is orders count per date
+type DeliveryTimeSlot struct {
+	id       string
+	from     string
+	to       string
+	capacity int
+	booked   int
+}
+
+func (s *DeliveryTimeSlot) GetId() string {
+	return s.id
+}
+func (s *DeliveryTimeSlot) GetFrom() string {
+	return s.from
+}
+func (s *DeliveryTimeSlot) GetTo() string {
+	return s.to
+}
+func (s *DeliveryTimeSlot) GetCapacity() int {
+	return s.capacity
+}
+func (s *DeliveryTimeSlot) GetAvailable() int {
+	if s.booked >= s.capacity {
+		return 0
+	}
+	return s.capacity - s.booked
+}
+
+// IsOverAt is true when the slot of the date ended before the time, slot with unknown end is never over
+func (s *DeliveryTimeSlot) IsOverAt(date, t time.Time) bool {
+
+	end, err := time.Parse("15:04", strings.TrimSpace(s.to))
+
+	if err != nil {
+		return false
+	}
+
+	y, m, d := date.Date()
+
+	return t.After(time.Date(y, m, d, end.Hour(), end.Minute(), 0, 0, date.Location()))
 }
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarehouse_IsOpenAt(t *testing.T) {
+	w := Warehouse{Schedule: map[string]string{"Monday": "08:00-20:00", "Sunday": "-"}}
+
+	// 2026-10-19 is Monday
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		tag      string
+		at       time.Time
+		expected bool
+	}{
+		{"t1", monday.Add(8 * time.Hour), true},
+		{"t2", monday.Add(19*time.Hour + 59*time.Minute), true},
+		{"t3", monday.Add(20 * time.Hour), false},
+		{"t4", monday.Add(7*time.Hour + 59*time.Minute), false},
+		{"t5", monday.AddDate(0, 0, -1).Add(12 * time.Hour), false},
+		{"t6", monday.AddDate(0, 0, 1).Add(12 * time.Hour), false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, w.IsOpenAt(test.at), test.tag)
+	}
+
+	assert.Equal(t, true, Warehouse{}.IsOpenAt(monday), "t7")
+}
+
+func TestDeliveryTimeSlot_GetAvailable(t *testing.T) {
+	assert.Equal(t, 3, NewDeliveryTimeSlot("morning", "10:00", "14:00", 5, 2).GetAvailable(), "t1")
+	assert.Equal(t, 0, NewDeliveryTimeSlot("morning", "10:00", "14:00", 5, 5).GetAvailable(), "t2")
+	assert.Equal(t, 0, NewDeliveryTimeSlot("morning", "10:00", "14:00", 5, 7).GetAvailable(), "t3")
+}
+
+func TestDeliveryTimeSlot_IsOverAt(t *testing.T) {
+	s := NewDeliveryTimeSlot("morning", "10:00", "14:00", 5, 0)
+
+	// 2026-10-19 is Monday
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+
+	assert.Equal(t, false, s.IsOverAt(monday, monday.Add(9*time.Hour)), "t1")
+	assert.Equal(t, false, s.IsOverAt(monday, monday.Add(14*time.Hour)), "t2")
+	assert.Equal(t, true, s.IsOverAt(monday, monday.Add(14*time.Hour+time.Minute)), "t3")
+	assert.Equal(t, false, s.IsOverAt(monday.AddDate(0, 0, 1), monday.Add(20*time.Hour)), "t4")
+	assert.Equal(t, false, NewDeliveryTimeSlot("morning", "", "", 5, 0).IsOverAt(monday, monday.Add(20*time.Hour)), "t5")
+}
+
+func TestOrderDeliveryCourier_GetFullAddress(t *testing.T) {
+	assert.Equal(t, "Khreshchatyk, 22, kv. 5", NewOrderDeliveryCourier("ref", "Khreshchatyk", "22", "5").GetFullAddress(), "t1")
+	assert.Equal(t, "Khreshchatyk, 22", NewOrderDeliveryCourier("", "Khreshchatyk", "22", "").GetFullAddress(), "t2")
+}
@@ -0,0 +1,47 @@
+package entity
+
+// InstallmentTypeParts is paying by parts without overpayment, bank commission is paid by the store
+const InstallmentTypeParts = "PP"
+
+// InstallmentTypeInstant is instant installment, bank commission is paid by the customer
+const InstallmentTypeInstant = "II"
+
+func NewInstallment(installmentType string, parts, payment, commission, total int) *Installment {
+
+	return &Installment{
+		installmentType: installmentType,
+		parts:           parts,
+		payment:         payment,
+		commission:      commission,
+		total:           total,
+	}
+}
+
+// Installment is a way to split the purchase amount by monthly payments, sums are in cents
+type Installment struct {
+	installmentType string
+	parts           int
+	payment         int
+	commission      int
+	total           int
+}
+
+func (i *Installment) GetType() string {
+	return i.installmentType
+}
+func (i *Installment) GetParts() int {
+	return i.parts
+}
+
+// GetPayment returns monthly payment of the customer
+func (i *Installment) GetPayment() *Price {
+	return NewPrice(i.payment, 0, 0, nil)
+}
+func (i *Installment) GetCommission() *Price {
+	return NewPrice(i.commission, 0, 0, nil)
+}
+
+// GetTotal returns the sum paid by the customer
+func (i *Installment) GetTotal() *Price {
+	return NewPrice(i.total, 0, 0, nil)
+}
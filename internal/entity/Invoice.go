@@ -0,0 +1,58 @@
+package entity
+
+import "time"
+
+func CreateNewInvoiceByPayment(number int, payment *Payment, document string) *Invoice {
+
+	return &Invoice{
+		number:        number,
+		transactionId: payment.GetTransactionId(),
+		paymentId:     payment.GetId(),
+		orderId:       payment.GetOrderId(),
+		document:      document,
+		created:       time.Now().Unix(),
+	}
+}
+
+func NewInvoice(number int, transactionId string, paymentId, orderId int, document string, created int64) *Invoice {
+
+	return &Invoice{
+		number:        number,
+		transactionId: transactionId,
+		paymentId:     paymentId,
+		orderId:       orderId,
+		document:      document,
+		created:       created,
+	}
+}
+
+// Invoice is a rendered document for the bank transfer of a pay-in payment
+type Invoice struct {
+	number        int
+	transactionId string
+	paymentId     int
+	orderId       int
+	document      string
+	created       int64
+}
+
+func (i *Invoice) GetNumber() int {
+	return i.number
+}
+func (i *Invoice) GetTransactionId() string {
+	return i.transactionId
+}
+func (i *Invoice) GetPaymentId() int {
+	return i.paymentId
+}
+func (i *Invoice) GetOrderId() int {
+	return i.orderId
+}
+
+// GetDocument returns invoice html
+func (i *Invoice) GetDocument() string {
+	return i.document
+}
+func (i *Invoice) GetCreatedTime() time.Time {
+	return time.Unix(i.created, 0)
+}
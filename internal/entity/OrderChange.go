@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+const OrderChangeCustomer = "customer"
+const OrderChangeWarehouse = "warehouse"
+const OrderChangeItems = "items"
+
+func CreateNewOrderChange(orderId int, field, before, after, operator, comment string) *OrderChange {
+
+	return NewOrderChange(0, orderId, field, before, after, operator, comment, time.Now().Unix())
+}
+
+func NewOrderChange(id, orderId int, field, before, after, operator, comment string, created int64) *OrderChange {
+
+	return &OrderChange{
+		id:       id,
+		orderId:  orderId,
+		field:    field,
+		before:   before,
+		after:    after,
+		operator: operator,
+		comment:  comment,
+		created:  created,
+	}
+}
+
+// OrderChange is an audit record of the order edited by operator, values are human readable
+type OrderChange struct {
+	id       int
+	orderId  int
+	field    string
+	before   string
+	after    string
+	operator string
+	comment  string
+	created  int64
+}
+
+func (c *OrderChange) GetId() int {
+	return c.id
+}
+func (c *OrderChange) GetOrderId() int {
+	return c.orderId
+}
+func (c *OrderChange) GetField() string {
+	return c.field
+}
+func (c *OrderChange) GetBefore() string {
+	return c.before
+}
+func (c *OrderChange) GetAfter() string {
+	return c.after
+}
+func (c *OrderChange) GetOperator() string {
+	return c.operator
+}
+func (c *OrderChange) GetComment() string {
+	return c.comment
+}
+func (c *OrderChange) GetCreated() int64 {
+	return c.created
+}
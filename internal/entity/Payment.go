@@ -12,6 +12,7 @@ const PaymentMethodPayin = "pay-in"
 const PaymentMethodCashOnDelivery = "cod"
 const PaymentMethodToCard = "to_card"
 const PaymentMethodPartsPay = "parts_pay"
+const PaymentMethodMonobank = "monobank"
 
 const PaymentStatusNew = 1
 const PaymentStatusWaitingConfirmation = 2
@@ -73,8 +74,10 @@ type Payment struct {
 	provider      string
 	price         *Price
 	status        int
-	created       int64
-	updated       int64
+	// externalId is the payment id on provider side, when provider doesn't use our transaction id
+	externalId string
+	created    int64
+	updated    int64
 }
 
 func (p *Payment) GetId() int {
@@ -134,25 +137,45 @@ func (p *Payment) GetProvider() string {
 func (p *Payment) SetProvider(provider string) {
 	p.provider = provider
 }
-func (p *Payment) UpdateStatus(status int) {
+func (p *Payment) GetExternalId() string {
+	return p.externalId
+}
+func (p *Payment) SetExternalId(externalId string) {
+	p.externalId = externalId
+}
+
+// UpdateStatus moves the payment to status, setting the current status again is a no-op
+func (p *Payment) UpdateStatus(status int) error {
+	if p.status == status {
+		return nil
+	}
+
+	if CanChangePaymentStatus(p.status, status) == false {
+		return &PaymentStatusTransitionError{From: p.status, To: status}
+	}
+
 	p.status = status
+
+	return nil
 }
 func (p *Payment) HasEqualStatus(status int) bool {
 	return p.status == status
 }
 
-//func (p *Payment) UpdateStatus(status int) error {
-//
-//}
+// IsOpen reports whether the payment may still be completed, failed, canceled and refunded payments are closed
+func (p *Payment) IsOpen() bool {
+	return p.status != PaymentStatusFailed && p.status != PaymentStatusCanceled && p.status != PaymentStatusRefund
+}
 
 func NewPaymentMethod(id int, name, slug string) *PaymentMethod {
-	return &PaymentMethod{id, name, slug}
+	return &PaymentMethod{ID: id, Name: name, Slug: slug}
 }
 
 type PaymentMethod struct {
-	ID   int
-	Name string
-	Slug string
+	ID    int
+	Name  string
+	Slug  string
+	Rules PaymentMethodRules
 }
 
 func (d PaymentMethod) GetID() int {
@@ -166,6 +189,11 @@ func (d PaymentMethod) GetName() string {
 func (d PaymentMethod) GetSlug() string {
 	return d.Slug
 }
+
+// IsEligible reports whether the cart can be paid by the method
+func (d PaymentMethod) IsEligible(cart PaymentCart) bool {
+	return d.Rules.Check(cart) == ""
+}
 func StatusLabel(status int) string {
 	m := map[int]string{
 		PaymentStatusNew: PaymentStatusNewLabel,
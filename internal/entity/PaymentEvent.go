@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+const PaymentEventOutcomeNew = "new"
+const PaymentEventOutcomeProcessed = "processed"
+const PaymentEventOutcomeSkipped = "skipped"
+const PaymentEventOutcomeFailed = "failed"
+
+// PaymentEventHash identifies the same callback delivered more than once
+func PaymentEventHash(provider string, body []byte) string {
+
+	h := sha256.New()
+	h.Write([]byte(provider))
+	h.Write([]byte{0})
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func CreateNewPaymentEvent(id int, provider, contentType string, headers map[string]string, body []byte) *PaymentEvent {
+
+	now := time.Now().Unix()
+
+	return &PaymentEvent{
+		id:          id,
+		provider:    provider,
+		contentType: contentType,
+		headers:     headers,
+		body:        string(body),
+		hash:        PaymentEventHash(provider, body),
+		outcome:     PaymentEventOutcomeNew,
+		created:     now,
+		updated:     now,
+	}
+}
+
+func NewPaymentEvent(id int, provider, transactionId, contentType string, headers map[string]string, body, hash string, validSignature bool, outcome, message string, created, updated int64) *PaymentEvent {
+
+	return &PaymentEvent{
+		id:             id,
+		provider:       provider,
+		transactionId:  transactionId,
+		contentType:    contentType,
+		headers:        headers,
+		body:           body,
+		hash:           hash,
+		validSignature: validSignature,
+		outcome:        outcome,
+		message:        message,
+		created:        created,
+		updated:        updated,
+	}
+}
+
+// PaymentEvent is a raw provider callback stored as it was received
+type PaymentEvent struct {
+	id             int
+	provider       string
+	transactionId  string
+	contentType    string
+	headers        map[string]string
+	body           string
+	hash           string
+	validSignature bool
+	outcome        string
+	message        string
+	created        int64
+	updated        int64
+}
+
+func (e *PaymentEvent) GetId() int {
+	return e.id
+}
+func (e *PaymentEvent) GetProvider() string {
+	return e.provider
+}
+func (e *PaymentEvent) GetTransactionId() string {
+	return e.transactionId
+}
+func (e *PaymentEvent) GetContentType() string {
+	return e.contentType
+}
+
+// GetHeaders returns request headers, some providers sign callback in header
+func (e *PaymentEvent) GetHeaders() map[string]string {
+	return e.headers
+}
+func (e *PaymentEvent) GetBody() string {
+	return e.body
+}
+func (e *PaymentEvent) GetHash() string {
+	return e.hash
+}
+func (e *PaymentEvent) IsValidSignature() bool {
+	return e.validSignature
+}
+func (e *PaymentEvent) GetOutcome() string {
+	return e.outcome
+}
+func (e *PaymentEvent) GetMessage() string {
+	return e.message
+}
+func (e *PaymentEvent) GetCreatedTime() time.Time {
+	return time.Unix(e.created, 0)
+}
+func (e *PaymentEvent) GetUpdatedTime() time.Time {
+	return time.Unix(e.updated, 0)
+}
+func (e *PaymentEvent) TouchUpdated() {
+	e.updated = time.Now().Unix()
+}
+func (e *PaymentEvent) SetTransactionId(transactionId string) {
+	e.transactionId = transactionId
+}
+func (e *PaymentEvent) SetValidSignature(valid bool) {
+	e.validSignature = valid
+}
+func (e *PaymentEvent) UpdateOutcome(outcome, message string) {
+	e.outcome = outcome
+	e.message = message
+}
+
+// IsHandled reports whether side effects of the event were already applied or deliberately skipped
+func (e *PaymentEvent) IsHandled() bool {
+	return e.outcome == PaymentEventOutcomeProcessed || e.outcome == PaymentEventOutcomeSkipped
+}
+
+// IsProcessing reports whether the new event may still be processed by another delivery of the callback,
+// the one not finished within timeout is considered lost
+func (e *PaymentEvent) IsProcessing(now time.Time, timeout time.Duration) bool {
+	return e.outcome == PaymentEventOutcomeNew && now.Sub(e.GetUpdatedTime()) < timeout
+}
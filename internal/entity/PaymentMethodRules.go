@@ -0,0 +1,52 @@
+package entity
+
+const PaymentRuleReasonMinAmount = "min_amount"
+const PaymentRuleReasonParts = "parts"
+const PaymentRuleReasonExcludedGroup = "excluded_group"
+const PaymentRuleReasonCurrency = "currency"
+
+// PaymentMethodRules limits carts the payment method is available for, zero values don't limit anything
+type PaymentMethodRules struct {
+	// MinAmount is the smallest cart total in cents
+	MinAmount      int
+	MinParts       int
+	MaxParts       int
+	ExcludedGroups []int
+}
+
+func NewPaymentCart(amount int, groupIds []int, parts int) PaymentCart {
+	return PaymentCart{Amount: amount, GroupIds: groupIds, Parts: parts}
+}
+
+// PaymentCart is what payment method rules are checked against
+type PaymentCart struct {
+	// Amount is a cart total in cents
+	Amount   int
+	GroupIds []int
+	// Parts is a count of parts chosen for parts pay, 0 when it isn't chosen yet
+	Parts int
+	// Items is a count of products in cart, 0 when it's unknown
+	Items int
+}
+
+// Check returns the reason why the cart isn't eligible for the payment method or empty string
+func (r PaymentMethodRules) Check(cart PaymentCart) string {
+
+	if r.MinAmount > 0 && cart.Amount < r.MinAmount {
+		return PaymentRuleReasonMinAmount
+	}
+
+	if cart.Parts > 0 && ((r.MinParts > 0 && cart.Parts < r.MinParts) || (r.MaxParts > 0 && cart.Parts > r.MaxParts)) {
+		return PaymentRuleReasonParts
+	}
+
+	for _, g := range cart.GroupIds {
+		for _, e := range r.ExcludedGroups {
+			if g == e {
+				return PaymentRuleReasonExcludedGroup
+			}
+		}
+	}
+
+	return ""
+}
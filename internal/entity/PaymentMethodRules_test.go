@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentMethodRules_Check(t *testing.T) {
+	rules := PaymentMethodRules{MinAmount: 50000, MinParts: 2, MaxParts: 6, ExcludedGroups: []int{7}}
+
+	tests := []struct {
+		tag      string
+		cart     PaymentCart
+		expected string
+	}{
+		{"t1", NewPaymentCart(50000, []int{1, 2}, 0), ""},
+		{"t2", NewPaymentCart(49999, []int{1}, 0), PaymentRuleReasonMinAmount},
+		{"t3", NewPaymentCart(60000, []int{1}, 7), PaymentRuleReasonParts},
+		{"t4", NewPaymentCart(60000, []int{1}, 1), PaymentRuleReasonParts},
+		{"t5", NewPaymentCart(60000, []int{1}, 6), ""},
+		{"t6", NewPaymentCart(60000, []int{1, 7}, 0), PaymentRuleReasonExcludedGroup},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, rules.Check(test.cart), test.tag)
+	}
+
+	assert.Equal(t, "", PaymentMethodRules{}.Check(NewPaymentCart(1, []int{7}, 24)), "t7")
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/spf13/viper"
+	"math"
 	"path/filepath"
 	"strconv"
 )
@@ -14,6 +15,11 @@ const defaultCurrencyName = "грн"
 const defaultCurrencyRate = 1
 const baseCurrency = "UAH"
 
+// currencyRatePrecision is a count of rate units in one, rates are rounded to 4 digits before conversion
+const currencyRatePrecision = 10000
+
+const ProductStatusActive = 1
+
 const photoLinkTypeOrigin = "origin"
 const photoLinkTypeThumb = "thumb"
 const photoLinkTypeSmall = "small"
@@ -112,9 +118,24 @@ type Currency struct {
 	ISO  string
 }
 
+// NewCurrencySnapshot restores the currency saved with order or payment, empty iso means orders created before the snapshot
+func NewCurrencySnapshot(iso string, rate float32) *Currency {
+	if IsBaseISO(iso) {
+		return DefaultCurrency()
+	}
+
+	return NewCurrency(0, iso, rate, iso)
+}
+
 func (c Currency) GetName() string {
 	return c.Name
 }
+func (c Currency) GetISO() string {
+	return c.ISO
+}
+func (c Currency) GetRate() float32 {
+	return c.Rate
+}
 
 func (c *Currency) IsBase() bool {
 	if c.ISO == baseCurrency {
@@ -124,6 +145,36 @@ func (c *Currency) IsBase() bool {
 	return false
 }
 
+// ToBase converts cents of the currency into cents of the base currency by its rate,
+// integer arithmetic keeps the result the same for the same rate regardless of float representation
+func (c *Currency) ToBase(cents int) int {
+	if c.IsBase() || c.Rate <= 0 {
+		return cents
+	}
+
+	rate := int64(math.Round(float64(c.Rate) * currencyRatePrecision))
+	amount := int64(cents) * rate
+
+	return int((amount + currencyRatePrecision/2) / currencyRatePrecision)
+}
+
+// FromBase converts cents of the base currency into cents of the currency by its rate, reverse of ToBase
+func (c *Currency) FromBase(cents int) int {
+	if c.IsBase() || c.Rate <= 0 {
+		return cents
+	}
+
+	rate := int64(math.Round(float64(c.Rate) * currencyRatePrecision))
+	amount := int64(cents) * currencyRatePrecision
+
+	return int((amount + rate/2) / rate)
+}
+
+// IsBaseISO reports whether iso is the code of the base currency, empty iso means the base one too
+func IsBaseISO(iso string) bool {
+	return iso == "" || iso == baseCurrency
+}
+
 func NewPrice(price, salePrice, saleCount int, currency *Currency) *Price {
 	var c *Currency
 
@@ -184,6 +235,11 @@ func (p *Price) GetPriceByQuantity(quantity int) int {
 	}
 }
 
+// GetBasePriceByQuantity returns unit price for the quantity converted into the base currency
+func (p *Price) GetBasePriceByQuantity(quantity int) int {
+	return p.Currency.ToBase(p.GetPriceByQuantity(quantity))
+}
+
 func (p *Price) toCurrency(cents int) string {
 	return fmt.Sprintf("%.2f", float64(cents)/100)
 }
@@ -218,32 +274,50 @@ type Group struct {
 	Meta        Meta
 }
 
-func NewSimpleProduct(id int, name string, code, exist, status int, price Price) *SimpleProduct {
+func NewSimpleProduct(id int, name string, code, exist, quantity, status int, price Price) *SimpleProduct {
 	return &SimpleProduct{
-		ID:     id,
-		Name:   name,
-		Code:   code,
-		Exist:  exist,
-		Status: status,
-		Price:  price,
+		ID:       id,
+		Name:     name,
+		Code:     code,
+		Exist:    exist,
+		Quantity: quantity,
+		Status:   status,
+		Price:    price,
 	}
 }
 
+// SimpleProduct Exist is the availability flag shown on the site, Quantity is the count in stock
 type SimpleProduct struct {
-	ID     int
-	Name   string
-	Code   int
-	Exist  int
-	Status int
+	ID       int
+	Name     string
+	Code     int
+	Exist    int
+	Quantity int
+	Status   int
 
 	Price Price
 }
+
+func (p SimpleProduct) IsActive() bool {
+	return p.Status == ProductStatusActive
+}
+
+// GetInStock returns the count in stock, products marked as not available have nothing to sell
+func (p SimpleProduct) GetInStock() int {
+	if p.Exist == 0 || p.Quantity < 0 {
+		return 0
+	}
+
+	return p.Quantity
+}
+
 type Product struct {
 	ID          int
 	Name        string
 	Description string
 	Code        int
 	Exist       int
+	Quantity    int
 	Status      int
 
 	Brand    Brand
@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimpleProduct_GetInStock(t *testing.T) {
+	assert.Equal(t, 5, SimpleProduct{Exist: 1, Quantity: 5}.GetInStock(), "t1")
+	assert.Equal(t, 0, SimpleProduct{Exist: 0, Quantity: 5}.GetInStock(), "t2")
+	assert.Equal(t, 0, SimpleProduct{Exist: 1, Quantity: 0}.GetInStock(), "t3")
+	assert.Equal(t, 0, SimpleProduct{Exist: 1, Quantity: -2}.GetInStock(), "t4")
+}
@@ -0,0 +1,260 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const PromoTypeFixed = "fixed"
+const PromoTypePercent = "percent"
+
+const PromoScopeCart = "cart"
+const PromoScopeGroup = "group"
+const PromoScopeBrand = "brand"
+
+const PromoReasonNotFound = "not_found"
+const PromoReasonInactive = "inactive"
+const PromoReasonExpired = "expired"
+const PromoReasonMinCartValue = "min_cart_value"
+const PromoReasonUsageLimit = "usage_limit"
+const PromoReasonNotApplicable = "not_applicable"
+const PromoReasonInvalid = "invalid"
+
+func NewPromoCode(id int, code, promoType string, value int, scope string, scopeIds []int, minCartValue, usageLimit, usageCount int, expires int64, active bool) *PromoCode {
+
+	return &PromoCode{
+		id:           id,
+		code:         code,
+		promoType:    promoType,
+		value:        value,
+		scope:        scope,
+		scopeIds:     scopeIds,
+		minCartValue: minCartValue,
+		usageLimit:   usageLimit,
+		usageCount:   usageCount,
+		expires:      expires,
+		active:       active,
+	}
+}
+
+// PromoCode gives a discount on the cart or on products of some groups or brands.
+// Value is in cents for fixed codes and in percents for percent ones, zero limits and expiry don't limit anything.
+type PromoCode struct {
+	id           int
+	code         string
+	promoType    string
+	value        int
+	scope        string
+	scopeIds     []int
+	minCartValue int
+	usageLimit   int
+	usageCount   int
+	expires      int64
+	active       bool
+}
+
+// PromoLine is an order item the discount is calculated for
+type PromoLine struct {
+	ProductId int
+	GroupId   int
+	BrandId   int
+	// Total is a line cost in cents
+	Total int
+}
+
+func (p *PromoCode) GetId() int {
+	return p.id
+}
+func (p *PromoCode) GetCode() string {
+	return p.code
+}
+func (p *PromoCode) GetType() string {
+	return p.promoType
+}
+func (p *PromoCode) GetValue() int {
+	return p.value
+}
+func (p *PromoCode) GetUsageCount() int {
+	return p.usageCount
+}
+
+// Validate checks the value is a discount at all, percent codes can't take more than the whole cost
+func (p *PromoCode) Validate() error {
+
+	if p.value <= 0 {
+		return errors.New(fmt.Sprintf("[promo code %s][value %d must be positive]", p.code, p.value))
+	}
+
+	if p.promoType == PromoTypePercent && p.value > 100 {
+		return errors.New(fmt.Sprintf("[promo code %s][percent value %d is over 100]", p.code, p.value))
+	}
+
+	return nil
+}
+
+// Check returns the reason why the code can't be applied to the cart or empty string
+func (p *PromoCode) Check(cartValue int, now time.Time) string {
+
+	if reason := p.Availability(now); reason != "" {
+		return reason
+	}
+
+	return p.CheckCartValue(cartValue)
+}
+
+// CheckCartValue returns PromoReasonMinCartValue when the cart is cheaper than the code requires or empty string
+func (p *PromoCode) CheckCartValue(cartValue int) string {
+
+	if cartValue < p.minCartValue {
+		return PromoReasonMinCartValue
+	}
+
+	return ""
+}
+
+// Availability returns the reason why the code can't be used at all or empty string
+func (p *PromoCode) Availability(now time.Time) string {
+
+	if p.Validate() != nil {
+		return PromoReasonInvalid
+	}
+
+	if p.active == false {
+		return PromoReasonInactive
+	}
+
+	if p.expires > 0 && now.Unix() > p.expires {
+		return PromoReasonExpired
+	}
+
+	if p.usageLimit > 0 && p.usageCount >= p.usageLimit {
+		return PromoReasonUsageLimit
+	}
+
+	return ""
+}
+
+// Use counts the code applied to one more order
+func (p *PromoCode) Use() {
+	p.usageCount++
+}
+
+// Release gives back the usage of the order which wasn't paid
+func (p *PromoCode) Release() {
+	if p.usageCount > 0 {
+		p.usageCount--
+	}
+}
+
+// Calculate returns discount in cents of every line in scope by product id.
+// Fixed value is spread over lines in proportion to their cost, no line discount exceeds the line cost.
+func (p *PromoCode) Calculate(lines []PromoLine) map[int]int {
+
+	discounts := make(map[int]int)
+
+	var (
+		eligible []PromoLine
+		total    int
+	)
+
+	for _, l := range lines {
+		if p.inScope(l) {
+			eligible = append(eligible, l)
+			total += l.Total
+		}
+	}
+
+	if total == 0 {
+		return discounts
+	}
+
+	switch p.promoType {
+	case PromoTypePercent:
+		for _, l := range eligible {
+			discounts[l.ProductId] = clampDiscount(int(math.Round(float64(l.Total)*float64(p.value)/100)), l.Total)
+		}
+	case PromoTypeFixed:
+		amount := p.value
+
+		if amount > total {
+			amount = total
+		}
+
+		rest := amount
+
+		for k, l := range eligible {
+			if k == len(eligible)-1 {
+				discounts[l.ProductId] = clampDiscount(rest, l.Total)
+				break
+			}
+
+			d := clampDiscount(amount*l.Total/total, l.Total)
+			discounts[l.ProductId] = d
+			rest -= d
+		}
+	}
+
+	return discounts
+}
+
+func clampDiscount(discount, total int) int {
+
+	if discount < 0 {
+		return 0
+	}
+
+	if discount > total {
+		return total
+	}
+
+	return discount
+}
+
+func (p *PromoCode) inScope(l PromoLine) bool {
+
+	var id int
+
+	switch p.scope {
+	case PromoScopeGroup:
+		id = l.GroupId
+	case PromoScopeBrand:
+		id = l.BrandId
+	default:
+		return true
+	}
+
+	for _, v := range p.scopeIds {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewOrderDiscount(code string, amount int) *OrderDiscount {
+	return &OrderDiscount{code: code, amount: amount}
+}
+
+// OrderDiscount is the promo code applied to the order and the sum it took off in cents
+type OrderDiscount struct {
+	code   string
+	amount int
+	// released is set when the usage counted for the order is given back, e.g. its payment failed
+	released bool
+}
+
+func (d OrderDiscount) GetCode() string {
+	return d.code
+}
+func (d OrderDiscount) GetAmount() int {
+	return d.amount
+}
+func (d OrderDiscount) IsReleased() bool {
+	return d.released
+}
+func (d *OrderDiscount) SetReleased(released bool) {
+	d.released = released
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromoCode_Check(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		tag      string
+		promo    *PromoCode
+		cart     int
+		expected string
+	}{
+		{"t1", NewPromoCode(1, "A", PromoTypeFixed, 1000, PromoScopeCart, nil, 5000, 0, 0, 0, true), 5000, ""},
+		{"t2", NewPromoCode(1, "A", PromoTypeFixed, 1000, PromoScopeCart, nil, 5000, 0, 0, 0, true), 4999, PromoReasonMinCartValue},
+		{"t3", NewPromoCode(1, "A", PromoTypeFixed, 1000, PromoScopeCart, nil, 0, 0, 0, 0, false), 5000, PromoReasonInactive},
+		{"t4", NewPromoCode(1, "A", PromoTypeFixed, 1000, PromoScopeCart, nil, 0, 0, 0, now.Unix()-1, true), 5000, PromoReasonExpired},
+		{"t5", NewPromoCode(1, "A", PromoTypeFixed, 1000, PromoScopeCart, nil, 0, 3, 3, 0, true), 5000, PromoReasonUsageLimit},
+		{"t6", NewPromoCode(1, "A", PromoTypeFixed, 1000, PromoScopeCart, nil, 0, 3, 2, now.Unix(), true), 5000, ""},
+		{"t7", NewPromoCode(1, "A", PromoTypePercent, 101, PromoScopeCart, nil, 0, 0, 0, 0, true), 5000, PromoReasonInvalid},
+		{"t8", NewPromoCode(1, "A", PromoTypeFixed, -100, PromoScopeCart, nil, 0, 0, 0, 0, true), 5000, PromoReasonInvalid},
+		{"t9", NewPromoCode(1, "A", PromoTypePercent, 100, PromoScopeCart, nil, 0, 0, 0, 0, true), 5000, ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.promo.Check(test.cart, now), test.tag)
+	}
+}
+
+func TestPromoCode_Calculate(t *testing.T) {
+	lines := []PromoLine{
+		{ProductId: 1, GroupId: 10, BrandId: 100, Total: 10000},
+		{ProductId: 2, GroupId: 20, BrandId: 100, Total: 5000},
+		{ProductId: 3, GroupId: 10, BrandId: 200, Total: 333},
+	}
+
+	percent := NewPromoCode(1, "P", PromoTypePercent, 15, PromoScopeGroup, []int{10}, 0, 0, 0, 0, true)
+	assert.Equal(t, map[int]int{1: 1500, 3: 50}, percent.Calculate(lines))
+
+	fixed := NewPromoCode(1, "F", PromoTypeFixed, 1000, PromoScopeBrand, []int{100}, 0, 0, 0, 0, true)
+	assert.Equal(t, map[int]int{1: 666, 2: 334}, fixed.Calculate(lines))
+
+	capped := NewPromoCode(1, "C", PromoTypeFixed, 100000, PromoScopeCart, nil, 0, 0, 0, 0, true)
+	assert.Equal(t, map[int]int{1: 10000, 2: 5000, 3: 333}, capped.Calculate(lines))
+
+	none := NewPromoCode(1, "N", PromoTypeFixed, 1000, PromoScopeGroup, []int{30}, 0, 0, 0, 0, true)
+	assert.Empty(t, none.Calculate(lines))
+
+	over := NewPromoCode(1, "O", PromoTypePercent, 150, PromoScopeCart, nil, 0, 0, 0, 0, true)
+	assert.Equal(t, map[int]int{1: 10000, 2: 5000, 3: 333}, over.Calculate(lines))
+
+	negative := NewPromoCode(1, "M", PromoTypeFixed, -1000, PromoScopeCart, nil, 0, 0, 0, 0, true)
+	assert.Equal(t, map[int]int{1: 0, 2: 0, 3: 0}, negative.Calculate(lines))
+}
@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+const RefundStatusNew = 1
+const RefundStatusDone = 2
+const RefundStatusFailed = 3
+
+func CreateNewRefundByPayment(id int, payment *Payment, amount int, comment string) *Refund {
+
+	now := time.Now().Unix()
+	// refund is made in the currency the payment was charged in
+	currency := payment.GetPrice().GetCurrency()
+
+	return &Refund{
+		id:            id,
+		transactionId: uuid.NewString(),
+		paymentId:     payment.GetId(),
+		orderId:       payment.GetOrderId(),
+		price:         NewPrice(amount, 0, 0, &currency),
+		status:        RefundStatusNew,
+		comment:       comment,
+		created:       now,
+		updated:       now,
+	}
+}
+
+func NewRefund(id int, transactionId string, paymentId, orderId int, price *Price, status int, comment string, created, updated int64) *Refund {
+
+	return &Refund{
+		id:            id,
+		transactionId: transactionId,
+		paymentId:     paymentId,
+		orderId:       orderId,
+		price:         price,
+		status:        status,
+		comment:       comment,
+		created:       created,
+		updated:       updated,
+	}
+}
+
+// Refund is a full or partial return of the money of a done payment
+type Refund struct {
+	id            int
+	transactionId string
+	paymentId     int
+	orderId       int
+	price         *Price
+	status        int
+	comment       string
+	created       int64
+	updated       int64
+}
+
+func (r *Refund) GetId() int {
+	return r.id
+}
+func (r *Refund) GetTransactionId() string {
+	return r.transactionId
+}
+func (r *Refund) GetPaymentId() int {
+	return r.paymentId
+}
+func (r *Refund) GetOrderId() int {
+	return r.orderId
+}
+func (r *Refund) GetPrice() *Price {
+	return r.price
+}
+func (r *Refund) GetStatus() int {
+	return r.status
+}
+func (r *Refund) GetComment() string {
+	return r.comment
+}
+func (r *Refund) GetDescription() string {
+	return fmt.Sprintf("Возврат по заказу № %d", r.orderId)
+}
+func (r *Refund) GetCreatedTime() time.Time {
+	return time.Unix(r.created, 0)
+}
+func (r *Refund) GetUpdatedTime() time.Time {
+	return time.Unix(r.updated, 0)
+}
+func (r *Refund) TouchUpdated() {
+	r.updated = time.Now().Unix()
+}
+func (r *Refund) UpdateStatus(status int, comment string) {
+	r.status = status
+
+	if comment != "" {
+		r.comment = comment
+	}
+}
+func (r *Refund) IsDone() bool {
+	return r.status == RefundStatusDone
+}
@@ -0,0 +1,63 @@
+package entity
+
+import "fmt"
+
+// allowed payment status transitions, the key is the current status
+var paymentStatusTransitions = map[int][]int{
+	PaymentStatusNew:                 {PaymentStatusWaitingConfirmation, PaymentStatusConfirmed, PaymentStatusPending, PaymentStatusDone, PaymentStatusFailed, PaymentStatusCanceled},
+	PaymentStatusPending:             {PaymentStatusWaitingConfirmation, PaymentStatusConfirmed, PaymentStatusDone, PaymentStatusFailed, PaymentStatusCanceled},
+	PaymentStatusWaitingConfirmation: {PaymentStatusConfirmed, PaymentStatusDone, PaymentStatusRefund, PaymentStatusFailed, PaymentStatusCanceled},
+	PaymentStatusConfirmed:           {PaymentStatusDone, PaymentStatusRefund, PaymentStatusFailed, PaymentStatusCanceled},
+	PaymentStatusFailed:              {PaymentStatusPending, PaymentStatusWaitingConfirmation, PaymentStatusConfirmed, PaymentStatusDone, PaymentStatusCanceled},
+	PaymentStatusDone:                {PaymentStatusRefund},
+	PaymentStatusRefund:              {},
+	PaymentStatusCanceled:            {},
+}
+
+// allowed delivery status transitions, the key is the current status
+var deliveryStatusTransitions = map[int][]int{
+	DeliveryStatusNew:             {DeliveryStatusCheck, DeliveryStatusWaitingDelivery, DeliveryStatusDelivery, DeliveryStatusCanceled},
+	DeliveryStatusCheck:           {DeliveryStatusWaitingDelivery, DeliveryStatusDelivery, DeliveryStatusCanceled},
+	DeliveryStatusWaitingDelivery: {DeliveryStatusDelivery, DeliveryStatusCanceled},
+	DeliveryStatusDelivery:        {DeliveryStatusReadyToReceive, DeliveryStatusCanceled},
+	DeliveryStatusReadyToReceive:  {DeliveryStatusCanceled},
+	DeliveryStatusCanceled:        {},
+}
+
+type PaymentStatusTransitionError struct {
+	From int
+	To   int
+}
+
+func (e *PaymentStatusTransitionError) Error() string {
+	return fmt.Sprintf("payment status can't be changed from '%s' to '%s'", StatusLabel(e.From), StatusLabel(e.To))
+}
+
+type DeliveryStatusTransitionError struct {
+	From int
+	To   int
+}
+
+func (e *DeliveryStatusTransitionError) Error() string {
+	return fmt.Sprintf("delivery status can't be changed from '%s' to '%s'", DeliveryStatusLabel(e.From), DeliveryStatusLabel(e.To))
+}
+
+// CanChangePaymentStatus reports whether payment in status from may move to status to
+func CanChangePaymentStatus(from, to int) bool {
+	return inTransitions(paymentStatusTransitions, from, to)
+}
+
+// CanChangeDeliveryStatus reports whether delivery in status from may move to status to
+func CanChangeDeliveryStatus(from, to int) bool {
+	return inTransitions(deliveryStatusTransitions, from, to)
+}
+
+func inTransitions(transitions map[int][]int, from, to int) bool {
+	for _, v := range transitions[from] {
+		if v == to {
+			return true
+		}
+	}
+
+	return false
+}
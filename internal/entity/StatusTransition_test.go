@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanChangePaymentStatus(t *testing.T) {
+	tests := []struct {
+		tag      string
+		from, to int
+		expected bool
+	}{
+		{"t1", PaymentStatusNew, PaymentStatusWaitingConfirmation, true},
+		{"t2", PaymentStatusWaitingConfirmation, PaymentStatusDone, true},
+		{"t3", PaymentStatusDone, PaymentStatusFailed, false},
+		{"t4", PaymentStatusDone, PaymentStatusWaitingConfirmation, false},
+		{"t5", PaymentStatusDone, PaymentStatusRefund, true},
+		{"t6", PaymentStatusFailed, PaymentStatusDone, true},
+		{"t7", PaymentStatusCanceled, PaymentStatusDone, false},
+		{"t8", PaymentStatusRefund, PaymentStatusNew, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, CanChangePaymentStatus(test.from, test.to), test.tag)
+	}
+}
+
+func TestOrder_UpdatePaymentStatus(t *testing.T) {
+	o := NewOrder(1, 0, "", false, 100, nil, nil, nil, NewOrderPayment(PaymentStatusDone, nil, nil, "", "", "", 0), nil)
+
+	err := o.UpdatePaymentStatus(PaymentStatusFailed, "late callback")
+
+	assert.IsType(t, &PaymentStatusTransitionError{}, err)
+	assert.Equal(t, PaymentStatusDone, o.GetPayment().GetStatus())
+	assert.Len(t, o.GetPayment().GetStatusHistory(), 0)
+
+	assert.NoError(t, o.UpdatePaymentStatus(PaymentStatusDone, "same status"))
+	assert.Len(t, o.GetPayment().GetStatusHistory(), 0)
+
+	assert.NoError(t, o.UpdatePaymentStatus(PaymentStatusRefund, "refund"))
+	assert.Equal(t, PaymentStatusRefund, o.GetPayment().GetStatus())
+	assert.Len(t, o.GetPayment().GetStatusHistory(), 1)
+}
+
+func TestOrder_UpdateDeliveryStatus(t *testing.T) {
+	o := NewOrder(1, 0, "", false, 100, nil, nil, NewOrderDelivery(DeliveryStatusReadyToReceive, nil, nil, nil), nil, nil)
+
+	err := o.UpdateDeliveryStatus(DeliveryStatusNew, "")
+
+	assert.IsType(t, &DeliveryStatusTransitionError{}, err)
+	assert.Equal(t, "delivery status can't be changed from 'Ready to receive' to 'New'", err.Error())
+	assert.NoError(t, o.UpdateDeliveryStatus(DeliveryStatusCanceled, "returned"))
+	assert.Equal(t, DeliveryStatusCanceled, o.GetDelivery().GetStatus())
+}
@@ -0,0 +1,91 @@
+package entity
+
+// NewParcel describes the shipment, weight is in kilograms and declared value in cents
+func NewParcel(weight float64, seats, declaredValue int, description string) *Parcel {
+
+	return &Parcel{
+		weight:        weight,
+		seats:         seats,
+		declaredValue: declaredValue,
+		description:   description,
+	}
+}
+
+type Parcel struct {
+	weight        float64
+	seats         int
+	declaredValue int
+	description   string
+}
+
+func (p *Parcel) GetWeight() float64 {
+	return p.weight
+}
+func (p *Parcel) GetSeats() int {
+	return p.seats
+}
+func (p *Parcel) GetDeclaredValue() Price {
+	return *NewPrice(p.declaredValue, 0, 0, nil)
+}
+func (p *Parcel) GetDescription() string {
+	return p.description
+}
+
+// NewWaybill is the carrier document of the shipment, cost is delivery cost in cents
+func NewWaybill(ref, number string, cost int, estimatedDelivery string) *Waybill {
+
+	return &Waybill{
+		ref:               ref,
+		number:            number,
+		cost:              cost,
+		estimatedDelivery: estimatedDelivery,
+	}
+}
+
+type Waybill struct {
+	ref               string
+	number            string
+	cost              int
+	estimatedDelivery string
+}
+
+func (w *Waybill) GetRef() string {
+	return w.ref
+}
+
+// GetNumber returns the tracking number
+func (w *Waybill) GetNumber() string {
+	return w.number
+}
+func (w *Waybill) GetCost() Price {
+	return *NewPrice(w.cost, 0, 0, nil)
+}
+func (w *Waybill) GetEstimatedDelivery() string {
+	return w.estimatedDelivery
+}
+
+// NewWaybillStatus is the carrier state of the waybill, status is one of DeliveryStatus constants or 0 if it has no match
+func NewWaybillStatus(number string, status int, description string) *WaybillStatus {
+
+	return &WaybillStatus{
+		number:      number,
+		status:      status,
+		description: description,
+	}
+}
+
+type WaybillStatus struct {
+	number      string
+	status      int
+	description string
+}
+
+func (s *WaybillStatus) GetNumber() string {
+	return s.number
+}
+func (s *WaybillStatus) GetStatus() int {
+	return s.status
+}
+func (s *WaybillStatus) GetDescription() string {
+	return s.description
+}
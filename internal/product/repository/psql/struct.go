@@ -91,6 +91,7 @@ type Product struct {
 	Description string
 	Code 		int
 	Exist 		int
+	Quantity 	int
 	Status 		int
 	Brand
 	Group
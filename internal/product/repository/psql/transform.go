@@ -76,6 +76,7 @@ func rowToProductEntity(row *Product) *entity.Product {
 		Description: row.Description,
 		Code: row.Code,
 		Exist: row.Exist,
+		Quantity: row.Quantity,
 		Status: row.Status,
 		Group: entity.Group{
 			ID: row.GroupID,
@@ -45,10 +45,16 @@ type Country struct {
 	Name string `json:"name"`
 }
 
+type DeliveryEstimate struct {
+	Cost *Price `json:"cost"`
+	Date string `json:"date"`
+}
+
 type DeliveryInfo struct {
-	DeliveryMethod *DeliveryMethod  `json:"deliveryMethod"`
-	PaymentMethods []*PaymentMethod `json:"paymentMethods"`
-	Warehouses     []*Warehouse     `json:"warehouses"`
+	DeliveryMethod *DeliveryMethod   `json:"deliveryMethod"`
+	PaymentMethods []*PaymentMethod  `json:"paymentMethods"`
+	Warehouses     []*Warehouse      `json:"warehouses"`
+	Estimate       *DeliveryEstimate `json:"estimate"`
 }
 
 type DeliveryMethod struct {
@@ -68,6 +74,14 @@ type Group struct {
 	Description *string `json:"description"`
 }
 
+type Installment struct {
+	Type       string `json:"type"`
+	Parts      int    `json:"parts"`
+	Payment    *Price `json:"payment"`
+	Commission *Price `json:"commission"`
+	Total      *Price `json:"total"`
+}
+
 type Pages struct {
 	Page       int        `json:"page"`
 	PerPage    int        `json:"perPage"`
@@ -143,6 +157,19 @@ type SimpleProduct struct {
 	MainPhoto   *Photo    `json:"mainPhoto"`
 }
 
+type Street struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type TimeSlot struct {
+	ID        string `json:"id"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Available int    `json:"available"`
+}
+
 type TreeChildrenMenuItem struct {
 	ID          int                     `json:"id"`
 	Name        string                  `json:"name"`
@@ -188,12 +215,23 @@ type Warehouse struct {
 	Phone     string `json:"phone"`
 	Number    int    `json:"number"`
 	MaxWeight int    `json:"maxWeight"`
+	Type      string `json:"type"`
+}
+
+type CartItem struct {
+	ProductID int `json:"productId"`
+	Count     int `json:"count"`
 }
 
 type CityID struct {
 	ID string `json:"id"`
 }
 
+type CourierTimeSlots struct {
+	CityID string `json:"cityId"`
+	Date   string `json:"date"`
+}
+
 type ID struct {
 	ID int `json:"id"`
 }
@@ -207,6 +245,12 @@ type Ids struct {
 	Ids []*int `json:"ids"`
 }
 
+type Installments struct {
+	ProductID *int    `json:"productId"`
+	Amount    *int    `json:"amount"`
+	Type      *string `json:"type"`
+}
+
 type Page struct {
 	Page    int `json:"page"`
 	PerPage int `json:"perPage"`
@@ -224,6 +268,17 @@ type PageByIds struct {
 	PerPage int    `json:"perPage"`
 }
 
+type StreetSearch struct {
+	CityID string `json:"cityId"`
+	Text   string `json:"text"`
+}
+
 type Text struct {
 	Text string `json:"text"`
 }
+
+type WarehouseFilter struct {
+	Type   *string `json:"type"`
+	Weight *int    `json:"weight"`
+	OpenAt *string `json:"openAt"`
+}
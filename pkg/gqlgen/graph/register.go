@@ -4,16 +4,17 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
+	"github.com/wowucco/G3/internal/checkout"
 	"github.com/wowucco/G3/internal/delivery"
 	"github.com/wowucco/G3/internal/menu"
 	"github.com/wowucco/G3/internal/product"
 	"github.com/wowucco/G3/pkg/gqlgen/graph/generated"
 )
 
-func RegisterGraphql(router *gin.RouterGroup, uc product.UseCase, r product.ReadRepository, m menu.ReadRepository, d delivery.DeliveryReadRepository)  {
+func RegisterGraphql(router *gin.RouterGroup, uc product.UseCase, r product.ReadRepository, m menu.ReadRepository, d delivery.DeliveryReadRepository, st delivery.IStreetService, o checkout.IOrderUseCase)  {
 	//srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: &Resolver{useCase: uc}}))
 
-	cnf := generated.Config{Resolvers: &Resolver{useCase: uc, productRead: r, menuRead: m, deliveryRead: d}}
+	cnf := generated.Config{Resolvers: &Resolver{useCase: uc, productRead: r, menuRead: m, deliveryRead: d, streets: st, orderManage: o}}
 
 	gql := router.Group("/graphql")
 	{
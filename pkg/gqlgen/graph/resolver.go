@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"github.com/wowucco/G3/internal/checkout"
 	"github.com/wowucco/G3/internal/delivery"
 	"github.com/wowucco/G3/internal/menu"
 	"github.com/wowucco/G3/internal/product"
@@ -15,4 +16,6 @@ type Resolver struct{
 	productRead product.ReadRepository
 	menuRead menu.ReadRepository
 	deliveryRead delivery.DeliveryReadRepository
+	streets delivery.IStreetService
+	orderManage checkout.IOrderUseCase
 }
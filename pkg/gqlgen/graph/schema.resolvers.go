@@ -6,6 +6,7 @@ package graph
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/wowucco/G3/internal/entity"
 	"github.com/wowucco/G3/pkg/gqlgen/graph/generated"
@@ -347,8 +348,26 @@ func (r *queryResolver) CityByID(ctx context.Context, input *model.CityID) (*mod
 	}, nil
 }
 
-func (r *queryResolver) DeliveryInfoByCityID(ctx context.Context, input *model.CityID) ([]*model.DeliveryInfo, error) {
-	d, e := r.deliveryRead.GetDeliveryInfoByCityId(ctx, input.ID)
+func (r *queryResolver) DeliveryInfoByCityID(ctx context.Context, input *model.CityID, cart []*model.CartItem, warehouses *model.WarehouseFilter) ([]*model.DeliveryInfo, error) {
+	filter, err := warehouseFilter(warehouses)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var paymentCart *entity.PaymentCart
+
+	if len(cart) > 0 {
+		c, err := r.paymentCart(ctx, cart)
+
+		if err != nil {
+			return nil, err
+		}
+
+		paymentCart = c
+	}
+
+	d, e := r.deliveryRead.GetDeliveryInfoByCityId(ctx, input.ID, paymentCart, filter)
 
 	if e != nil {
 		return nil, e
@@ -367,11 +386,103 @@ func (r *queryResolver) DeliveryInfoByCityID(ctx context.Context, input *model.C
 			PaymentMethods: deliveryPaymentMethods(value.PaymentMethods),
 			Warehouses:     deliveryWarehouses(value.Warehouses),
 		}
+
+		if value.Estimate != nil {
+			deliveryInfos[i].Estimate = &model.DeliveryEstimate{
+				Cost: toPrice(value.Estimate.GetCost()),
+				Date: value.Estimate.GetDate().Format("2006-01-02"),
+			}
+		}
 	}
 
 	return deliveryInfos, nil
 }
 
+func (r *queryResolver) SearchStreet(ctx context.Context, input model.StreetSearch) ([]*model.Street, error) {
+	found, err := r.streets.Search(ctx, input.CityID, input.Text)
+
+	if err != nil {
+		return nil, err
+	}
+
+	streets := make([]*model.Street, len(found))
+
+	for k, v := range found {
+		streets[k] = &model.Street{
+			ID:   v.ID,
+			Name: v.Name,
+			Type: v.Type,
+		}
+	}
+
+	return streets, nil
+}
+
+func (r *queryResolver) CourierTimeSlots(ctx context.Context, input model.CourierTimeSlots) ([]*model.TimeSlot, error) {
+	if _, err := time.Parse(entity.DeliverySlotDateLayout, input.Date); err != nil {
+		return nil, fmt.Errorf("date isn't in 2006-01-02 format")
+	}
+
+	found, err := r.deliveryRead.GetCourierTimeSlots(ctx, input.CityID, input.Date)
+
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make([]*model.TimeSlot, len(found))
+
+	for k, v := range found {
+		slots[k] = &model.TimeSlot{
+			ID:        v.GetId(),
+			From:      v.GetFrom(),
+			To:        v.GetTo(),
+			Available: v.GetAvailable(),
+		}
+	}
+
+	return slots, nil
+}
+
+func (r *queryResolver) Installments(ctx context.Context, input *model.Installments) ([]*model.Installment, error) {
+	form := installmentsForm{}
+
+	if input != nil {
+		if input.ProductID != nil {
+			form.productId = *input.ProductID
+		}
+		if input.Amount != nil {
+			form.amount = *input.Amount
+		}
+		if input.Type != nil {
+			form.installmentType = *input.Type
+		}
+	}
+
+	if form.productId == 0 && form.amount <= 0 {
+		return nil, fmt.Errorf("product id or amount is required")
+	}
+
+	installments, err := r.orderManage.Installments(ctx, form)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*model.Installment, len(installments))
+
+	for k, v := range installments {
+		items[k] = &model.Installment{
+			Type:       v.GetType(),
+			Parts:      v.GetParts(),
+			Payment:    toPrice(v.GetPayment()),
+			Commission: toPrice(v.GetCommission()),
+			Total:      toPrice(v.GetTotal()),
+		}
+	}
+
+	return items, nil
+}
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
@@ -395,6 +506,7 @@ func deliveryWarehouses(w []entity.Warehouse) []*model.Warehouse {
 			Phone:     v.Phone,
 			Number:    v.Number,
 			MaxWeight: v.MaxWeight,
+			Type:      v.Type,
 		}
 	}
 
@@ -556,3 +668,90 @@ func toProduct(p *entity.Product) *model.Product {
 }
 
 type mutationResolver struct{ *Resolver }
+
+type installmentsForm struct {
+	productId       int
+	amount          int
+	installmentType string
+}
+
+func (f installmentsForm) GetProductId() int {
+	return f.productId
+}
+func (f installmentsForm) GetAmount() int {
+	return f.amount
+}
+func (f installmentsForm) GetType() string {
+	return f.installmentType
+}
+
+func toPrice(p *entity.Price) *model.Price {
+	return &model.Price{
+		Price:        p.CentToCurrency(),
+		PriceInCents: p.GetInCent(),
+		Currency:     p.GetCurrency().GetName(),
+	}
+}
+
+// paymentCart sums the cart in base currency, payment methods are filtered and delivery is estimated by it
+func (r *queryResolver) paymentCart(ctx context.Context, items []*model.CartItem) (*entity.PaymentCart, error) {
+	ids := make([]int, len(items))
+	counts := make(map[int]int, len(items))
+
+	for k, v := range items {
+		ids[k] = v.ProductID
+		counts[v.ProductID] += v.Count
+	}
+
+	ps, err := r.productRead.GetByIdsWithSequence(ctx, ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var amount, quantity int
+
+	groupIds := make([]int, len(ps))
+
+	for k, p := range ps {
+		amount += p.Price.GetBasePriceByQuantity(counts[p.ID]) * counts[p.ID]
+		quantity += counts[p.ID]
+		groupIds[k] = p.Group.ID
+	}
+
+	cart := entity.NewPaymentCart(amount, groupIds, 0)
+	cart.Items = quantity
+
+	return &cart, nil
+}
+
+// warehouseFilter parses openAt in server local time
+func warehouseFilter(input *model.WarehouseFilter) (entity.WarehouseFilter, error) {
+	filter := entity.WarehouseFilter{}
+
+	if input == nil {
+		return filter, nil
+	}
+
+	if input.Type != nil {
+		if *input.Type != entity.WarehouseTypeBranch && *input.Type != entity.WarehouseTypePostomat {
+			return filter, fmt.Errorf("unknown warehouse type %s", *input.Type)
+		}
+		filter.Type = *input.Type
+	}
+
+	if input.Weight != nil {
+		filter.Weight = *input.Weight
+	}
+
+	if input.OpenAt != nil {
+		t, err := time.ParseInLocation("2006-01-02 15:04", *input.OpenAt, time.Local)
+
+		if err != nil {
+			return filter, fmt.Errorf("openAt isn't in 2006-01-02 15:04 format")
+		}
+		filter.OpenAt = t
+	}
+
+	return filter, nil
+}
@@ -97,9 +97,58 @@ func (s *Service) PaymentStatusUpdated(order *entity.Order, payment *entity.Paym
 
 		smessage := fmt.Sprintf("Vashe zamovlennya # %d ne bulo oplacheno. My zatelefonuemo Vam najblizhchim chasom", order.GetId())
 		s.smsSend([]string{order.GetCustomer().GetPhone()}, smessage)
+	case entity.PaymentStatusCanceled:
+		tmessage := fmt.Sprintf("*Payment was canceled: * [%d](%s)", order.GetId(), s.makeLinkToOrder(order))
+		s.telegramMessageCustomerBlock(&tmessage, order)
+		s.telegramMessageOrderInfoBlock(&tmessage, order)
+		s.telegramSend(s.telegramChats[TelegramOrderChat], tmessage)
 	}
 }
 
+// send sms to client when the order is sent or waits in the pickup point, with tracking number if it's known
+func (s *Service) DeliveryStatusUpdated(order *entity.Order) {
+
+	var smsMessage string
+
+	switch order.GetDelivery().GetStatus() {
+	case entity.DeliveryStatusDelivery:
+		smsMessage = fmt.Sprintf("Vashe zamovlennya # %d vidpravleno.", order.GetId())
+	case entity.DeliveryStatusReadyToReceive:
+		smsMessage = fmt.Sprintf("Vashe zamovlennya # %d chekae na Vas u viddilenni.", order.GetId())
+	default:
+		return
+	}
+
+	if number := order.GetDelivery().GetTrackingNumber(); number != "" {
+		smsMessage = fmt.Sprintf("%s Nomer TTN %s", smsMessage, number)
+	}
+
+	s.smsSend([]string{order.GetCustomer().GetPhone()}, smsMessage)
+}
+
+// send message to order chat about refund of the payment
+func (s *Service) PaymentRefunded(order *entity.Order, payment *entity.Payment, refund *entity.Refund) {
+
+	var message string
+
+	if refund.IsDone() == true {
+		message = fmt.Sprintf("*Payment refunded for order: * [%d](%s)", order.GetId(), s.makeLinkToOrder(order))
+	} else {
+		message = fmt.Sprintf("*Refund failed for order: * [%d](%s)", order.GetId(), s.makeLinkToOrder(order))
+	}
+
+	message += fmt.Sprintf("\nrefund amount: _%s_\n", refund.GetPrice().CentToCurrency())
+	message += fmt.Sprintf("payment amount: _%s_\n", payment.GetPrice().CentToCurrency())
+
+	if refund.GetComment() != "" {
+		message += fmt.Sprintf("_Comment_\n%s\n", refund.GetComment())
+	}
+
+	s.telegramMessageCustomerBlock(&message, order)
+	s.telegramMessageOrderInfoBlock(&message, order)
+	s.telegramSend(s.telegramChats[TelegramOrderChat], message)
+}
+
 func (s *Service) Recall(phone, message string) {
 
 	if message == "" {
@@ -148,7 +197,22 @@ func (s *Service) telegramMessageSummaryBlock(message *string, order *entity.Ord
 	*message += "\n_Summary_\n"
 	*message += fmt.Sprintf("total items: _%d_\n", len(order.GetItems()))
 	*message += fmt.Sprintf("total cost: _%s_\n", (&totalCost).CentToCurrency())
+
+	if d := order.GetDiscount(); d != nil {
+		discount := entity.NewPrice(d.GetAmount(), 0, 0, nil)
+		*message += fmt.Sprintf("promo code: _%s_\tdiscount: _%s_\n", d.GetCode(), discount.CentToCurrency())
+	}
+
 	*message += fmt.Sprintf("delivery: _%s_\n", order.GetDelivery().GetMethod().GetName())
+
+	if courier := order.GetDelivery().GetCourier(); courier != nil {
+		*message += fmt.Sprintf("address: _%s, %s_\n", order.GetDelivery().GetWarehouse().GetCity().GetName(), courier.GetFullAddress())
+	}
+
+	if slot := order.GetDelivery().GetSlot(); slot != nil {
+		*message += fmt.Sprintf("time slot: _%s %s-%s_\n", slot.GetDate(), slot.GetFrom(), slot.GetTo())
+	}
+
 	*message += fmt.Sprintf("payment: _%s_\n", order.GetPayment().GetMethod().GetName())
 }
 func (s *Service) telegramMessageItemsBlock(message *string, order *entity.Order) {
@@ -0,0 +1,99 @@
+package novaposhta
+
+import (
+	"context"
+	"strconv"
+)
+
+const modelAddress = "Address"
+
+const WarehouseCategoryBranch = "Branch"
+const WarehouseCategoryPostomat = "Postomat"
+
+// AddressLimit is the page size of cities and warehouses lists
+const AddressLimit = 500
+
+type City struct {
+	Ref           string `json:"Ref"`
+	Description   string `json:"Description"`
+	DescriptionRu string `json:"DescriptionRu"`
+	Area          string `json:"Area"`
+}
+
+// Warehouse numbers and weights are strings in api, Schedule is open hours by weekday,
+// e.g. {"Monday": "08:00-20:00", "Sunday": "-"}
+type Warehouse struct {
+	Ref                          string            `json:"Ref"`
+	Description                  string            `json:"Description"`
+	DescriptionRu                string            `json:"DescriptionRu"`
+	ShortAddress                 string            `json:"ShortAddress"`
+	ShortAddressRu               string            `json:"ShortAddressRu"`
+	Phone                        string            `json:"Phone"`
+	Number                       string            `json:"Number"`
+	CityRef                      string            `json:"CityRef"`
+	CityDescription              string            `json:"CityDescription"`
+	CityDescriptionRu            string            `json:"CityDescriptionRu"`
+	SettlementRef                string            `json:"SettlementRef"`
+	SettlementDescription        string            `json:"SettlementDescription"`
+	SettlementAreaDescription    string            `json:"SettlementAreaDescription"`
+	SettlementRegionsDescription string            `json:"SettlementRegionsDescription"`
+	SettlementTypeDescription    string            `json:"SettlementTypeDescription"`
+	CategoryOfWarehouse          string            `json:"CategoryOfWarehouse"`
+	TotalMaxWeightAllowed        string            `json:"TotalMaxWeightAllowed"`
+	Schedule                     map[string]string `json:"Schedule"`
+}
+
+// GetCities returns the page of all cities, the last page is shorter than limit
+func (c *Client) GetCities(ctx context.Context, page, limit int) ([]City, error) {
+
+	var cities []City
+
+	if err := c.call(ctx, modelAddress, "getCities", pageProperties(page, limit), &cities); err != nil {
+		return nil, err
+	}
+
+	return cities, nil
+}
+
+// GetWarehouses returns the page of warehouses of all cities, the last page is shorter than limit
+func (c *Client) GetWarehouses(ctx context.Context, page, limit int) ([]Warehouse, error) {
+
+	var warehouses []Warehouse
+
+	if err := c.call(ctx, modelAddress, "getWarehouses", pageProperties(page, limit), &warehouses); err != nil {
+		return nil, err
+	}
+
+	return warehouses, nil
+}
+
+type Street struct {
+	Ref         string `json:"Ref"`
+	Description string `json:"Description"`
+	StreetsType string `json:"StreetsType"`
+}
+
+// GetStreets finds streets of the city by part of the name. searchSettlementStreets is not used
+// since it takes settlement refs and cities are referenced by city refs across the shop.
+func (c *Client) GetStreets(ctx context.Context, cityRef, text string, limit int) ([]Street, error) {
+
+	var streets []Street
+
+	properties := pageProperties(1, limit)
+	properties["CityRef"] = cityRef
+	properties["FindByString"] = text
+
+	if err := c.call(ctx, modelAddress, "getStreet", properties, &streets); err != nil {
+		return nil, err
+	}
+
+	return streets, nil
+}
+
+func pageProperties(page, limit int) map[string]string {
+
+	return map[string]string{
+		"Page":  strconv.Itoa(page),
+		"Limit": strconv.Itoa(limit),
+	}
+}
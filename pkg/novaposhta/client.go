@@ -0,0 +1,116 @@
+// Package novaposhta calls Nova Poshta json api methods
+package novaposhta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const baseUrl = "https://api.novaposhta.ua/v2.0/json/"
+
+type Config struct {
+	ApiKey string
+
+	Transport http.RoundTripper
+	BaseUrl   *url.URL
+}
+
+func NewClient(cfg Config) *Client {
+
+	if cfg.Transport == nil {
+		cfg.Transport = http.DefaultTransport
+	}
+
+	if cfg.BaseUrl == nil {
+		cfg.BaseUrl, _ = url.Parse(baseUrl)
+	}
+
+	return &Client{
+		apiKey:    cfg.ApiKey,
+		transport: cfg.Transport,
+		url:       cfg.BaseUrl,
+	}
+}
+
+type Client struct {
+	apiKey    string
+	transport http.RoundTripper
+	url       *url.URL
+}
+
+type request struct {
+	ApiKey           string      `json:"apiKey"`
+	ModelName        string      `json:"modelName"`
+	CalledMethod     string      `json:"calledMethod"`
+	MethodProperties interface{} `json:"methodProperties"`
+}
+
+type response struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Errors  []string        `json:"errors"`
+}
+
+// call sends the method of the model and decodes response data into result
+func (c *Client) call(ctx context.Context, model, method string, properties, result interface{}) error {
+
+	var buf bytes.Buffer
+
+	err := json.NewEncoder(&buf).Encode(request{
+		ApiKey:           c.apiKey,
+		ModelName:        model,
+		CalledMethod:     method,
+		MethodProperties: properties,
+	})
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("novaposhta failed encode body for %s.%s %v", model, method, err))
+	}
+
+	u := *c.url
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + model + "/" + method
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), &buf)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.transport.RoundTrip(req)
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("novaposhta failed %s.%s request %v", model, method, err))
+	}
+
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("novaposhta failed read %s.%s response %v", model, method, err))
+	}
+
+	if res.StatusCode > 299 {
+		return errors.New(fmt.Sprintf("novaposhta %s.%s response status %d %s", model, method, res.StatusCode, string(b)))
+	}
+
+	var r response
+
+	if err := json.Unmarshal(b, &r); err != nil {
+		return errors.New(fmt.Sprintf("novaposhta failed decode %s.%s response %v", model, method, err))
+	}
+
+	if r.Success == false {
+		return errors.New(fmt.Sprintf("novaposhta %s.%s errors %v", model, method, r.Errors))
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(r.Data, result)
+}
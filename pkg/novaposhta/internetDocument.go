@@ -0,0 +1,152 @@
+package novaposhta
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const modelInternetDocument = "InternetDocument"
+
+const PayerTypeSender = "Sender"
+const PayerTypeRecipient = "Recipient"
+
+const PaymentMethodCash = "Cash"
+const PaymentMethodNonCash = "NonCash"
+
+const CargoTypeParcel = "Parcel"
+const CargoTypeMoney = "Money"
+
+const ServiceTypeWarehouseWarehouse = "WarehouseWarehouse"
+
+const RecipientTypePrivatePerson = "PrivatePerson"
+
+// DateLayout is the date format of api, e.g. 31.12.2020
+const DateLayout = "02.01.2006"
+
+// deliveryDateLayout is the format of estimated delivery date, e.g. 2020-12-31 00:00:00.000000
+const deliveryDateLayout = "2006-01-02 15:04:05.000000"
+
+// InternetDocument is the waybill, recipient is set by names and refs without creating counterparty first
+type InternetDocument struct {
+	PayerType     string `json:"PayerType"`
+	PaymentMethod string `json:"PaymentMethod"`
+	DateTime      string `json:"DateTime"`
+	CargoType     string `json:"CargoType"`
+	ServiceType   string `json:"ServiceType"`
+	// Weight in kilograms
+	Weight      float64 `json:"Weight"`
+	SeatsAmount int     `json:"SeatsAmount"`
+	Description string  `json:"Description"`
+	// Cost is declared value in hryvnias
+	Cost float64 `json:"Cost"`
+
+	CitySender    string `json:"CitySender"`
+	Sender        string `json:"Sender"`
+	SenderAddress string `json:"SenderAddress"`
+	ContactSender string `json:"ContactSender"`
+	SendersPhone  string `json:"SendersPhone"`
+
+	CityRecipient    string `json:"CityRecipient"`
+	RecipientAddress string `json:"RecipientAddress"`
+	RecipientName    string `json:"RecipientName"`
+	RecipientType    string `json:"RecipientType"`
+	RecipientsPhone  string `json:"RecipientsPhone"`
+
+	BackwardDeliveryData []BackwardDelivery `json:"BackwardDeliveryData,omitempty"`
+}
+
+// BackwardDelivery is cash on delivery the carrier returns to sender
+type BackwardDelivery struct {
+	PayerType        string `json:"PayerType"`
+	CargoType        string `json:"CargoType"`
+	RedeliveryString string `json:"RedeliveryString"`
+}
+
+type CreatedDocument struct {
+	Ref                   string  `json:"Ref"`
+	IntDocNumber          string  `json:"IntDocNumber"`
+	CostOnSite            float64 `json:"CostOnSite"`
+	EstimatedDeliveryDate string  `json:"EstimatedDeliveryDate"`
+}
+
+// CreateInternetDocument saves the waybill, IntDocNumber of the result is the tracking number
+func (c *Client) CreateInternetDocument(ctx context.Context, doc InternetDocument) (*CreatedDocument, error) {
+
+	var created []CreatedDocument
+
+	if err := c.call(ctx, modelInternetDocument, "save", doc, &created); err != nil {
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, errors.New("novaposhta internet document wasn't created")
+	}
+
+	return &created[0], nil
+}
+
+// DocumentPriceRequest is the shipment to calculate delivery cost of, refs are the cities ones
+type DocumentPriceRequest struct {
+	CitySender    string `json:"CitySender"`
+	CityRecipient string `json:"CityRecipient"`
+	ServiceType   string `json:"ServiceType"`
+	CargoType     string `json:"CargoType"`
+	// Weight in kilograms
+	Weight      float64 `json:"Weight"`
+	SeatsAmount int     `json:"SeatsAmount"`
+	// Cost is declared value in hryvnias
+	Cost float64 `json:"Cost"`
+}
+
+type DocumentPrice struct {
+	// Cost is delivery cost in hryvnias
+	Cost         float64 `json:"Cost"`
+	AssessedCost float64 `json:"AssessedCost"`
+}
+
+// GetDocumentPrice calculates delivery cost by carrier tariffs
+func (c *Client) GetDocumentPrice(ctx context.Context, req DocumentPriceRequest) (*DocumentPrice, error) {
+
+	var prices []DocumentPrice
+
+	if err := c.call(ctx, modelInternetDocument, "getDocumentPrice", req, &prices); err != nil {
+		return nil, err
+	}
+
+	if len(prices) == 0 {
+		return nil, errors.New("novaposhta document price wasn't calculated")
+	}
+
+	return &prices[0], nil
+}
+
+// DocumentDeliveryDateRequest is the shipment to estimate delivery date of, DateTime is the date of sending in DateLayout
+type DocumentDeliveryDateRequest struct {
+	DateTime      string `json:"DateTime"`
+	ServiceType   string `json:"ServiceType"`
+	CitySender    string `json:"CitySender"`
+	CityRecipient string `json:"CityRecipient"`
+}
+
+type documentDeliveryDate struct {
+	DeliveryDate struct {
+		Date string `json:"date"`
+	} `json:"DeliveryDate"`
+}
+
+// GetDocumentDeliveryDate estimates the date the shipment arrives to the recipient city
+func (c *Client) GetDocumentDeliveryDate(ctx context.Context, req DocumentDeliveryDateRequest) (time.Time, error) {
+
+	var dates []documentDeliveryDate
+
+	if err := c.call(ctx, modelInternetDocument, "getDocumentDeliveryDate", req, &dates); err != nil {
+		return time.Time{}, err
+	}
+
+	if len(dates) == 0 {
+		return time.Time{}, errors.New("novaposhta delivery date wasn't estimated")
+	}
+
+	return time.ParseInLocation(deliveryDateLayout, dates[0].DeliveryDate.Date, time.Local)
+}
@@ -0,0 +1,36 @@
+package novaposhta
+
+import "context"
+
+const modelTrackingDocument = "TrackingDocument"
+
+// TrackingLimit is the most documents api returns statuses for in one request
+const TrackingLimit = 100
+
+// TrackingDocument is the waybill to track, recipient or sender phone adds personal details to the status
+type TrackingDocument struct {
+	DocumentNumber string `json:"DocumentNumber"`
+	Phone          string `json:"Phone"`
+}
+
+type DocumentStatus struct {
+	Number     string `json:"Number"`
+	StatusCode string `json:"StatusCode"`
+	Status     string `json:"Status"`
+}
+
+// GetStatusDocuments returns statuses of at most TrackingLimit documents
+func (c *Client) GetStatusDocuments(ctx context.Context, documents []TrackingDocument) ([]DocumentStatus, error) {
+
+	var statuses []DocumentStatus
+
+	properties := map[string]interface{}{
+		"Documents": documents,
+	}
+
+	if err := c.call(ctx, modelTrackingDocument, "getStatusDocuments", properties, &statuses); err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
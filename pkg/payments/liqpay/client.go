@@ -10,22 +10,27 @@ import (
 )
 
 const (
-	path        = "request"
-	version     = "3"
-	currencyUah = "UAH"
+	path    = "request"
+	version = "3"
 
 	actionHold       = "hold"
 	actionAcceptHold = "hold_completion"
 	actionRefund     = "refund"
+	actionStatus     = "status"
 
 	StatusHoldWait = "hold_wait"
 
-	StatusError   = "error"
-	StatusSuccess = "success"
-	StatusFail    = "failure"
+	StatusError    = "error"
+	StatusSuccess  = "success"
+	StatusFail     = "failure"
 	StatusReversed = "reversed"
+
+	ErrCodePaymentNotFound = "payment_not_found"
 )
 
+// Currencies are ISO codes of currencies payments are accepted in
+var Currencies = []string{"UAH", "USD", "EUR"}
+
 type CallbackResponse struct {
 	Status string
 	Desc   string
@@ -51,7 +56,8 @@ type Client struct {
 	publicKey string
 }
 
-func (c *Client) Hold(orderId, amount, description string) (string, error) {
+// Hold renders checkout form, currency is ISO 4217 code, e.g. UAH
+func (c *Client) Hold(orderId, amount, currency, description string) (string, error) {
 
 	r := _liqpay.Request{
 		"public_key":  c.publicKey,
@@ -60,7 +66,7 @@ func (c *Client) Hold(orderId, amount, description string) (string, error) {
 		"description": description,
 		"action":      actionHold,
 		"version":     version,
-		"currency":    currencyUah,
+		"currency":    currency,
 		"result_url":  c.returnUrl,
 		"server_url":  c.callbackUrl,
 	}
@@ -91,8 +97,44 @@ func (c Client) RenderForm(req _liqpay.Request) (string, error) {
 
 func (c *Client) AcceptHolden(orderId, amount string) (map[string]interface{}, error) {
 
+	return c.sendWithAmount("p2p accept holden", actionAcceptHold, orderId, amount)
+}
+
+// Status asks liqpay for the current state of the payment
+func (c *Client) Status(orderId string) (map[string]interface{}, error) {
+
+	r := _liqpay.Request{
+		"action":   actionStatus,
+		"version":  version,
+		"order_id": orderId,
+	}
+
+	response, err := c.api.Send(path, r)
+
+	if err != nil {
+		log.Printf("[p2p status][liqpay request error][%s][%v][%v]", orderId, r, response)
+	}
+
+	return response, err
+}
+
+// CancelHolden releases the holden amount, liqpay makes it by refund action on the payment in hold_wait status
+func (c *Client) CancelHolden(orderId, amount string) (map[string]interface{}, error) {
+
+	return c.sendWithAmount("p2p cancel holden", actionRefund, orderId, amount)
+}
+
+// Refund returns amount of the payment to the customer, amount may be less than the payment amount
+func (c *Client) Refund(orderId, amount string) (map[string]interface{}, error) {
+
+	return c.sendWithAmount("p2p refund", actionRefund, orderId, amount)
+}
+
+// sendWithAmount signs and sends the action on the payment, the response is decoded by sdk
+func (c *Client) sendWithAmount(tag, action, orderId, amount string) (map[string]interface{}, error) {
+
 	r := _liqpay.Request{
-		"action":   actionAcceptHold,
+		"action":   action,
 		"version":  version,
 		"order_id": orderId,
 		"amount":   amount,
@@ -101,9 +143,9 @@ func (c *Client) AcceptHolden(orderId, amount string) (map[string]interface{}, e
 	response, err := c.api.Send(path, r)
 
 	if err != nil {
-		log.Printf("[p2p accept holden][liqpay request error][%s][%v][%v]", orderId, r, response)
+		log.Printf("[%s][liqpay request error][%s][%v][%v]", tag, orderId, r, response)
 	} else {
-		log.Printf("[p2p accept holden][liqpay request][%s][%v][%v]", orderId, r, response)
+		log.Printf("[%s][liqpay request][%s][%v][%v]", tag, orderId, r, response)
 	}
 
 	return response, err
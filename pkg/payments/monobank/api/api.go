@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+)
+
+const (
+	StatusCreated    = "created"    //Рахунок створено успішно, очікується оплата
+	StatusProcessing = "processing" //Платіж обробляється
+	StatusHold       = "hold"       //Сума заблокована
+	StatusSuccess    = "success"    //Успішна оплата
+	StatusFailure    = "failure"    //Неуспішна оплата
+	StatusReversed   = "reversed"   //Оплата повернена після успіху
+	StatusExpired    = "expired"    //Час дії вичерпано
+)
+
+const PaymentTypeDebit = "debit"
+const PaymentTypeHold = "hold"
+
+const CurrencyUAH = 980
+const CurrencyUSD = 840
+const CurrencyEUR = 978
+
+// CurrencyCode returns ISO 4217 numeric code monobank expects in ccy
+func CurrencyCode(iso string) (int, bool) {
+	codes := map[string]int{
+		"UAH": CurrencyUAH,
+		"USD": CurrencyUSD,
+		"EUR": CurrencyEUR,
+	}
+
+	c, ok := codes[iso]
+
+	return c, ok
+}
+
+type Transport interface {
+	Perform(*http.Request) (*http.Response, error)
+}
+
+func NewConfig(webHookUrl, redirectUrl, publicKey string) Config {
+	return Config{
+		webHookUrl:  webHookUrl,
+		redirectUrl: redirectUrl,
+		publicKey:   publicKey,
+	}
+}
+
+type Config struct {
+	webHookUrl  string
+	redirectUrl string
+	publicKey   string
+}
+
+func New(t Transport, cfg Config) *API {
+
+	pubKey := newPubKeyFunc(t)
+
+	return &API{
+		Invoice: &Invoice{
+			Create:   newInvoiceCreateFunc(t, cfg),
+			Finalize: newInvoiceFinalizeFunc(t),
+			Cancel:   newInvoiceCancelFunc(t),
+			Status:   newInvoiceStatusFunc(t),
+		},
+		PubKey: pubKey,
+		Sign: &Sign{
+			WebhookCheck: newSignWebhookCheckFunc(newKeyStore(cfg.publicKey, pubKey)),
+		},
+	}
+}
+
+type API struct {
+	Invoice *Invoice
+	PubKey  PubKey
+	Sign    *Sign
+}
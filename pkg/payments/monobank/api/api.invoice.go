@@ -0,0 +1,19 @@
+package api
+
+type Invoice struct {
+	Create   InvoiceCreate
+	Finalize InvoiceFinalize
+	Cancel   InvoiceCancel
+	Status   InvoiceStatus
+}
+
+func NewBasketItem(name string, qty int, sum int) BasketItem {
+	return BasketItem{Name: name, Qty: qty, Sum: sum}
+}
+
+// BasketItem sum is the price of one item in kopiyky
+type BasketItem struct {
+	Name string `json:"name"`
+	Qty  int    `json:"qty"`
+	Sum  int    `json:"sum"`
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const invoiceCancelUri = "/api/merchant/invoice/cancel"
+
+type InvoiceCancel func(r ...func(*InvoiceCancelRequest)) (*Response, error)
+
+type InvoiceCancelRequest struct {
+	invoiceId string
+	amount    int
+
+	ctx context.Context
+}
+
+func newInvoiceCancelFunc(t Transport) InvoiceCancel {
+
+	return func(o ...func(*InvoiceCancelRequest)) (*Response, error) {
+		var r InvoiceCancelRequest
+
+		for _, f := range o {
+			f(&r)
+		}
+
+		return r.Do(t)
+	}
+}
+
+func (r InvoiceCancel) WithContext(ctx context.Context) func(request *InvoiceCancelRequest) {
+
+	return func(r *InvoiceCancelRequest) {
+		r.ctx = ctx
+	}
+}
+
+// WithParams amount in kopiyky, zero cancels the whole invoice amount
+func (r InvoiceCancel) WithParams(invoiceId string, amount int) func(*InvoiceCancelRequest) {
+
+	return func(r *InvoiceCancelRequest) {
+		r.invoiceId = invoiceId
+		r.amount = amount
+	}
+}
+
+func (r InvoiceCancelRequest) Do(t Transport) (*Response, error) {
+	var (
+		buf    bytes.Buffer
+		method string
+		params map[string]interface{}
+	)
+
+	method = "POST"
+
+	params = map[string]interface{}{
+		"invoiceId": r.invoiceId,
+	}
+
+	if r.amount > 0 {
+		params["amount"] = r.amount
+	}
+
+	if err := json.NewEncoder(&buf).Encode(params); err != nil {
+		return nil, errors.New(fmt.Sprintf("monobank failed encode body for request %v", err))
+	}
+
+	req, _ := newRequest(method, invoiceCancelUri, &buf)
+
+	if r.ctx != nil {
+		req = req.WithContext(r.ctx)
+	}
+
+	res, err := t.Perform(req)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("monobank failed invoice cancel request %v", err))
+	}
+
+	response := Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+
+	return &response, nil
+}
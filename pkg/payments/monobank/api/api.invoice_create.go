@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const invoiceCreateUri = "/api/merchant/invoice/create"
+
+type InvoiceCreate func(r ...func(*InvoiceCreateRequest)) (*Response, error)
+
+type InvoiceCreateRequest struct {
+	webHookUrl  string
+	redirectUrl string
+
+	reference   string
+	amount      int
+	ccy         int
+	destination string
+	paymentType string
+	basket      []BasketItem
+
+	ctx context.Context
+}
+
+func newInvoiceCreateFunc(t Transport, cfg Config) InvoiceCreate {
+
+	return func(o ...func(*InvoiceCreateRequest)) (*Response, error) {
+		var r = InvoiceCreateRequest{
+			webHookUrl:  cfg.webHookUrl,
+			redirectUrl: cfg.redirectUrl,
+			paymentType: PaymentTypeDebit,
+			ccy:         CurrencyUAH,
+		}
+
+		for _, f := range o {
+			f(&r)
+		}
+
+		return r.Do(t)
+	}
+}
+
+func (r InvoiceCreate) WithContext(ctx context.Context) func(request *InvoiceCreateRequest) {
+
+	return func(r *InvoiceCreateRequest) {
+		r.ctx = ctx
+	}
+}
+
+// WithParams reference is the merchant payment id returned back in webhook, amount is in kopiyky
+func (r InvoiceCreate) WithParams(reference string, amount int, destination string, basket []BasketItem) func(*InvoiceCreateRequest) {
+
+	return func(r *InvoiceCreateRequest) {
+		r.reference = reference
+		r.amount = amount
+		r.destination = destination
+		r.basket = basket
+	}
+}
+
+// WithCurrency sets numeric code of the invoice currency, UAH is used by default
+func (r InvoiceCreate) WithCurrency(ccy int) func(*InvoiceCreateRequest) {
+
+	return func(r *InvoiceCreateRequest) {
+		r.ccy = ccy
+	}
+}
+
+// WithHold creates invoice which amount is blocked till finalize or cancel
+func (r InvoiceCreate) WithHold() func(*InvoiceCreateRequest) {
+
+	return func(r *InvoiceCreateRequest) {
+		r.paymentType = PaymentTypeHold
+	}
+}
+
+func (r InvoiceCreateRequest) Do(t Transport) (*Response, error) {
+	var (
+		buf    bytes.Buffer
+		method string
+		params map[string]interface{}
+	)
+
+	method = "POST"
+
+	params = map[string]interface{}{
+		"amount":      r.amount,
+		"ccy":         r.ccy,
+		"paymentType": r.paymentType,
+		"redirectUrl": r.redirectUrl,
+		"webHookUrl":  r.webHookUrl,
+		"merchantPaymInfo": map[string]interface{}{
+			"reference":   r.reference,
+			"destination": r.destination,
+			"basketOrder": r.basket,
+		},
+	}
+
+	if err := json.NewEncoder(&buf).Encode(params); err != nil {
+		return nil, errors.New(fmt.Sprintf("monobank failed encode body for request %v", err))
+	}
+
+	req, _ := newRequest(method, invoiceCreateUri, &buf)
+
+	if r.ctx != nil {
+		req = req.WithContext(r.ctx)
+	}
+
+	res, err := t.Perform(req)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("monobank failed invoice create request %v", err))
+	}
+
+	response := Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const invoiceFinalizeUri = "/api/merchant/invoice/finalize"
+
+type InvoiceFinalize func(r ...func(*InvoiceFinalizeRequest)) (*Response, error)
+
+type InvoiceFinalizeRequest struct {
+	invoiceId string
+	amount    int
+
+	ctx context.Context
+}
+
+func newInvoiceFinalizeFunc(t Transport) InvoiceFinalize {
+
+	return func(o ...func(*InvoiceFinalizeRequest)) (*Response, error) {
+		var r InvoiceFinalizeRequest
+
+		for _, f := range o {
+			f(&r)
+		}
+
+		return r.Do(t)
+	}
+}
+
+func (r InvoiceFinalize) WithContext(ctx context.Context) func(request *InvoiceFinalizeRequest) {
+
+	return func(r *InvoiceFinalizeRequest) {
+		r.ctx = ctx
+	}
+}
+
+// WithParams amount in kopiyky, zero finalizes the whole holden amount
+func (r InvoiceFinalize) WithParams(invoiceId string, amount int) func(*InvoiceFinalizeRequest) {
+
+	return func(r *InvoiceFinalizeRequest) {
+		r.invoiceId = invoiceId
+		r.amount = amount
+	}
+}
+
+func (r InvoiceFinalizeRequest) Do(t Transport) (*Response, error) {
+	var (
+		buf    bytes.Buffer
+		method string
+		params map[string]interface{}
+	)
+
+	method = "POST"
+
+	params = map[string]interface{}{
+		"invoiceId": r.invoiceId,
+	}
+
+	if r.amount > 0 {
+		params["amount"] = r.amount
+	}
+
+	if err := json.NewEncoder(&buf).Encode(params); err != nil {
+		return nil, errors.New(fmt.Sprintf("monobank failed encode body for request %v", err))
+	}
+
+	req, _ := newRequest(method, invoiceFinalizeUri, &buf)
+
+	if r.ctx != nil {
+		req = req.WithContext(r.ctx)
+	}
+
+	res, err := t.Perform(req)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("monobank failed invoice finalize request %v", err))
+	}
+
+	response := Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const invoiceStatusUri = "/api/merchant/invoice/status"
+
+type InvoiceStatus func(r ...func(*InvoiceStatusRequest)) (*Response, error)
+
+type InvoiceStatusRequest struct {
+	invoiceId string
+
+	ctx context.Context
+}
+
+func newInvoiceStatusFunc(t Transport) InvoiceStatus {
+
+	return func(o ...func(*InvoiceStatusRequest)) (*Response, error) {
+		var r InvoiceStatusRequest
+
+		for _, f := range o {
+			f(&r)
+		}
+
+		return r.Do(t)
+	}
+}
+
+func (r InvoiceStatus) WithContext(ctx context.Context) func(request *InvoiceStatusRequest) {
+
+	return func(r *InvoiceStatusRequest) {
+		r.ctx = ctx
+	}
+}
+
+func (r InvoiceStatus) WithParams(invoiceId string) func(*InvoiceStatusRequest) {
+
+	return func(r *InvoiceStatusRequest) {
+		r.invoiceId = invoiceId
+	}
+}
+
+func (r InvoiceStatusRequest) Do(t Transport) (*Response, error) {
+
+	req, _ := newRequest("GET", invoiceStatusUri, nil)
+
+	q := req.URL.Query()
+	q.Set("invoiceId", r.invoiceId)
+	req.URL.RawQuery = q.Encode()
+
+	if r.ctx != nil {
+		req = req.WithContext(r.ctx)
+	}
+
+	res, err := t.Perform(req)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("monobank failed invoice status request %v", err))
+	}
+
+	response := Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+
+	return &response, nil
+}
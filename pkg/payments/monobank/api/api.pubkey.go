@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const pubKeyUri = "/api/merchant/pubkey"
+
+// PubKey requests the key used to verify webhook signature
+type PubKey func(r ...func(*PubKeyRequest)) (*Response, error)
+
+type PubKeyRequest struct {
+	ctx context.Context
+}
+
+func newPubKeyFunc(t Transport) PubKey {
+
+	return func(o ...func(*PubKeyRequest)) (*Response, error) {
+		var r PubKeyRequest
+
+		for _, f := range o {
+			f(&r)
+		}
+
+		return r.Do(t)
+	}
+}
+
+func (r PubKey) WithContext(ctx context.Context) func(request *PubKeyRequest) {
+
+	return func(r *PubKeyRequest) {
+		r.ctx = ctx
+	}
+}
+
+func (r PubKeyRequest) Do(t Transport) (*Response, error) {
+
+	req, _ := newRequest("GET", pubKeyUri, nil)
+
+	if r.ctx != nil {
+		req = req.WithContext(r.ctx)
+	}
+
+	res, err := t.Perform(req)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("monobank failed pubkey request %v", err))
+	}
+
+	response := Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+
+	return &response, nil
+}
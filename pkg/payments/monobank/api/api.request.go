@@ -0,0 +1,71 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type Request interface {
+	Do(t Transport) (*Response, error)
+}
+
+func newRequest(method, uri string, body io.Reader) (*http.Request, error) {
+
+	r := http.Request{
+		Method:     method,
+		URL:        &url.URL{Path: uri},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+	}
+
+	if body != nil {
+		switch b := body.(type) {
+		case *bytes.Buffer:
+			r.Body = ioutil.NopCloser(body)
+			r.ContentLength = int64(b.Len())
+		case *bytes.Reader:
+			r.Body = ioutil.NopCloser(body)
+			r.ContentLength = int64(b.Len())
+		case *strings.Reader:
+			r.Body = ioutil.NopCloser(body)
+			r.ContentLength = int64(b.Len())
+		default:
+			r.Body = ioutil.NopCloser(body)
+		}
+	}
+
+	return &r, nil
+}
+
+// Callback is the webhook body, monobank sends it on every invoice status change
+type Callback struct {
+	InvoiceId     string `json:"invoiceId"`
+	Status        string `json:"status"`
+	FailureReason string `json:"failureReason"`
+	Amount        int    `json:"amount"`
+	Ccy           int    `json:"ccy"`
+	FinalAmount   int    `json:"finalAmount"`
+	CreatedDate   string `json:"createdDate"`
+	ModifiedDate  string `json:"modifiedDate"`
+	Reference     string `json:"reference"`
+}
+
+func (r Callback) Stack() map[string]interface{} {
+	return map[string]interface{}{
+		"invoiceId":     r.InvoiceId,
+		"status":        r.Status,
+		"failureReason": r.FailureReason,
+		"amount":        r.Amount,
+		"ccy":           r.Ccy,
+		"finalAmount":   r.FinalAmount,
+		"createdDate":   r.CreatedDate,
+		"modifiedDate":  r.ModifiedDate,
+		"reference":     r.Reference,
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"io"
+	"net/http"
+)
+
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       io.ReadCloser
+}
+
+// ErrorResponse is returned by api with non 200 status code
+type ErrorResponse struct {
+	ErrCode string `json:"errCode"`
+	ErrText string `json:"errText"`
+}
+
+func (r ErrorResponse) Stack() map[string]interface{} {
+	return map[string]interface{}{
+		"errCode": r.ErrCode,
+		"errText": r.ErrText,
+	}
+}
+
+type CreateInvoiceResponse struct {
+	InvoiceId string `json:"invoiceId"`
+	PageUrl   string `json:"pageUrl"`
+}
+
+func (r CreateInvoiceResponse) Stack() map[string]interface{} {
+	return map[string]interface{}{
+		"invoiceId": r.InvoiceId,
+		"pageUrl":   r.PageUrl,
+	}
+}
+
+// FinalizeInvoiceResponse and CancelInvoiceResponse status is one of success, processing or failure
+type FinalizeInvoiceResponse struct {
+	Status string `json:"status"`
+}
+
+func (r FinalizeInvoiceResponse) Stack() map[string]interface{} {
+	return map[string]interface{}{
+		"status": r.Status,
+	}
+}
+
+type CancelInvoiceResponse struct {
+	Status       string `json:"status"`
+	CreatedDate  string `json:"createdDate"`
+	ModifiedDate string `json:"modifiedDate"`
+}
+
+func (r CancelInvoiceResponse) Stack() map[string]interface{} {
+	return map[string]interface{}{
+		"status":       r.Status,
+		"createdDate":  r.CreatedDate,
+		"modifiedDate": r.ModifiedDate,
+	}
+}
+
+// InvoiceStatusResponse has the same fields as webhook body
+type InvoiceStatusResponse = Callback
+
+type PubKeyResponse struct {
+	Key string `json:"key"`
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// keyRefreshInterval limits requests of the fresh key, every webhook with a bad signature asks for it otherwise
+const keyRefreshInterval = 5 * time.Minute
+
+type Sign struct {
+	WebhookCheck SignWebhookCheck
+}
+
+// SignWebhookCheck verifies X-Sign header of webhook, the header is base64 ECDSA signature of sha256 of body
+type SignWebhookCheck func(r ...func(*SignWebhookCheckRequest)) bool
+
+type SignWebhookCheckRequest struct {
+	body []byte
+	sign string
+
+	ctx context.Context
+}
+
+func newSignWebhookCheckFunc(keys *keyStore) SignWebhookCheck {
+
+	return func(o ...func(*SignWebhookCheckRequest)) bool {
+
+		var r SignWebhookCheckRequest
+
+		for _, f := range o {
+			f(&r)
+		}
+
+		sign, err := base64.StdEncoding.DecodeString(r.sign)
+
+		if err != nil || len(sign) == 0 {
+			return false
+		}
+
+		key, err := keys.get(r.ctx, false)
+
+		if err != nil {
+			return false
+		}
+
+		if verifySignature(key, r.body, sign) {
+			return true
+		}
+
+		// monobank could rotate the key, so the check is repeated once with the fresh one
+		key, err = keys.get(r.ctx, true)
+
+		if err != nil {
+			return false
+		}
+
+		return verifySignature(key, r.body, sign)
+	}
+}
+
+func (r SignWebhookCheck) WithContext(ctx context.Context) func(request *SignWebhookCheckRequest) {
+
+	return func(r *SignWebhookCheckRequest) {
+		r.ctx = ctx
+	}
+}
+
+// WithParams body is the raw webhook body, sign is the value of X-Sign header
+func (r SignWebhookCheck) WithParams(body []byte, sign string) func(*SignWebhookCheckRequest) {
+
+	return func(r *SignWebhookCheckRequest) {
+		r.body = body
+		r.sign = sign
+	}
+}
+
+func verifySignature(key *ecdsa.PublicKey, body, sign []byte) bool {
+
+	var rs struct {
+		R, S *big.Int
+	}
+
+	if _, err := asn1.Unmarshal(sign, &rs); err != nil {
+		return false
+	}
+
+	hash := sha256.Sum256(body)
+
+	return ecdsa.Verify(key, hash[:], rs.R, rs.S)
+}
+
+func newKeyStore(encoded string, pubKey PubKey) *keyStore {
+
+	return &keyStore{encoded: encoded, pubKey: pubKey}
+}
+
+// keyStore keeps parsed webhook key, the key is requested from api when it isn't configured
+type keyStore struct {
+	sync.Mutex
+
+	encoded   string
+	key       *ecdsa.PublicKey
+	pubKey    PubKey
+	refreshed time.Time
+}
+
+func (s *keyStore) get(ctx context.Context, refresh bool) (*ecdsa.PublicKey, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.key != nil && (refresh == false || time.Since(s.refreshed) < keyRefreshInterval) {
+		return s.key, nil
+	}
+
+	if s.encoded == "" || refresh == true {
+		// failed attempts count as well, so unavailable api isn't requested on every webhook
+		s.refreshed = time.Now()
+
+		encoded, err := s.request(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		s.encoded = encoded
+	}
+
+	key, err := parsePublicKey(s.encoded)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.key = key
+
+	return key, nil
+}
+
+func (s *keyStore) request(ctx context.Context) (string, error) {
+
+	opts := []func(*PubKeyRequest){}
+
+	if ctx != nil {
+		opts = append(opts, s.pubKey.WithContext(ctx))
+	}
+
+	res, err := s.pubKey(opts...)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", errors.New(fmt.Sprintf("monobank pubkey response status %d", res.StatusCode))
+	}
+
+	var result PubKeyResponse
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return "", errors.New(fmt.Sprintf("monobank failed decode pubkey response %v", err))
+	}
+
+	return result.Key, nil
+}
+
+func parsePublicKey(encoded string) (*ecdsa.PublicKey, error) {
+
+	b, err := base64.StdEncoding.DecodeString(encoded)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("monobank failed decode pubkey %v", err))
+	}
+
+	block, _ := pem.Decode(b)
+
+	if block == nil {
+		return nil, errors.New("monobank pubkey is not pem")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("monobank failed parse pubkey %v", err))
+	}
+
+	ecKey, ok := key.(*ecdsa.PublicKey)
+
+	if ok == false {
+		return nil, errors.New("monobank pubkey is not ecdsa")
+	}
+
+	return ecKey, nil
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeKey(t *testing.T, key *ecdsa.PrivateKey) string {
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func signBody(t *testing.T, key *ecdsa.PrivateKey, body []byte) string {
+
+	hash := sha256.Sum256(body)
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	assert.NoError(t, err)
+
+	sign, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	assert.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(sign)
+}
+
+func TestSignWebhookCheck(t *testing.T) {
+
+	old, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rotated, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	requests := 0
+	pubKey := PubKey(func(r ...func(*PubKeyRequest)) (*Response, error) {
+		requests++
+		body := fmt.Sprintf(`{"key":"%s"}`, encodeKey(t, rotated))
+		return &Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})
+
+	keys := newKeyStore(encodeKey(t, old), pubKey)
+	check := newSignWebhookCheckFunc(keys)
+	body := []byte(`{"invoiceId":"1"}`)
+
+	assert.True(t, check(check.WithContext(context.Background()), check.WithParams(body, signBody(t, old, body))), "t1")
+	assert.Equal(t, 0, requests, "t2")
+
+	// the key is rotated, the fresh one is requested once
+	assert.True(t, check(check.WithContext(context.Background()), check.WithParams(body, signBody(t, rotated, body))), "t3")
+	assert.Equal(t, 1, requests, "t4")
+
+	// bad signatures don't request the key again until the refresh interval passes
+	assert.False(t, check(check.WithContext(context.Background()), check.WithParams(body, signBody(t, old, body))), "t5")
+	assert.False(t, check(check.WithContext(context.Background()), check.WithParams(body, signBody(t, old, body))), "t6")
+	assert.Equal(t, 1, requests, "t7")
+
+	keys.refreshed = time.Now().Add(-keyRefreshInterval)
+
+	assert.False(t, check(check.WithContext(context.Background()), check.WithParams(body, signBody(t, old, body))), "t8")
+	assert.Equal(t, 2, requests, "t9")
+}
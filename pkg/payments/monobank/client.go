@@ -0,0 +1,38 @@
+package monobank
+
+import (
+	"github.com/wowucco/G3/pkg/payments/monobank/api"
+	"github.com/wowucco/G3/pkg/payments/monobank/transport"
+	"net/http"
+	"net/url"
+)
+
+type Config struct {
+	Token       string
+	WebHookUrl  string
+	RedirectUrl string
+	// PublicKey is base64 encoded PEM key of webhook signature, it is requested from api when empty
+	PublicKey string
+
+	Transport http.RoundTripper
+	BaseUrl   *url.URL
+}
+
+func NewClient(cfg Config) *Client {
+
+	tcfg := transport.Config{
+		Token:     cfg.Token,
+		Transport: cfg.Transport,
+		Url:       cfg.BaseUrl,
+	}
+
+	acfg := api.NewConfig(cfg.WebHookUrl, cfg.RedirectUrl, cfg.PublicKey)
+
+	return &Client{
+		API: api.New(transport.New(tcfg), acfg),
+	}
+}
+
+type Client struct {
+	*api.API
+}
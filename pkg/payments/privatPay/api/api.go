@@ -40,6 +40,9 @@ func New(t Transport, cfg Config) *API {
 		Pay: &Pay{
 			Hold:   newPaymentHoldFunc(t, cfg),
 			Accept: newAcceptHoldenFunc(t, cfg),
+			Refund: newPaymentRefundFunc(t, cfg),
+			Cancel: newCancelHoldenFunc(t, cfg),
+			State:  newPaymentStateFunc(t, cfg),
 		},
 		Sign: &Sign{
 			CallbackCheck: newSignCallbackCheckFunc(cfg),
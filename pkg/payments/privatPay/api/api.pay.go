@@ -23,4 +23,7 @@ type Product struct {
 type Pay struct {
 	Hold   PaymentHold
 	Accept PaymentAcceptHolden
+	Refund PaymentRefund
+	Cancel PaymentCancelHolden
+	State  PaymentState
 }
\ No newline at end of file
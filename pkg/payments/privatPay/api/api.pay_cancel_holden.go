@@ -0,0 +1,100 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const cancelHoldenUri = "/ipp/v2/payment/cancel"
+
+type PaymentCancelHolden func(r ...func(*PaymentCancelHoldenRequest)) (*Response, error)
+
+type PaymentCancelHoldenRequest struct {
+	storeId  string
+	passport string
+
+	orderId string
+
+	signature string
+
+	ctx context.Context
+}
+
+func newCancelHoldenFunc(t Transport, cfg Config) PaymentCancelHolden {
+
+	return func(o ...func(*PaymentCancelHoldenRequest)) (*Response, error) {
+		var r = PaymentCancelHoldenRequest{
+			storeId:  cfg.storeId,
+			passport: cfg.passport,
+		}
+
+		for _, f := range o {
+			f(&r)
+		}
+
+		return r.Do(t)
+	}
+}
+
+func (r PaymentCancelHolden) WithContext(ctx context.Context) func(request *PaymentCancelHoldenRequest) {
+
+	return func(r *PaymentCancelHoldenRequest) {
+		r.ctx = ctx
+	}
+}
+
+func (r PaymentCancelHolden) WithParams(orderId string) func(*PaymentCancelHoldenRequest) {
+
+	return func(r *PaymentCancelHoldenRequest) {
+		r.orderId = orderId
+	}
+}
+
+func (r PaymentCancelHoldenRequest) Do(t Transport) (*Response, error) {
+	var (
+		buf    bytes.Buffer
+		method string
+		params map[string]interface{}
+	)
+
+	method = "POST"
+
+	params = map[string]interface{}{
+		"storeId": r.storeId,
+		"orderId": r.orderId,
+	}
+
+	params["signature"] = makeSignature(
+		[]byte(r.passport),
+		[]byte(r.storeId),
+		[]byte(r.orderId),
+		[]byte(r.passport),
+	)
+
+	if err := json.NewEncoder(&buf).Encode(params); err != nil {
+		return nil, errors.New(fmt.Sprintf("ppp failed encode body for request %v", err))
+	}
+
+	req, _ := newRequest(method, cancelHoldenUri, &buf)
+
+	if r.ctx != nil {
+		req = req.WithContext(r.ctx)
+	}
+
+	res, err := t.Perform(req)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("ppp failed cancel hold request %v", err))
+	}
+
+	response := Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const refundUri = "/ipp/v2/payment/decline"
+
+type PaymentRefund func(r ...func(*PaymentRefundRequest)) (*Response, error)
+
+type PaymentRefundRequest struct {
+	storeId  string
+	passport string
+
+	orderId string
+	amount  float64
+
+	signature string
+
+	ctx context.Context
+}
+
+func newPaymentRefundFunc(t Transport, cfg Config) PaymentRefund {
+
+	return func(o ...func(*PaymentRefundRequest)) (*Response, error) {
+		var r = PaymentRefundRequest{
+			storeId:  cfg.storeId,
+			passport: cfg.passport,
+		}
+
+		for _, f := range o {
+			f(&r)
+		}
+
+		return r.Do(t)
+	}
+}
+
+func (r PaymentRefund) WithContext(ctx context.Context) func(request *PaymentRefundRequest) {
+
+	return func(r *PaymentRefundRequest) {
+		r.ctx = ctx
+	}
+}
+
+// WithParams sets the refunded amount, it may be less than the payment amount for partial refund
+func (r PaymentRefund) WithParams(orderId string, amount float64) func(*PaymentRefundRequest) {
+
+	return func(r *PaymentRefundRequest) {
+		r.orderId = orderId
+		r.amount = amount
+	}
+}
+
+func (r PaymentRefundRequest) Do(t Transport) (*Response, error) {
+	var (
+		buf    bytes.Buffer
+		method string
+		params map[string]interface{}
+	)
+
+	method = "POST"
+
+	params = map[string]interface{}{
+		"storeId": r.storeId,
+		"orderId": r.orderId,
+		"amount":  r.amount,
+	}
+
+	params["signature"] = makeSignature(
+		[]byte(r.passport),
+		[]byte(r.storeId),
+		[]byte(r.orderId),
+		[]byte(strconv.Itoa(int(math.Round(r.amount*100)))),
+		[]byte(r.passport),
+	)
+
+	if err := json.NewEncoder(&buf).Encode(params); err != nil {
+		return nil, errors.New(fmt.Sprintf("ppp failed encode body for refund request %v", err))
+	}
+
+	req, _ := newRequest(method, refundUri, &buf)
+
+	if r.ctx != nil {
+		req = req.WithContext(r.ctx)
+	}
+
+	res, err := t.Perform(req)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("ppp failed refund request %v", err))
+	}
+
+	response := Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const stateUri = "/ipp/v2/payment/state"
+
+type PaymentState func(r ...func(*PaymentStateRequest)) (*Response, error)
+
+type PaymentStateRequest struct {
+	storeId  string
+	passport string
+
+	orderId string
+
+	signature string
+
+	ctx context.Context
+}
+
+func newPaymentStateFunc(t Transport, cfg Config) PaymentState {
+
+	return func(o ...func(*PaymentStateRequest)) (*Response, error) {
+		var r = PaymentStateRequest{
+			storeId:  cfg.storeId,
+			passport: cfg.passport,
+		}
+
+		for _, f := range o {
+			f(&r)
+		}
+
+		return r.Do(t)
+	}
+}
+
+func (r PaymentState) WithContext(ctx context.Context) func(request *PaymentStateRequest) {
+
+	return func(r *PaymentStateRequest) {
+		r.ctx = ctx
+	}
+}
+
+func (r PaymentState) WithParams(orderId string) func(*PaymentStateRequest) {
+
+	return func(r *PaymentStateRequest) {
+		r.orderId = orderId
+	}
+}
+
+func (r PaymentStateRequest) Do(t Transport) (*Response, error) {
+	var (
+		buf    bytes.Buffer
+		method string
+		params map[string]interface{}
+	)
+
+	method = "POST"
+
+	params = map[string]interface{}{
+		"storeId": r.storeId,
+		"orderId": r.orderId,
+	}
+
+	params["signature"] = makeSignature(
+		[]byte(r.passport),
+		[]byte(r.storeId),
+		[]byte(r.orderId),
+		[]byte(r.passport),
+	)
+
+	if err := json.NewEncoder(&buf).Encode(params); err != nil {
+		return nil, errors.New(fmt.Sprintf("ppp failed encode body for request %v", err))
+	}
+
+	req, _ := newRequest(method, stateUri, &buf)
+
+	if r.ctx != nil {
+		req = req.WithContext(r.ctx)
+	}
+
+	res, err := t.Perform(req)
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("ppp failed state request %v", err))
+	}
+
+	response := Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+
+	return &response, nil
+}
@@ -47,4 +47,61 @@ func (r AcceptHoldenPaymentResponse) Stack() map[string]interface{} {
 		"storeId":   r.StoreId,
 		"signature": r.Signature,
 	}
+}
+
+type RefundPaymentResponse struct {
+	StoreId   string `json:"storeId"`
+	State     string `json:"state"`
+	OrderId   string `json:"orderId"`
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+func (r RefundPaymentResponse) Stack() map[string]interface{} {
+	return map[string]interface{}{
+		"state":     r.State,
+		"orderId":   r.OrderId,
+		"message":   r.Message,
+		"storeId":   r.StoreId,
+		"signature": r.Signature,
+	}
+}
+
+type CancelHoldenPaymentResponse struct {
+	StoreId   string `json:"storeId"`
+	State     string `json:"state"`
+	OrderId   string `json:"orderId"`
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+func (r CancelHoldenPaymentResponse) Stack() map[string]interface{} {
+	return map[string]interface{}{
+		"state":     r.State,
+		"orderId":   r.OrderId,
+		"message":   r.Message,
+		"storeId":   r.StoreId,
+		"signature": r.Signature,
+	}
+}
+
+// PaymentStateResponse State is the state of the request, PaymentState is the state of the payment itself
+type PaymentStateResponse struct {
+	StoreId      string `json:"storeId"`
+	State        string `json:"state"`
+	OrderId      string `json:"orderId"`
+	PaymentState string `json:"paymentState"`
+	Message      string `json:"message"`
+	Signature    string `json:"signature"`
+}
+
+func (r PaymentStateResponse) Stack() map[string]interface{} {
+	return map[string]interface{}{
+		"state":        r.State,
+		"orderId":      r.OrderId,
+		"paymentState": r.PaymentState,
+		"message":      r.Message,
+		"storeId":      r.StoreId,
+		"signature":    r.Signature,
+	}
 }
\ No newline at end of file
@@ -0,0 +1,81 @@
+package privatPay
+
+import (
+	"errors"
+	"fmt"
+	"github.com/wowucco/G3/pkg/payments/privatPay/api"
+	"math"
+)
+
+// Commission is a monthly bank commission in percents of the purchase amount
+type Commission struct {
+	// PP is charged from the merchant, the customer pays the amount only
+	PP float64
+	// II is charged from the customer in addition to the amount
+	II float64
+}
+
+// Installment is a way to split the amount, all sums are in cents
+type Installment struct {
+	MerchantType string
+	Parts        int
+	// Payment is a monthly payment of the customer
+	Payment    int
+	Commission int
+	// Total is a sum paid by the customer
+	Total int
+}
+
+// Calculate returns installments for every parts count the amount can be split by
+func (c *Client) Calculate(amount int, merchantType string) ([]Installment, error) {
+
+	if amount <= 0 {
+		return nil, errors.New(fmt.Sprintf("[invalid amount %d]", amount))
+	}
+
+	var rate float64
+
+	switch merchantType {
+	case api.MerchantTypePP:
+		rate = c.commission.PP
+	case api.MerchantTypeII:
+		rate = c.commission.II
+	default:
+		return nil, errors.New(fmt.Sprintf("[unknown merchant type %s]", merchantType))
+	}
+
+	// parts range is the same the payment is initialized with, see config min_parts and max_parts
+	installments := make([]Installment, 0, c.max)
+
+	// unset min_parts isn't a parts count, the range starts with a single part then
+	first := c.min
+
+	if first < 1 {
+		first = 1
+	}
+
+	for parts := first; parts <= c.max; parts++ {
+		commission := int(math.Round(float64(amount) * rate / 100 * float64(parts)))
+
+		i := Installment{
+			MerchantType: merchantType,
+			Parts:        parts,
+			Commission:   commission,
+			Total:        amount,
+		}
+
+		if merchantType == api.MerchantTypeII {
+			i.Total += commission
+		}
+
+		i.Payment = int(math.Ceil(float64(i.Total) / float64(parts)))
+
+		if i.Payment < c.minPartAmount {
+			break
+		}
+
+		installments = append(installments, i)
+	}
+
+	return installments, nil
+}
@@ -0,0 +1,49 @@
+package privatPay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wowucco/G3/pkg/payments/privatPay/api"
+)
+
+func TestClient_Calculate(t *testing.T) {
+	c := NewClient(Config{
+		Min:           2,
+		Max:           4,
+		MinPartAmount: 30000,
+		Commission:    Commission{PP: 1.5, II: 2.9},
+	})
+
+	pp, err := c.Calculate(100000, api.MerchantTypePP)
+	assert.NoError(t, err, "t1")
+	assert.Len(t, pp, 2, "t2")
+	assert.Equal(t, Installment{api.MerchantTypePP, 2, 50000, 3000, 100000}, pp[0], "t3")
+	assert.Equal(t, 33334, pp[1].Payment, "t4")
+
+	ii, err := c.Calculate(100000, api.MerchantTypeII)
+	assert.NoError(t, err, "t5")
+	assert.Len(t, ii, 2, "t6")
+	assert.Equal(t, Installment{api.MerchantTypeII, 3, 36234, 8700, 108700}, ii[1], "t7")
+
+	_, err = c.Calculate(0, api.MerchantTypePP)
+	assert.Error(t, err, "t8")
+
+	_, err = c.Calculate(100000, "XX")
+	assert.Error(t, err, "t9")
+
+	c = NewClient(Config{Min: 3, Max: 6, MinPartAmount: 100, Commission: Commission{PP: 1.5}})
+
+	pp, err = c.Calculate(100000, api.MerchantTypePP)
+	assert.NoError(t, err, "t10")
+	assert.Len(t, pp, 4, "t11")
+	assert.Equal(t, 3, pp[0].Parts, "t12")
+	assert.Equal(t, 6, pp[3].Parts, "t13")
+
+	c = NewClient(Config{Max: 2, MinPartAmount: 100, Commission: Commission{PP: 1.5}})
+
+	pp, err = c.Calculate(100000, api.MerchantTypePP)
+	assert.NoError(t, err, "t14")
+	assert.Len(t, pp, 2, "t15")
+	assert.Equal(t, 1, pp[0].Parts, "t16")
+}
@@ -15,6 +15,10 @@ type Config struct {
 	ResponseUrl string
 	RedirectUrl string
 
+	// MinPartAmount is the smallest monthly payment in cents accepted by the bank
+	MinPartAmount int
+	Commission    Commission
+
 	Transport http.RoundTripper
 	BaseUrl   *url.URL
 }
@@ -29,15 +33,26 @@ func NewClient(cfg Config) *Client {
 	acfg := api.NewConfig(cfg.StoreId, cfg.Password, cfg.ResponseUrl, cfg.RedirectUrl)
 
 	return &Client{
-		min: cfg.Min,
-		max: cfg.Max,
-		API: api.New(transport.New(tcfg), acfg),
+		min:           cfg.Min,
+		max:           cfg.Max,
+		minPartAmount: cfg.MinPartAmount,
+		commission:    cfg.Commission,
+		API:           api.New(transport.New(tcfg), acfg),
 	}
 }
 
 type Client struct {
-	min      int
-	max      int
+	min           int
+	max           int
+	minPartAmount int
+	commission    Commission
 
 	*api.API
 }
+
+func (c *Client) GetMin() int {
+	return c.min
+}
+func (c *Client) GetMax() int {
+	return c.max
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/spf13/viper"
 	"github.com/wowucco/G3/internal/checkout"
 	checkoutHttp "github.com/wowucco/G3/internal/checkout/delivery/http"
+	"github.com/wowucco/G3/internal/checkout/promo"
 	"github.com/wowucco/G3/internal/checkout/repository"
+	"github.com/wowucco/G3/internal/checkout/stock"
 	"github.com/wowucco/G3/internal/checkout/strategy"
 	"github.com/wowucco/G3/internal/checkout/usecase"
 	"github.com/wowucco/G3/internal/contact"
@@ -19,6 +21,7 @@ import (
 	contactUC "github.com/wowucco/G3/internal/contact/usecases"
 	"github.com/wowucco/G3/internal/delivery"
 	_deliveryRepo "github.com/wowucco/G3/internal/delivery/repository"
+	"github.com/wowucco/G3/internal/delivery/waybill"
 	"github.com/wowucco/G3/internal/menu"
 	_menuRepo "github.com/wowucco/G3/internal/menu/repository/psql"
 	"github.com/wowucco/G3/internal/product"
@@ -28,15 +31,14 @@ import (
 	"github.com/wowucco/G3/pkg/gqlgen/graph"
 	"github.com/wowucco/G3/pkg/http/middleware"
 	"github.com/wowucco/G3/pkg/notification"
-	"github.com/wowucco/G3/pkg/payments/liqpay"
-	"github.com/wowucco/G3/pkg/payments/privatPay"
+	npDocument "github.com/wowucco/G3/pkg/novaposhta"
 	"github.com/wowucco/G3/pkg/sms"
 	smsMock "github.com/wowucco/G3/pkg/sms/mock"
 	smsClub "github.com/wowucco/G3/pkg/sms/smsclub"
 	telegram2 "github.com/wowucco/G3/pkg/telegram"
-	"github.com/wowucco/go-novaposhta"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"time"
@@ -49,6 +51,8 @@ type App struct {
 	productRead  product.ReadRepository
 	menuRead     menu.ReadRepository
 	deliveryRead delivery.DeliveryReadRepository
+	deliverySync delivery.DeliverySyncRepository
+	streets      delivery.IStreetService
 
 	orderManage checkout.IOrderUseCase
 
@@ -66,11 +70,11 @@ type App struct {
 func NewApp() *App {
 	db := initDB()
 	es := initElasticsearch()
-	np := initNovaposhtaClient()
+	npClient := initNovaposhtaDocumentClient()
 
 	productRepo := _productRepo.NewProductRepository(db)
 	productRead := _productRepo.NewProductReadRepository(db, es)
-	deliveryRead := _deliveryRepo.NewDeliveryReadRepository(db, es, np)
+	deliveryRead := _deliveryRepo.NewDeliveryReadRepository(db, es, initNovaposhtaEstimateService(npClient))
 
 	smsChan := make(chan sms.Message, 1)
 	telegramChan := make(chan telegram2.Message, 1)
@@ -85,12 +89,24 @@ func NewApp() *App {
 		productRead:  productRead,
 		menuRead:     _menuRepo.NewMenuReadRepository(db),
 		deliveryRead: deliveryRead,
+		deliverySync: _deliveryRepo.NewDeliverySyncRepository(es, npClient),
+		streets:      waybill.NewNovaposhtaStreetService(npClient),
 
 		orderManage: usecase.NewOrderUseCase(
 			repository.NewOrderRepository(db),
 			productRead,
 			deliveryRead,
 			repository.NewPaymentRepository(db),
+			repository.NewRefundRepository(db),
+			repository.NewPaymentEventRepository(db),
+			repository.NewInvoiceRepository(db),
+			repository.NewUnitOfWork(db),
+			repository.NewOrderChangeRepository(db),
+			repository.NewDeliverySlotRepository(db),
+			stock.NewStockService(repository.NewStockRepository(db)),
+			promo.NewPromoService(repository.NewPromoRepository(db)),
+			waybill.NewNovaposhtaWaybillService(npClient, waybillSender()),
+			waybill.NewNovaposhtaTrackingService(npClient),
 			notify,
 			initPaymentContext(db),
 		),
@@ -123,7 +139,7 @@ func (app *App) Run(port string) error {
 	checkoutHttp.RegisterHTTPEndpoints(api, app.orderManage, platformAuth)
 	contactHttp.RegisterHTTPEndpoints(api, platformAuth, app.contactManage)
 
-	graph.RegisterGraphql(api, app.productUC, app.productRead, app.menuRead, app.deliveryRead)
+	graph.RegisterGraphql(api, app.productUC, app.productRead, app.menuRead, app.deliveryRead, app.streets, app.orderManage)
 
 	app.httpServer = &http.Server{
 		Addr:    fmt.Sprintf(":%v", port),
@@ -132,6 +148,10 @@ func (app *App) Run(port string) error {
 
 	initSmsListening(app.smsChan)
 	initTelegramListening(app.telegramChan)
+	initPaymentReconciling(app.orderManage)
+	initDeliveryTracking(app.orderManage)
+	initStockReleasing(app.orderManage)
+	initDeliverySyncing(app.deliverySync)
 
 	go func() {
 		if err := app.httpServer.ListenAndServe(); err != nil {
@@ -194,18 +214,47 @@ func initElasticsearch() *elasticsearch.Client {
 	return es
 }
 
-func initNovaposhtaClient() *novaposhta.Client {
-	cfg := novaposhta.Config{
+func initNovaposhtaDocumentClient() *npDocument.Client {
+
+	cfg := npDocument.Config{
 		ApiKey: viper.GetString("novaposhta.api_key"),
 	}
 
-	np, err := novaposhta.NewClient(cfg)
+	// api_url points the client to a stand-in of the api on dev environments
+	if apiUrl := viper.GetString("novaposhta.api_url"); apiUrl != "" {
+		u, err := url.Parse(apiUrl)
 
-	if err != nil {
-		log.Fatalf("Error creating the novaposhta client: %s", err)
+		if err != nil {
+			log.Fatalf("Error parsing the novaposhta api url: %s", err)
+		}
+
+		cfg.BaseUrl = u
+	}
+
+	return npDocument.NewClient(cfg)
+}
+
+func waybillSender() waybill.NovaposhtaSender {
+
+	return waybill.NovaposhtaSender{
+		CityRef:    viper.GetString("novaposhta.sender.city_ref"),
+		Ref:        viper.GetString("novaposhta.sender.ref"),
+		AddressRef: viper.GetString("novaposhta.sender.address_ref"),
+		ContactRef: viper.GetString("novaposhta.sender.contact_ref"),
+		Phone:      viper.GetString("novaposhta.sender.phone"),
+	}
+}
+
+func initNovaposhtaEstimateService(c *npDocument.Client) *waybill.NovaposhtaEstimateService {
+
+	// products have no weight, parcel weight is estimated per item
+	itemWeight := viper.GetFloat64("novaposhta.estimate.item_weight")
+
+	if itemWeight <= 0 {
+		itemWeight = 1
 	}
 
-	return np
+	return waybill.NewNovaposhtaEstimateService(c, viper.GetString("novaposhta.sender.city_ref"), itemWeight)
 }
 
 func initSmsListening(ch <-chan sms.Message) {
@@ -230,6 +279,120 @@ func initSmsListening(ch <-chan sms.Message) {
 	}(c, ch)
 }
 
+func initPaymentReconciling(uc checkout.IOrderUseCase) {
+
+	interval := viper.GetDuration("payments.reconcile.interval")
+
+	if interval <= 0 {
+		return
+	}
+
+	staleAfter := viper.GetDuration("payments.reconcile.stale_after")
+	maxAge := viper.GetDuration("payments.reconcile.max_age")
+	limit := viper.GetInt("payments.reconcile.limit")
+
+	if staleAfter <= 0 {
+		staleAfter = 15 * time.Minute
+	}
+	if maxAge <= 0 {
+		maxAge = 7 * 24 * time.Hour
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	go func(uc checkout.IOrderUseCase) {
+		t := time.NewTicker(interval)
+		for {
+			<-t.C
+			if _, err := uc.ReconcilePayments(context.Background(), staleAfter, maxAge, limit); err != nil {
+				log.Printf("[error][payment reconciling]%v", err)
+			}
+		}
+	}(uc)
+}
+
+func initDeliveryTracking(uc checkout.IOrderUseCase) {
+
+	interval := viper.GetDuration("novaposhta.tracking.interval")
+
+	if interval <= 0 {
+		return
+	}
+
+	maxAge := viper.GetDuration("novaposhta.tracking.max_age")
+	limit := viper.GetInt("novaposhta.tracking.limit")
+
+	if maxAge <= 0 {
+		maxAge = 30 * 24 * time.Hour
+	}
+	if limit <= 0 {
+		limit = 500
+	}
+
+	go func(uc checkout.IOrderUseCase) {
+		t := time.NewTicker(interval)
+		for {
+			<-t.C
+			if _, err := uc.TrackDeliveries(context.Background(), maxAge, limit); err != nil {
+				log.Printf("[error][delivery tracking]%v", err)
+			}
+		}
+	}(uc)
+}
+
+func initStockReleasing(uc checkout.IOrderUseCase) {
+
+	interval := viper.GetDuration("stock.release.interval")
+
+	if interval <= 0 {
+		return
+	}
+
+	// unpaid orders keep the stock for expire_after, payment initialized later reserves it again
+	expireAfter := viper.GetDuration("stock.release.expire_after")
+
+	if expireAfter <= 0 {
+		expireAfter = 24 * time.Hour
+	}
+
+	go func(uc checkout.IOrderUseCase) {
+		t := time.NewTicker(interval)
+		for {
+			<-t.C
+			if _, err := uc.ReleaseExpiredReservations(context.Background(), expireAfter); err != nil {
+				log.Printf("[error][stock releasing]%v", err)
+			}
+		}
+	}(uc)
+}
+
+func initDeliverySyncing(s delivery.DeliverySyncRepository) {
+
+	interval := viper.GetDuration("novaposhta.sync.interval")
+
+	if interval <= 0 {
+		return
+	}
+
+	go func(s delivery.DeliverySyncRepository) {
+		// on_start fills the index on fresh environments, reads are served from it only
+		if viper.GetBool("novaposhta.sync.on_start") {
+			if err := s.SyncNovaposhta(context.Background()); err != nil {
+				log.Printf("[error][delivery sync]%v", err)
+			}
+		}
+
+		t := time.NewTicker(interval)
+		for {
+			<-t.C
+			if err := s.SyncNovaposhta(context.Background()); err != nil {
+				log.Printf("[error][delivery sync]%v", err)
+			}
+		}
+	}(s)
+}
+
 func initTelegramListening(ch <-chan telegram2.Message) {
 
 	var cl telegram2.Client
@@ -279,25 +442,15 @@ func initPaymentContext(db *dbx.DB) *strategy.PaymentContext {
 
 	r := repository.NewPaymentRepository(db)
 
-	lp := liqpay.NewClient(
-		viper.GetString("payments.liqpay.public"),
-		viper.GetString("payments.liqpay.private"),
-		viper.GetString("payments.liqpay.callback_url"),
-		viper.GetString("payments.liqpay.return_url"),
-	)
-
-	ppp := privatPay.NewClient(privatPay.Config{
-		StoreId:     viper.GetString("payments.privat_pay.store_id"),
-		Password:    viper.GetString("payments.privat_pay.passport"),
-		Min:         viper.GetInt("payments.privat_pay.min_parts"),
-		Max:         viper.GetInt("payments.privat_pay.max_parts"),
-		ResponseUrl: viper.GetString("payments.privat_pay.callback_url"),
-		RedirectUrl: viper.GetString("payments.privat_pay.return_url"),
+	registry, err := strategy.NewRegistryFromFactories(strategy.ProviderDeps{
+		Repository:        r,
+		InvoiceRepository: repository.NewInvoiceRepository(db),
+		Config:            viper.GetViper(),
 	})
 
-	p2p := strategy.NewP2PStrategy(r, lp)
-	pp := strategy.NewPartsPayStrategy(r, ppp)
-	d := strategy.NewDefaultStrategy(r)
+	if err != nil {
+		log.Fatalf("Failed to init payment providers: %+v", err)
+	}
 
-	return strategy.NewPaymentContext(p2p, pp, d)
+	return strategy.NewPaymentContext(registry, strategy.NewDefaultStrategy(r))
 }